@@ -1,25 +1,92 @@
 package neutrinoapi
 
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
 type State int8
 
 const (
 	INITIALIZING State = iota
 	PLAYING
 	DONE
+	CANCELLED // Voided by an admin, cancelled games have no result and are never rated
 )
 
+var stateNames = map[State]string{
+	INITIALIZING: "initializing",
+	PLAYING:      "playing",
+	DONE:         "done",
+	CANCELLED:    "cancelled",
+}
+
+func ParseState(name string) (State, error) {
+	for state, stateName := range stateNames {
+		if strings.EqualFold(name, stateName) {
+			return state, nil
+		}
+	}
+	return INITIALIZING, errors.New("Unknown state " + name)
+}
+
 type WinningCondition int8
 
 const (
 	BACK_LINE WinningCondition = iota
 	TRAP
-	DEFAULT
+	DEFAULT // The opponent resigned, or an admin finished the game
+)
+
+type Visibility int8
+
+const (
+	PLAYERS_ONLY Visibility = iota
+	PUBLIC                  // Anyone can watch the game, and it shows up in the live games listing
 )
 
+var visibilityNames = map[Visibility]string{
+	PLAYERS_ONLY: "playersOnly",
+	PUBLIC:       "public",
+}
+
+func ParseVisibility(name string) (Visibility, error) {
+	for visibility, visibilityName := range visibilityNames {
+		if strings.EqualFold(name, visibilityName) {
+			return visibility, nil
+		}
+	}
+	return PLAYERS_ONLY, errors.New("Unknown visibility " + name)
+}
+
 // TODO figure out if these fields should be private or public. I've made GameID public for now to create a test
 type Game struct {
 	GameID, PlayerOneID, PlayerTwoID string
 	State                            State
 	WinningCondition                 WinningCondition
 	SerializedGame                   uint64
+	InviteCode                       string // Only set for private games, which are never matched with random players
+	WinnerID                         string // Empty until the game is DONE, and still empty if it ended in a draw
+	CreatedAt, FinishedAt            time.Time
+	Turns                            int
+	BotPlayed                        bool       // Set when a bot took the place of a human opponent, these games are never rated
+	History                          []uint64   // The serialized game before every turn, so finished games can be reviewed
+	Visibility                       Visibility // Chosen by whoever creates the game
+	Version                          int        // Goes up by one every time the game changes, so clients can tell what they have missed
+
+	// Not stored with the game, but filled in when games are returned to the players
+	PlayerOneRating, PlayerTwoRating int
+}
+
+func (game *Game) isPlayer(userID string) bool {
+	return userID != "" && (game.PlayerOneID == userID || game.PlayerTwoID == userID)
+}
+
+// SpectatorView is a copy of the game without the parts that are only for the players, as the
+// invite code would let a spectator take the open seat of a private game.
+func (game *Game) SpectatorView() *Game {
+	view := *game
+	view.InviteCode = ""
+	return &view
 }
@@ -0,0 +1,35 @@
+package neutrinoapi
+
+import (
+	"github.com/Morras/neutrinoapi/auth"
+	"strings"
+)
+
+// AdminPolicy decides who can use the admin endpoints: users on the allowlist, and users whose
+// token has the admin claim set to true. Identity providers set custom claims for their own users,
+// the allowlist covers the ones that cannot.
+type AdminPolicy struct {
+	claim     string
+	allowlist map[string]bool
+}
+
+func NewAdminPolicy(claim string, allowlist []string) *AdminPolicy {
+	policy := &AdminPolicy{claim: claim, allowlist: map[string]bool{}}
+	for _, userID := range allowlist {
+		if userID = strings.TrimSpace(userID); userID != "" {
+			policy.allowlist[userID] = true
+		}
+	}
+	return policy
+}
+
+func (ap *AdminPolicy) IsAdmin(claims *auth.Claims) bool {
+	if claims.Subject == "" {
+		return false
+	}
+	if ap.allowlist[claims.Subject] {
+		return true
+	}
+	isAdmin, ok := claims.All[ap.claim].(bool)
+	return ok && isAdmin
+}
@@ -0,0 +1,52 @@
+package neutrinoapi
+
+import (
+	"fmt"
+	"net/http"
+)
+
+type AdminFinishGameEndpoint struct {
+	ds        GameDataStore
+	rater     *Rater
+	publisher GameEventPublisher
+	audit     AuditSink
+}
+
+func NewAdminFinishGameEndpoint(ds GameDataStore, rater *Rater, publisher GameEventPublisher, audit AuditSink) *AdminFinishGameEndpoint {
+	return &AdminFinishGameEndpoint{ds: ds, rater: rater, publisher: publisher, audit: audit}
+}
+
+// PerformAction ends a game that is being played with the result the admin decided on, an empty
+// winnerID makes it a draw. The game is rated like any other finished game.
+func (afe *AdminFinishGameEndpoint) PerformAction(requestIDs RequestIDs, adminID string, gameID string, winnerID string) int {
+	dsGame, err := afe.ds.Game(gameID)
+	if err != nil {
+		return http.StatusInternalServerError
+	}
+	if dsGame == nil {
+		return http.StatusNotFound
+	}
+	if dsGame.State != PLAYING {
+		return http.StatusBadRequest
+	}
+	if winnerID != "" && winnerID != dsGame.PlayerOneID && winnerID != dsGame.PlayerTwoID {
+		return http.StatusBadRequest
+	}
+
+	before := auditedGame(dsGame)
+	finishGame(dsGame, winnerID, DEFAULT)
+	dsGame.Version++
+	event := newGameEvent(GAME_FINISHED, adminID, dsGame)
+	if err = afe.ds.UpdateGame(dsGame, event); err != nil {
+		return http.StatusInternalServerError
+	}
+
+	if err = afe.rater.RateGame(dsGame); err != nil {
+		fmt.Printf("Error rating game %v: %v\n", dsGame.GameID, err)
+	}
+	publishGameEvent(afe.publisher, event)
+	recordAudit(afe.audit, requestIDs, &AuditEntry{ActorID: adminID, Action: AUDIT_ADMIN_FINISH_GAME, GameID: gameID,
+		Details: fmt.Sprintf("winner %q", winnerID), Before: before, After: auditedGame(dsGame)})
+
+	return http.StatusOK
+}
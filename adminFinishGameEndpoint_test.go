@@ -0,0 +1,96 @@
+package neutrinoapi_test
+
+import (
+	"errors"
+	api "github.com/Morras/neutrinoapi"
+	"github.com/Morras/neutrinoapi/spy"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+)
+
+var _ = Describe("adminFinishGameEndpoint", func() {
+
+	adminID := "AdminUserId"
+	testRequestID := api.RequestIDs{ID: "TestRequestId"}
+	playerOneID := "PlayerOneId"
+	playerTwoID := "PlayerTwoId"
+	const gameID = "game id"
+
+	var dataStoreSpy *spy.GameDataStoreSpy
+	var ratingDataStoreSpy *spy.RatingDataStoreSpy
+	var publisherSpy *spy.GameEventPublisherSpy
+	var auditSpy *spy.AuditSinkSpy
+	var endpoint *api.AdminFinishGameEndpoint
+
+	BeforeEach(func() {
+		dataStoreSpy = &spy.GameDataStoreSpy{}
+		ratingDataStoreSpy = &spy.RatingDataStoreSpy{}
+		publisherSpy = &spy.GameEventPublisherSpy{}
+		auditSpy = &spy.AuditSinkSpy{}
+		endpoint = api.NewAdminFinishGameEndpoint(dataStoreSpy, api.NewRater(ratingDataStoreSpy, &spy.LeaderboardDataStoreSpy{}), publisherSpy, auditSpy)
+	})
+
+	Context("performAction method", func() {
+		It("Should return not found if the game does not exist", func() {
+			code := endpoint.PerformAction(testRequestID, adminID, gameID, playerOneID)
+			Expect(code).To(BeIdenticalTo(http.StatusNotFound))
+		})
+
+		It("Should only finish games that are being played", func() {
+			dataStoreSpy.GameReturn = &api.Game{GameID: gameID, PlayerOneID: playerOneID, State: api.INITIALIZING}
+			code := endpoint.PerformAction(testRequestID, adminID, gameID, playerOneID)
+			Expect(code).To(BeIdenticalTo(http.StatusBadRequest))
+			Expect(dataStoreSpy.UpdateGameGame).To(BeNil())
+		})
+
+		Context("Given the game is being played", func() {
+			BeforeEach(func() {
+				dataStoreSpy.GameReturn = &api.Game{GameID: gameID, PlayerOneID: playerOneID, PlayerTwoID: playerTwoID, State: api.PLAYING, Version: 3}
+			})
+
+			It("Should finish the game with the chosen winner", func() {
+				code := endpoint.PerformAction(testRequestID, adminID, gameID, playerTwoID)
+				Expect(code).To(BeIdenticalTo(http.StatusOK))
+				saved := dataStoreSpy.UpdateGameGame
+				Expect(saved.State).To(BeIdenticalTo(api.DONE))
+				Expect(saved.WinnerID).To(BeIdenticalTo(playerTwoID))
+				Expect(saved.WinningCondition).To(BeIdenticalTo(api.DEFAULT))
+				Expect(saved.Version).To(BeIdenticalTo(4))
+			})
+
+			It("Should make it a draw when there is no winner", func() {
+				endpoint.PerformAction(testRequestID, adminID, gameID, "")
+				Expect(dataStoreSpy.UpdateGameGame.State).To(BeIdenticalTo(api.DONE))
+				Expect(dataStoreSpy.UpdateGameGame.WinnerID).To(BeEmpty())
+			})
+
+			It("Should not let someone outside the game win it", func() {
+				code := endpoint.PerformAction(testRequestID, adminID, gameID, adminID)
+				Expect(code).To(BeIdenticalTo(http.StatusBadRequest))
+				Expect(dataStoreSpy.UpdateGameGame).To(BeNil())
+			})
+
+			It("Should rate the game, publish it and record it in the audit log", func() {
+				endpoint.PerformAction(testRequestID, adminID, gameID, playerOneID)
+				Expect(len(ratingDataStoreSpy.UpdateRatingsChanges)).To(BeIdenticalTo(2))
+				Expect(publisherSpy.PublishEvents[0].Type).To(BeIdenticalTo(api.GAME_FINISHED))
+				Expect(publisherSpy.PublishEvents[0].UserID).To(BeIdenticalTo(adminID))
+				entry := auditSpy.RecordEntries[0]
+				Expect(entry.Action).To(BeIdenticalTo(api.AUDIT_ADMIN_FINISH_GAME))
+				Expect(entry.Details).To(ContainSubstring(playerOneID))
+				Expect(entry.RequestID).To(BeIdenticalTo(testRequestID.ID))
+				Expect(entry.Before.State).To(BeIdenticalTo(api.PLAYING))
+				Expect(entry.After.State).To(BeIdenticalTo(api.DONE))
+				Expect(entry.After.WinnerID).To(BeIdenticalTo(playerOneID))
+			})
+
+			It("Should return an internal server error without recording anything if the game cannot be saved", func() {
+				dataStoreSpy.UpdateGameErr = errors.New("error updating game")
+				code := endpoint.PerformAction(testRequestID, adminID, gameID, playerOneID)
+				Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
+				Expect(auditSpy.RecordEntries).To(BeEmpty())
+			})
+		})
+	})
+})
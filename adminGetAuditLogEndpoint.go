@@ -0,0 +1,32 @@
+package neutrinoapi
+
+import (
+	"fmt"
+	"net/http"
+)
+
+type AdminGetAuditLogEndpoint struct {
+	ds    AuditDataStore
+	audit AuditSink
+}
+
+func NewAdminGetAuditLogEndpoint(ds AuditDataStore, audit AuditSink) *AdminGetAuditLogEndpoint {
+	return &AdminGetAuditLogEndpoint{ds: ds, audit: audit}
+}
+
+// PerformAction returns a page of the audit log, newest entries first, optionally only for one game
+// or one user. Looking at the audit log ends up in the audit log as well.
+func (agl *AdminGetAuditLogEndpoint) PerformAction(requestIDs RequestIDs, adminID string, gameID string, actorID string, page int) ([]*AuditEntry, int) {
+	if page < 0 {
+		return nil, http.StatusBadRequest
+	}
+
+	entries, err := agl.ds.AuditEntries(gameID, actorID, page*AUDIT_LOG_PAGE_SIZE, AUDIT_LOG_PAGE_SIZE)
+	if err != nil {
+		return nil, http.StatusInternalServerError
+	}
+
+	recordAudit(agl.audit, requestIDs, &AuditEntry{ActorID: adminID, Action: AUDIT_ADMIN_GET_AUDIT_LOG, GameID: gameID,
+		Details: fmt.Sprintf("actor %q, page %v", actorID, page)})
+	return entries, http.StatusOK
+}
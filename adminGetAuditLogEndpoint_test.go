@@ -0,0 +1,62 @@
+package neutrinoapi_test
+
+import (
+	"errors"
+	api "github.com/Morras/neutrinoapi"
+	"github.com/Morras/neutrinoapi/spy"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+)
+
+var _ = Describe("adminGetAuditLogEndpoint", func() {
+
+	adminID := "AdminUserId"
+	testRequestID := api.RequestIDs{ID: "TestRequestId"}
+
+	var dataStoreSpy *spy.AuditDataStoreSpy
+	var auditSpy *spy.AuditSinkSpy
+	var endpoint *api.AdminGetAuditLogEndpoint
+
+	BeforeEach(func() {
+		dataStoreSpy = &spy.AuditDataStoreSpy{}
+		auditSpy = &spy.AuditSinkSpy{}
+		endpoint = api.NewAdminGetAuditLogEndpoint(dataStoreSpy, auditSpy)
+	})
+
+	Context("performAction method", func() {
+		It("Should return the requested page of entries for the game and user", func() {
+			entries := []*api.AuditEntry{{ActorID: "TestUserId", Action: api.AUDIT_MAKE_MOVE}}
+			dataStoreSpy.AuditEntriesReturn = entries
+			returned, code := endpoint.PerformAction(testRequestID, adminID, "game id", "TestUserId", 1)
+			Expect(code).To(BeIdenticalTo(http.StatusOK))
+			Expect(returned).To(Equal(entries))
+			Expect(dataStoreSpy.AuditEntriesGameID).To(BeIdenticalTo("game id"))
+			Expect(dataStoreSpy.AuditEntriesActorID).To(BeIdenticalTo("TestUserId"))
+			Expect(dataStoreSpy.AuditEntriesOffset).To(BeIdenticalTo(api.AUDIT_LOG_PAGE_SIZE))
+			Expect(dataStoreSpy.AuditEntriesCount).To(BeIdenticalTo(api.AUDIT_LOG_PAGE_SIZE))
+		})
+
+		It("Should record that the admin looked at the audit log", func() {
+			endpoint.PerformAction(testRequestID, adminID, "game id", "", 0)
+			Expect(len(auditSpy.RecordEntries)).To(BeIdenticalTo(1))
+			entry := auditSpy.RecordEntries[0]
+			Expect(entry.ActorID).To(BeIdenticalTo(adminID))
+			Expect(entry.Action).To(BeIdenticalTo(api.AUDIT_ADMIN_GET_AUDIT_LOG))
+			Expect(entry.RequestID).To(BeIdenticalTo(testRequestID.ID))
+			Expect(entry.GameID).To(BeIdenticalTo("game id"))
+		})
+
+		It("Should reject negative pages", func() {
+			_, code := endpoint.PerformAction(testRequestID, adminID, "", "", -1)
+			Expect(code).To(BeIdenticalTo(http.StatusBadRequest))
+		})
+
+		It("Should return an internal server error if the entries could not be fetched", func() {
+			dataStoreSpy.AuditEntriesErr = errors.New("error getting entries")
+			_, code := endpoint.PerformAction(testRequestID, adminID, "", "", 0)
+			Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
+			Expect(auditSpy.RecordEntries).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,26 @@
+package neutrinoapi
+
+import "net/http"
+
+type AdminGetGameEndpoint struct {
+	ds    GameDataStore
+	audit AuditSink
+}
+
+func NewAdminGetGameEndpoint(ds GameDataStore, audit AuditSink) *AdminGetGameEndpoint {
+	return &AdminGetGameEndpoint{ds: ds, audit: audit}
+}
+
+// PerformAction returns any game, whoever is playing it and whatever its visibility.
+func (age *AdminGetGameEndpoint) PerformAction(requestIDs RequestIDs, adminID string, gameID string) (*Game, int) {
+	game, err := age.ds.Game(gameID)
+	if err != nil {
+		return nil, http.StatusInternalServerError
+	}
+	if game == nil {
+		return nil, http.StatusNotFound
+	}
+
+	recordAudit(age.audit, requestIDs, &AuditEntry{ActorID: adminID, Action: AUDIT_ADMIN_GET_GAME, GameID: gameID})
+	return game, http.StatusOK
+}
@@ -0,0 +1,65 @@
+package neutrinoapi_test
+
+import (
+	"errors"
+	api "github.com/Morras/neutrinoapi"
+	"github.com/Morras/neutrinoapi/spy"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+)
+
+var _ = Describe("adminGetGameEndpoint", func() {
+
+	adminID := "AdminUserId"
+	testRequestID := api.RequestIDs{ID: "TestRequestId"}
+	const gameID = "game id"
+
+	var dataStoreSpy *spy.GameDataStoreSpy
+	var auditSpy *spy.AuditSinkSpy
+	var endpoint *api.AdminGetGameEndpoint
+
+	BeforeEach(func() {
+		dataStoreSpy = &spy.GameDataStoreSpy{}
+		auditSpy = &spy.AuditSinkSpy{}
+		endpoint = api.NewAdminGetGameEndpoint(dataStoreSpy, auditSpy)
+	})
+
+	Context("performAction method", func() {
+		It("Should return a game the admin is not playing in", func() {
+			game := &api.Game{GameID: gameID, PlayerOneID: "someone", PlayerTwoID: "someone else"}
+			dataStoreSpy.GameReturn = game
+			returned, code := endpoint.PerformAction(testRequestID, adminID, gameID)
+			Expect(code).To(BeIdenticalTo(http.StatusOK))
+			Expect(returned).To(BeIdenticalTo(game))
+		})
+
+		It("Should record that the admin looked at the game", func() {
+			dataStoreSpy.GameReturn = &api.Game{GameID: gameID}
+			endpoint.PerformAction(testRequestID, adminID, gameID)
+			Expect(len(auditSpy.RecordEntries)).To(BeIdenticalTo(1))
+			entry := auditSpy.RecordEntries[0]
+			Expect(entry.ActorID).To(BeIdenticalTo(adminID))
+			Expect(entry.Action).To(BeIdenticalTo(api.AUDIT_ADMIN_GET_GAME))
+			Expect(entry.GameID).To(BeIdenticalTo(gameID))
+		})
+
+		It("Should return not found if the game does not exist", func() {
+			_, code := endpoint.PerformAction(testRequestID, adminID, gameID)
+			Expect(code).To(BeIdenticalTo(http.StatusNotFound))
+		})
+
+		It("Should return an internal server error if the game could not be fetched", func() {
+			dataStoreSpy.GameErr = errors.New("error getting game")
+			_, code := endpoint.PerformAction(testRequestID, adminID, gameID)
+			Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
+		})
+
+		It("Should still return the game if the audit log could not be written", func() {
+			dataStoreSpy.GameReturn = &api.Game{GameID: gameID}
+			auditSpy.RecordErr = errors.New("error recording")
+			_, code := endpoint.PerformAction(testRequestID, adminID, gameID)
+			Expect(code).To(BeIdenticalTo(http.StatusOK))
+		})
+	})
+})
@@ -0,0 +1,30 @@
+package neutrinoapi
+
+import (
+	"fmt"
+	"net/http"
+)
+
+type AdminListGamesEndpoint struct {
+	ds    GameDataStore
+	audit AuditSink
+}
+
+func NewAdminListGamesEndpoint(ds GameDataStore, audit AuditSink) *AdminListGamesEndpoint {
+	return &AdminListGamesEndpoint{ds: ds, audit: audit}
+}
+
+func (ale *AdminListGamesEndpoint) PerformAction(requestIDs RequestIDs, adminID string, state State, page int) ([]*Game, int) {
+	if page < 0 {
+		return nil, http.StatusBadRequest
+	}
+
+	games, err := ale.ds.GamesByState(state, page*ADMIN_GAMES_PAGE_SIZE, ADMIN_GAMES_PAGE_SIZE)
+	if err != nil {
+		return nil, http.StatusInternalServerError
+	}
+
+	recordAudit(ale.audit, requestIDs, &AuditEntry{ActorID: adminID, Action: AUDIT_ADMIN_LIST_GAMES,
+		Details: fmt.Sprintf("state %v, page %v", stateNames[state], page)})
+	return games, http.StatusOK
+}
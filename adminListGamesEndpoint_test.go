@@ -0,0 +1,56 @@
+package neutrinoapi_test
+
+import (
+	"errors"
+	api "github.com/Morras/neutrinoapi"
+	"github.com/Morras/neutrinoapi/spy"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+)
+
+var _ = Describe("adminListGamesEndpoint", func() {
+
+	adminID := "AdminUserId"
+	testRequestID := api.RequestIDs{ID: "TestRequestId"}
+
+	var dataStoreSpy *spy.GameDataStoreSpy
+	var auditSpy *spy.AuditSinkSpy
+	var endpoint *api.AdminListGamesEndpoint
+
+	BeforeEach(func() {
+		dataStoreSpy = &spy.GameDataStoreSpy{}
+		auditSpy = &spy.AuditSinkSpy{}
+		endpoint = api.NewAdminListGamesEndpoint(dataStoreSpy, auditSpy)
+	})
+
+	Context("performAction method", func() {
+		It("Should return the requested page of games in the state", func() {
+			games := []*api.Game{{GameID: "game id"}}
+			dataStoreSpy.GamesByStateReturn = games
+			returned, code := endpoint.PerformAction(testRequestID, adminID, api.PLAYING, 2)
+			Expect(code).To(BeIdenticalTo(http.StatusOK))
+			Expect(returned).To(Equal(games))
+			Expect(dataStoreSpy.GamesByStateState).To(BeIdenticalTo(api.PLAYING))
+			Expect(dataStoreSpy.GamesByStateOffset).To(BeIdenticalTo(2 * api.ADMIN_GAMES_PAGE_SIZE))
+			Expect(dataStoreSpy.GamesByStateCount).To(BeIdenticalTo(api.ADMIN_GAMES_PAGE_SIZE))
+		})
+
+		It("Should record the listing in the audit log", func() {
+			endpoint.PerformAction(testRequestID, adminID, api.DONE, 0)
+			Expect(auditSpy.RecordEntries[0].Action).To(BeIdenticalTo(api.AUDIT_ADMIN_LIST_GAMES))
+			Expect(auditSpy.RecordEntries[0].Details).To(ContainSubstring("done"))
+		})
+
+		It("Should reject negative pages", func() {
+			_, code := endpoint.PerformAction(testRequestID, adminID, api.PLAYING, -1)
+			Expect(code).To(BeIdenticalTo(http.StatusBadRequest))
+		})
+
+		It("Should return an internal server error if the games could not be fetched", func() {
+			dataStoreSpy.GamesByStateErr = errors.New("error getting games")
+			_, code := endpoint.PerformAction(testRequestID, adminID, api.PLAYING, 0)
+			Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
+		})
+	})
+})
@@ -0,0 +1,72 @@
+package neutrinoapi
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type AdminReassignPlayerEndpoint struct {
+	ds        GameDataStore
+	publisher GameEventPublisher
+	audit     AuditSink
+}
+
+func NewAdminReassignPlayerEndpoint(ds GameDataStore, publisher GameEventPublisher, audit AuditSink) *AdminReassignPlayerEndpoint {
+	return &AdminReassignPlayerEndpoint{ds: ds, publisher: publisher, audit: audit}
+}
+
+// PerformAction hands a players seat in a game that has not finished to another user, for instance
+// when someone lost access to their account mid game. Bots only get games through the bot
+// endpoints, and the new user has to have room for another game like any player joining one.
+func (are *AdminReassignPlayerEndpoint) PerformAction(requestIDs RequestIDs, adminID string, gameID string, fromUserID string, toUserID string) int {
+	if fromUserID == "" || toUserID == "" {
+		return http.StatusBadRequest
+	}
+	if strings.HasPrefix(toUserID, BOT_USER_ID_PREFIX) {
+		return http.StatusBadRequest
+	}
+
+	dsGame, err := are.ds.Game(gameID)
+	if err != nil {
+		return http.StatusInternalServerError
+	}
+	if dsGame == nil {
+		return http.StatusNotFound
+	}
+	if dsGame.State != INITIALIZING && dsGame.State != PLAYING {
+		return http.StatusBadRequest
+	}
+
+	before := auditedGame(dsGame)
+	// Nobody can end up playing against themselves
+	switch fromUserID {
+	case dsGame.PlayerOneID:
+		if toUserID == dsGame.PlayerTwoID {
+			return http.StatusBadRequest
+		}
+		dsGame.PlayerOneID = toUserID
+	case dsGame.PlayerTwoID:
+		if toUserID == dsGame.PlayerOneID {
+			return http.StatusBadRequest
+		}
+		dsGame.PlayerTwoID = toUserID
+	default:
+		return http.StatusBadRequest
+	}
+	if eligible, statusCode := isEligibleForNewGame(are.ds, toUserID); !eligible {
+		return statusCode
+	}
+
+	dsGame.Version++
+	event := newGameEvent(PLAYER_REASSIGNED, adminID, dsGame)
+	if err = are.ds.UpdateGame(dsGame, event); err != nil {
+		return http.StatusInternalServerError
+	}
+
+	publishGameEvent(are.publisher, event)
+	recordAudit(are.audit, requestIDs, &AuditEntry{ActorID: adminID, Action: AUDIT_ADMIN_REASSIGN_PLAYER, GameID: gameID,
+		Details: fmt.Sprintf("from %q to %q", fromUserID, toUserID), Before: before, After: auditedGame(dsGame)})
+
+	return http.StatusOK
+}
@@ -0,0 +1,120 @@
+package neutrinoapi_test
+
+import (
+	"errors"
+	api "github.com/Morras/neutrinoapi"
+	"github.com/Morras/neutrinoapi/spy"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+)
+
+var _ = Describe("adminReassignPlayerEndpoint", func() {
+
+	adminID := "AdminUserId"
+	testRequestID := api.RequestIDs{ID: "TestRequestId"}
+	playerOneID := "PlayerOneId"
+	playerTwoID := "PlayerTwoId"
+	newPlayerID := "NewPlayerId"
+	const gameID = "game id"
+
+	var dataStoreSpy *spy.GameDataStoreSpy
+	var publisherSpy *spy.GameEventPublisherSpy
+	var auditSpy *spy.AuditSinkSpy
+	var endpoint *api.AdminReassignPlayerEndpoint
+
+	BeforeEach(func() {
+		dataStoreSpy = &spy.GameDataStoreSpy{}
+		publisherSpy = &spy.GameEventPublisherSpy{}
+		auditSpy = &spy.AuditSinkSpy{}
+		endpoint = api.NewAdminReassignPlayerEndpoint(dataStoreSpy, publisherSpy, auditSpy)
+		dataStoreSpy.GameReturn = &api.Game{GameID: gameID, PlayerOneID: playerOneID, PlayerTwoID: playerTwoID, State: api.PLAYING, Version: 5}
+	})
+
+	Context("performAction method", func() {
+		It("Should give the seat of either player to the new user", func() {
+			code := endpoint.PerformAction(testRequestID, adminID, gameID, playerTwoID, newPlayerID)
+			Expect(code).To(BeIdenticalTo(http.StatusOK))
+			saved := dataStoreSpy.UpdateGameGame
+			Expect(saved.PlayerOneID).To(BeIdenticalTo(playerOneID))
+			Expect(saved.PlayerTwoID).To(BeIdenticalTo(newPlayerID))
+			Expect(saved.Version).To(BeIdenticalTo(6))
+
+			endpoint.PerformAction(testRequestID, adminID, gameID, playerOneID, "AnotherPlayerId")
+			Expect(dataStoreSpy.UpdateGameGame.PlayerOneID).To(BeIdenticalTo("AnotherPlayerId"))
+		})
+
+		It("Should save and publish an event about the reassignment", func() {
+			endpoint.PerformAction(testRequestID, adminID, gameID, playerTwoID, newPlayerID)
+			Expect(len(dataStoreSpy.UpdateGameEvents)).To(BeIdenticalTo(1))
+			Expect(publisherSpy.PublishEvents).To(Equal(dataStoreSpy.UpdateGameEvents))
+
+			event := publisherSpy.PublishEvents[0]
+			Expect(event.Type).To(BeIdenticalTo(api.PLAYER_REASSIGNED))
+			Expect(event.UserID).To(BeIdenticalTo(adminID))
+			Expect(event.Game.PlayerTwoID).To(BeIdenticalTo(newPlayerID))
+		})
+
+		It("Should record the reassignment in the audit log", func() {
+			endpoint.PerformAction(testRequestID, adminID, gameID, playerTwoID, newPlayerID)
+			entry := auditSpy.RecordEntries[0]
+			Expect(entry.Action).To(BeIdenticalTo(api.AUDIT_ADMIN_REASSIGN_PLAYER))
+			Expect(entry.Details).To(ContainSubstring(playerTwoID))
+			Expect(entry.Details).To(ContainSubstring(newPlayerID))
+			Expect(entry.Before.PlayerTwoID).To(BeIdenticalTo(playerTwoID))
+			Expect(entry.After.PlayerTwoID).To(BeIdenticalTo(newPlayerID))
+		})
+
+		It("Should reject users that are not in the game", func() {
+			code := endpoint.PerformAction(testRequestID, adminID, gameID, "someone", newPlayerID)
+			Expect(code).To(BeIdenticalTo(http.StatusBadRequest))
+			Expect(dataStoreSpy.UpdateGameGame).To(BeNil())
+		})
+
+		It("Should not let a player end up playing against themselves", func() {
+			code := endpoint.PerformAction(testRequestID, adminID, gameID, playerOneID, playerTwoID)
+			Expect(code).To(BeIdenticalTo(http.StatusBadRequest))
+			Expect(dataStoreSpy.UpdateGameGame).To(BeNil())
+		})
+
+		It("Should not give seats to bots", func() {
+			code := endpoint.PerformAction(testRequestID, adminID, gameID, playerTwoID, api.BotUserID(api.HARD))
+			Expect(code).To(BeIdenticalTo(http.StatusBadRequest))
+			Expect(dataStoreSpy.UpdateGameGame).To(BeNil())
+		})
+
+		It("Should not give seats to users that already play as many games as they may", func() {
+			dataStoreSpy.NumberOfActiveGamesReturn = api.MAX_ACTIVE_GAMES
+			code := endpoint.PerformAction(testRequestID, adminID, gameID, playerTwoID, newPlayerID)
+			Expect(code).To(BeIdenticalTo(http.StatusBadRequest))
+			Expect(dataStoreSpy.NumberOfActiveGamesUserID).To(BeIdenticalTo(newPlayerID))
+			Expect(dataStoreSpy.UpdateGameGame).To(BeNil())
+			Expect(publisherSpy.PublishEvents).To(BeEmpty())
+		})
+
+		It("Should reject missing users", func() {
+			Expect(endpoint.PerformAction(testRequestID, adminID, gameID, "", newPlayerID)).To(BeIdenticalTo(http.StatusBadRequest))
+			Expect(endpoint.PerformAction(testRequestID, adminID, gameID, playerOneID, "")).To(BeIdenticalTo(http.StatusBadRequest))
+		})
+
+		It("Should not change finished games", func() {
+			dataStoreSpy.GameReturn.State = api.DONE
+			code := endpoint.PerformAction(testRequestID, adminID, gameID, playerTwoID, newPlayerID)
+			Expect(code).To(BeIdenticalTo(http.StatusBadRequest))
+		})
+
+		It("Should return not found if the game does not exist", func() {
+			dataStoreSpy.GameReturn = nil
+			code := endpoint.PerformAction(testRequestID, adminID, gameID, playerTwoID, newPlayerID)
+			Expect(code).To(BeIdenticalTo(http.StatusNotFound))
+		})
+
+		It("Should return an internal server error if the game cannot be saved", func() {
+			dataStoreSpy.UpdateGameErr = errors.New("error updating game")
+			code := endpoint.PerformAction(testRequestID, adminID, gameID, playerTwoID, newPlayerID)
+			Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
+			Expect(auditSpy.RecordEntries).To(BeEmpty())
+			Expect(publisherSpy.PublishEvents).To(BeEmpty())
+		})
+	})
+})
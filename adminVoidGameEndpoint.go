@@ -0,0 +1,42 @@
+package neutrinoapi
+
+import "net/http"
+
+type AdminVoidGameEndpoint struct {
+	ds        GameDataStore
+	publisher GameEventPublisher
+	audit     AuditSink
+}
+
+func NewAdminVoidGameEndpoint(ds GameDataStore, publisher GameEventPublisher, audit AuditSink) *AdminVoidGameEndpoint {
+	return &AdminVoidGameEndpoint{ds: ds, publisher: publisher, audit: audit}
+}
+
+// PerformAction cancels a game that has not finished, so it ends without a result or any rating.
+// Finished games cannot be voided as their ratings have already been handed out.
+func (ave *AdminVoidGameEndpoint) PerformAction(requestIDs RequestIDs, adminID string, gameID string) int {
+	dsGame, err := ave.ds.Game(gameID)
+	if err != nil {
+		return http.StatusInternalServerError
+	}
+	if dsGame == nil {
+		return http.StatusNotFound
+	}
+	if dsGame.State != INITIALIZING && dsGame.State != PLAYING {
+		return http.StatusBadRequest
+	}
+
+	before := auditedGame(dsGame)
+	dsGame.State = CANCELLED
+	dsGame.Version++
+	event := newGameEvent(GAME_CANCELLED, adminID, dsGame)
+	if err = ave.ds.UpdateGame(dsGame, event); err != nil {
+		return http.StatusInternalServerError
+	}
+
+	publishGameEvent(ave.publisher, event)
+	recordAudit(ave.audit, requestIDs, &AuditEntry{ActorID: adminID, Action: AUDIT_ADMIN_VOID_GAME, GameID: gameID,
+		Before: before, After: auditedGame(dsGame)})
+
+	return http.StatusOK
+}
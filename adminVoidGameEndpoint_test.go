@@ -0,0 +1,68 @@
+package neutrinoapi_test
+
+import (
+	"errors"
+	api "github.com/Morras/neutrinoapi"
+	"github.com/Morras/neutrinoapi/spy"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+)
+
+var _ = Describe("adminVoidGameEndpoint", func() {
+
+	adminID := "AdminUserId"
+	testRequestID := api.RequestIDs{ID: "TestRequestId"}
+	const gameID = "game id"
+
+	var dataStoreSpy *spy.GameDataStoreSpy
+	var publisherSpy *spy.GameEventPublisherSpy
+	var auditSpy *spy.AuditSinkSpy
+	var endpoint *api.AdminVoidGameEndpoint
+
+	BeforeEach(func() {
+		dataStoreSpy = &spy.GameDataStoreSpy{}
+		publisherSpy = &spy.GameEventPublisherSpy{}
+		auditSpy = &spy.AuditSinkSpy{}
+		endpoint = api.NewAdminVoidGameEndpoint(dataStoreSpy, publisherSpy, auditSpy)
+	})
+
+	Context("performAction method", func() {
+		It("Should return not found if the game does not exist", func() {
+			code := endpoint.PerformAction(testRequestID, adminID, gameID)
+			Expect(code).To(BeIdenticalTo(http.StatusNotFound))
+		})
+
+		It("Should cancel games that have not finished", func() {
+			for _, state := range []api.State{api.INITIALIZING, api.PLAYING} {
+				dataStoreSpy.GameReturn = &api.Game{GameID: gameID, State: state}
+				code := endpoint.PerformAction(testRequestID, adminID, gameID)
+				Expect(code).To(BeIdenticalTo(http.StatusOK))
+				Expect(dataStoreSpy.UpdateGameGame.State).To(BeIdenticalTo(api.CANCELLED))
+			}
+		})
+
+		It("Should not void games that already have a result", func() {
+			dataStoreSpy.GameReturn = &api.Game{GameID: gameID, State: api.DONE}
+			code := endpoint.PerformAction(testRequestID, adminID, gameID)
+			Expect(code).To(BeIdenticalTo(http.StatusBadRequest))
+			Expect(dataStoreSpy.UpdateGameGame).To(BeNil())
+		})
+
+		It("Should publish the cancellation and record it in the audit log", func() {
+			dataStoreSpy.GameReturn = &api.Game{GameID: gameID, State: api.PLAYING}
+			endpoint.PerformAction(testRequestID, adminID, gameID)
+			Expect(publisherSpy.PublishEvents[0].Type).To(BeIdenticalTo(api.GAME_CANCELLED))
+			Expect(auditSpy.RecordEntries[0].Action).To(BeIdenticalTo(api.AUDIT_ADMIN_VOID_GAME))
+			Expect(auditSpy.RecordEntries[0].GameID).To(BeIdenticalTo(gameID))
+		})
+
+		It("Should return an internal server error if the game cannot be saved", func() {
+			dataStoreSpy.GameReturn = &api.Game{GameID: gameID, State: api.PLAYING}
+			dataStoreSpy.UpdateGameErr = errors.New("error updating game")
+			code := endpoint.PerformAction(testRequestID, adminID, gameID)
+			Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
+			Expect(publisherSpy.PublishEvents).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,47 @@
+package neutrinoapi_test
+
+import (
+	api "github.com/Morras/neutrinoapi"
+	"github.com/Morras/neutrinoapi/auth"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AdminPolicy", func() {
+
+	policy := api.NewAdminPolicy(api.ADMIN_CLAIM, []string{"allowed admin", " ", ""})
+
+	It("Should make users on the allowlist admins", func() {
+		Expect(policy.IsAdmin(&auth.Claims{Subject: "allowed admin"})).To(BeTrue())
+	})
+
+	It("Should make users with the admin claim admins", func() {
+		claims := &auth.Claims{Subject: "TestUserId", All: map[string]interface{}{api.ADMIN_CLAIM: true}}
+		Expect(policy.IsAdmin(claims)).To(BeTrue())
+	})
+
+	It("Should not make anyone else admins", func() {
+		for _, claims := range []*auth.Claims{
+			{Subject: "TestUserId"},
+			{Subject: "TestUserId", All: map[string]interface{}{api.ADMIN_CLAIM: false}},
+			{Subject: "TestUserId", All: map[string]interface{}{api.ADMIN_CLAIM: "true"}},
+			{Subject: "", All: map[string]interface{}{api.ADMIN_CLAIM: true}},
+			{Subject: " "},
+		} {
+			Expect(policy.IsAdmin(claims)).To(BeFalse())
+		}
+	})
+})
+
+var _ = Describe("ParseState", func() {
+	It("Should parse the state names regardless of case", func() {
+		state, err := api.ParseState("Playing")
+		Expect(err).To(BeNil())
+		Expect(state).To(BeIdenticalTo(api.PLAYING))
+	})
+
+	It("Should return an error for unknown states", func() {
+		_, err := api.ParseState("paused")
+		Expect(err).ToNot(BeNil())
+	})
+})
@@ -0,0 +1,25 @@
+package neutrinoapi
+
+import "github.com/Morras/go-neutrino/game"
+
+// Analysis is what a search found in a position, seen from the player to move.
+type Analysis struct {
+	// The neutrino fields are left empty on the first turn of the game, where only a piece is moved,
+	// and the piece fields are left empty if the neutrino move wins right away.
+	BestTurn         *MakeMoveRequest
+	Score            int
+	ForcedWin        bool             // The player to move wins within Plies turns whatever the opponent does
+	ForcedLoss       bool             // The opponent wins within Plies turns whatever the player to move does
+	WinningCondition WinningCondition // Only meaningful for forced wins and losses
+	Plies            int
+	// Depth is how many turns ahead the search got before it ran out of nodes. A search that stops
+	// short of the turns asked for can only rule out forced wins and losses within Depth turns.
+	Depth    int
+	Complete bool // Filled in by the analyze endpoint, false if Depth fell short without a forced result
+}
+
+// Analyzer evaluates positions for players reviewing their games.
+type Analyzer interface {
+	// Analyze searches plies turns ahead from the position in the game.
+	Analyze(g *game.Game, plies int) (*Analysis, error)
+}
@@ -0,0 +1,108 @@
+package neutrinoapi
+
+import (
+	"github.com/Morras/go-neutrino/game"
+	"net/http"
+)
+
+// AnalyzeRequest either points at a position in a finished game, or carries the position itself
+// in SerializedGame when GameID is empty. A position that is on the board of one of the players
+// live games is refused either way.
+type AnalyzeRequest struct {
+	GameID         string
+	Turn           int // Index into the game history, a negative turn means the last position before the game ended
+	SerializedGame uint64
+	Plies          int // Zero means ANALYSIS_DEFAULT_PLIES
+}
+
+type AnalyzeEndpoint struct {
+	ds       GameDataStore
+	analyzer Analyzer
+}
+
+func NewAnalyzeEndpoint(ds GameDataStore, analyzer Analyzer) *AnalyzeEndpoint {
+	return &AnalyzeEndpoint{ds: ds, analyzer: analyzer}
+}
+
+func (ae *AnalyzeEndpoint) PerformAction(userID string, analyzeReq *AnalyzeRequest) (*Analysis, int) {
+	plies := analyzeReq.Plies
+	if plies == 0 {
+		plies = ANALYSIS_DEFAULT_PLIES
+	}
+	// The search grows very quickly with the number of plies, so there has to be a limit
+	if plies < 0 || plies > ANALYSIS_MAX_PLIES {
+		return nil, http.StatusBadRequest
+	}
+
+	serializedGame := analyzeReq.SerializedGame
+	var statusCode int
+	if analyzeReq.GameID != "" {
+		serializedGame, statusCode = ae.positionFromGame(userID, analyzeReq.GameID, analyzeReq.Turn)
+	} else {
+		statusCode = ae.checkNotInActiveGame(userID, serializedGame)
+	}
+	if statusCode != http.StatusOK {
+		return nil, statusCode
+	}
+
+	position := game.UInt64ToGame(serializedGame)
+	if isGameOver(position.State) {
+		return nil, http.StatusBadRequest
+	}
+
+	analysis, err := ae.analyzer.Analyze(position, plies)
+	if err != nil {
+		return nil, http.StatusBadRequest
+	}
+	// Looking deeper does not change a forced result, so the search stops once it finds one
+	analysis.Complete = analysis.Depth >= plies || analysis.ForcedWin || analysis.ForcedLoss
+	return analysis, http.StatusOK
+}
+
+// Copying the board of a live game into the request would get around not analysing games that are
+// still going. Whose turn it is does not matter, as either way the analysis would help the player.
+// This only catches the position as it is right now, not one the player has played ahead from.
+func (ae *AnalyzeEndpoint) checkNotInActiveGame(userID string, serializedGame uint64) int {
+	activeGames, err := ae.ds.ActiveGames(userID)
+	if err != nil {
+		return http.StatusInternalServerError
+	}
+	board := boardOnly(serializedGame)
+	for _, activeGame := range activeGames {
+		if activeGame.State == PLAYING && boardOnly(activeGame.SerializedGame) == board {
+			return http.StatusForbidden
+		}
+	}
+	return http.StatusOK
+}
+
+func boardOnly(serializedGame uint64) uint64 {
+	position := game.UInt64ToGame(serializedGame)
+	position.State = game.Player1NeutrinoMove
+	return game.GameToUInt64(position)
+}
+
+func (ae *AnalyzeEndpoint) positionFromGame(userID string, gameID string, turn int) (uint64, int) {
+	dsGame, err := ae.ds.Game(gameID)
+	if err != nil {
+		return 0, http.StatusInternalServerError
+	}
+	if dsGame == nil {
+		return 0, http.StatusNotFound
+	}
+	if dsGame.PlayerOneID != userID && dsGame.PlayerTwoID != userID {
+		return 0, http.StatusForbidden
+	}
+	// Analysing a game that is still going would be the same as getting help from an engine
+	if dsGame.State != DONE || len(dsGame.History) == 0 {
+		return 0, http.StatusBadRequest
+	}
+
+	if turn < 0 {
+		turn = len(dsGame.History) - 1
+	}
+	if turn >= len(dsGame.History) {
+		return 0, http.StatusBadRequest
+	}
+	return dsGame.History[turn], http.StatusOK
+}
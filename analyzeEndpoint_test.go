@@ -0,0 +1,151 @@
+package neutrinoapi_test
+
+import (
+	"errors"
+	g "github.com/Morras/go-neutrino/game"
+	api "github.com/Morras/neutrinoapi"
+	"github.com/Morras/neutrinoapi/spy"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+)
+
+var _ = Describe("analyzeEndpoint", func() {
+
+	testUserID := "TestUserId"
+
+	var dataStoreSpy *spy.GameDataStoreSpy
+	var analyzerSpy *spy.AnalyzerSpy
+	var endpoint *api.AnalyzeEndpoint
+	var position uint64
+
+	BeforeEach(func() {
+		dataStoreSpy = &spy.GameDataStoreSpy{}
+		analyzerSpy = &spy.AnalyzerSpy{AnalyzeReturn: &api.Analysis{ForcedWin: true, WinningCondition: api.TRAP, Plies: 3}}
+		endpoint = api.NewAnalyzeEndpoint(dataStoreSpy, analyzerSpy)
+		position = g.GameToUInt64(g.NewStandardGame())
+	})
+
+	Context("performAction method", func() {
+
+		Context("given a serialized position", func() {
+			It("Should analyze the position", func() {
+				analysis, code := endpoint.PerformAction(testUserID, &api.AnalyzeRequest{SerializedGame: position, Plies: 2})
+				Expect(code).To(BeIdenticalTo(http.StatusOK))
+				Expect(analysis).To(BeIdenticalTo(analyzerSpy.AnalyzeReturn))
+				Expect(g.GameToUInt64(analyzerSpy.AnalyzeGame)).To(BeIdenticalTo(position))
+				Expect(analyzerSpy.AnalyzePlies).To(BeIdenticalTo(2))
+			})
+
+			It("Should flag an analysis that stopped short of the plies asked for", func() {
+				analyzerSpy.AnalyzeReturn = &api.Analysis{Plies: 1, Depth: 1}
+				analysis, code := endpoint.PerformAction(testUserID, &api.AnalyzeRequest{SerializedGame: position, Plies: 2})
+				Expect(code).To(BeIdenticalTo(http.StatusOK))
+				Expect(analysis.Complete).To(BeFalse())
+
+				analyzerSpy.AnalyzeReturn = &api.Analysis{Plies: 2, Depth: 2}
+				analysis, _ = endpoint.PerformAction(testUserID, &api.AnalyzeRequest{SerializedGame: position, Plies: 2})
+				Expect(analysis.Complete).To(BeTrue())
+			})
+
+			It("Should not flag a forced result found before the plies asked for", func() {
+				analysis, _ := endpoint.PerformAction(testUserID, &api.AnalyzeRequest{SerializedGame: position, Plies: 3})
+				Expect(analysis.Depth).To(BeIdenticalTo(0))
+				Expect(analysis.Complete).To(BeTrue())
+			})
+
+			It("Should search the default number of plies if none are given", func() {
+				endpoint.PerformAction(testUserID, &api.AnalyzeRequest{SerializedGame: position})
+				Expect(analyzerSpy.AnalyzePlies).To(BeIdenticalTo(api.ANALYSIS_DEFAULT_PLIES))
+			})
+
+			It("Should return bad request if asked to search too deep", func() {
+				_, code := endpoint.PerformAction(testUserID, &api.AnalyzeRequest{SerializedGame: position, Plies: api.ANALYSIS_MAX_PLIES + 1})
+				Expect(code).To(BeIdenticalTo(http.StatusBadRequest))
+				Expect(analyzerSpy.AnalyzeGame).To(BeNil())
+			})
+
+			It("Should return bad request if the game in the position is already over", func() {
+				over := g.NewStandardGame()
+				over.State = g.Player2Win
+				_, code := endpoint.PerformAction(testUserID, &api.AnalyzeRequest{SerializedGame: g.GameToUInt64(over)})
+				Expect(code).To(BeIdenticalTo(http.StatusBadRequest))
+			})
+
+			It("Should return bad request if the position cannot be analyzed", func() {
+				analyzerSpy.AnalyzeErr = errors.New("No legal turn")
+				_, code := endpoint.PerformAction(testUserID, &api.AnalyzeRequest{SerializedGame: position})
+				Expect(code).To(BeIdenticalTo(http.StatusBadRequest))
+			})
+
+			It("Should return forbidden if the position is on the board of one of the players live games", func() {
+				live := g.UInt64ToGame(position)
+				live.State = g.Player2Move
+				dataStoreSpy.ActiveGamesReturn = []*api.Game{{State: api.PLAYING, SerializedGame: g.GameToUInt64(live)}}
+				_, code := endpoint.PerformAction(testUserID, &api.AnalyzeRequest{SerializedGame: position})
+				Expect(code).To(BeIdenticalTo(http.StatusForbidden))
+				Expect(dataStoreSpy.ActiveGamesUserID).To(BeIdenticalTo(testUserID))
+				Expect(analyzerSpy.AnalyzeGame).To(BeNil())
+			})
+
+			It("Should return an internal server error if the live games cannot be looked up", func() {
+				dataStoreSpy.ActiveGamesErr = errors.New("Error getting active games")
+				_, code := endpoint.PerformAction(testUserID, &api.AnalyzeRequest{SerializedGame: position})
+				Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
+			})
+		})
+
+		Context("given a game", func() {
+			var game *api.Game
+
+			BeforeEach(func() {
+				second := g.NewStandardGame()
+				second.State = g.Player2NeutrinoMove
+				game = &api.Game{GameID: "game id", PlayerOneID: testUserID, PlayerTwoID: "opponent", State: api.DONE,
+					History: []uint64{position, g.GameToUInt64(second)}}
+				dataStoreSpy.GameReturn = game
+			})
+
+			It("Should analyze the position at the requested turn", func() {
+				_, code := endpoint.PerformAction(testUserID, &api.AnalyzeRequest{GameID: "game id", Turn: 0})
+				Expect(code).To(BeIdenticalTo(http.StatusOK))
+				Expect(dataStoreSpy.GameGameID).To(BeIdenticalTo("game id"))
+				Expect(g.GameToUInt64(analyzerSpy.AnalyzeGame)).To(BeIdenticalTo(position))
+			})
+
+			It("Should analyze the last position before the game ended if no turn is given", func() {
+				endpoint.PerformAction(testUserID, &api.AnalyzeRequest{GameID: "game id", Turn: -1})
+				Expect(g.GameToUInt64(analyzerSpy.AnalyzeGame)).To(BeIdenticalTo(game.History[1]))
+			})
+
+			It("Should return bad request for a turn that was never played", func() {
+				_, code := endpoint.PerformAction(testUserID, &api.AnalyzeRequest{GameID: "game id", Turn: 2})
+				Expect(code).To(BeIdenticalTo(http.StatusBadRequest))
+			})
+
+			It("Should return bad request while the game is still going", func() {
+				game.State = api.PLAYING
+				_, code := endpoint.PerformAction(testUserID, &api.AnalyzeRequest{GameID: "game id", Turn: 0})
+				Expect(code).To(BeIdenticalTo(http.StatusBadRequest))
+				Expect(analyzerSpy.AnalyzeGame).To(BeNil())
+			})
+
+			It("Should return forbidden to users who did not play the game", func() {
+				_, code := endpoint.PerformAction("someoneElse", &api.AnalyzeRequest{GameID: "game id", Turn: 0})
+				Expect(code).To(BeIdenticalTo(http.StatusForbidden))
+			})
+
+			It("Should return not found if the game does not exist", func() {
+				dataStoreSpy.GameReturn = nil
+				_, code := endpoint.PerformAction(testUserID, &api.AnalyzeRequest{GameID: "game id", Turn: 0})
+				Expect(code).To(BeIdenticalTo(http.StatusNotFound))
+			})
+
+			It("Should return an internal server error if the game cannot be looked up", func() {
+				dataStoreSpy.GameErr = errors.New("Error getting game")
+				_, code := endpoint.PerformAction(testUserID, &api.AnalyzeRequest{GameID: "game id", Turn: 0})
+				Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
+			})
+		})
+	})
+})
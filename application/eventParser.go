@@ -1,9 +1,8 @@
 package main
 
 import (
-	fjv "github.com/Morras/firebaseJwtValidator"
 	api "github.com/Morras/neutrinoapi" // TODO move the dependencies away from api
-	"strings"
+	"github.com/Morras/neutrinoapi/auth"
 	"github.com/eawsy/aws-lambda-go-event/service/lambda/runtime/event/apigatewayproxyevt"
 	"encoding/json"
 	"fmt"
@@ -12,39 +11,81 @@ import (
 
 type EventParser interface {
 	GetUserID(evt *apigatewayproxyevt.Event) (string, error)
+	GetAdminID(evt *apigatewayproxyevt.Event) (string, error)
 	ExtractMakeMoveRequest(evt *apigatewayproxyevt.Event) (*api.MakeMoveRequest, error)
+	ExtractPuzzleSolution(evt *apigatewayproxyevt.Event) (*api.MakeMoveRequest, error)
+	ExtractChatMessageRequest(evt *apigatewayproxyevt.Event) (*api.ChatMessageRequest, error)
+	ExtractNotificationPreferences(evt *apigatewayproxyevt.Event) (*api.NotificationPreferences, error)
 }
 
-type FirebaseTokenEventParser struct {
-	validator fjv.TokenValidator
+type TokenEventParser struct {
+	authenticator auth.Authenticator
+	adminPolicy   *api.AdminPolicy
 }
 
-func NewEventParser(validator fjv.TokenValidator) EventParser {
-	return &FirebaseTokenEventParser{validator: validator}
+func NewEventParser(authenticator auth.Authenticator, adminPolicy *api.AdminPolicy) EventParser {
+	return &TokenEventParser{authenticator: authenticator, adminPolicy: adminPolicy}
 }
 
-func (parser *FirebaseTokenEventParser) GetUserID(evt *apigatewayproxyevt.Event) (string, error) {
+func (parser *TokenEventParser) GetUserID(evt *apigatewayproxyevt.Event) (string, error) {
+	claims, err := parser.authenticate(evt)
+	if err != nil {
+		return "", err
+	}
+	return claims.Subject, nil
+}
+
+// GetAdminID works like GetUserID, but only for users the admin policy accepts
+func (parser *TokenEventParser) GetAdminID(evt *apigatewayproxyevt.Event) (string, error) {
+	claims, err := parser.authenticate(evt)
+	if err != nil {
+		return "", err
+	}
+	if !parser.adminPolicy.IsAdmin(claims) {
+		fmt.Printf("User %v tried to use an admin endpoint\n", claims.Subject)
+		return "", api.ErrNotAdmin
+	}
+	return claims.Subject, nil
+}
+
+func (parser *TokenEventParser) authenticate(evt *apigatewayproxyevt.Event) (*auth.Claims, error) {
 	jwt := evt.Headers[api.JWT_HEADER_KEY]
 
 	if jwt == "" {
-		return "", api.ErrMissingJWT
+		return nil, api.ErrMissingJWT
+	}
+
+	claims, err := parser.authenticator.Authenticate(jwt)
+	if err != nil {
+		fmt.Printf("Error authenticating request: %v\n", err)
+		return nil, api.ErrInvalidJWT
 	}
+	// Authenticators check this already, but an empty ID would let the request act as nobody in particular
+	if claims.Subject == "" {
+		return nil, api.ErrInvalidJWT
+	}
+	return claims, nil
+}
 
-	// Ignoring error as it should have been logged by the library
-	valid, _ := parser.validator.Validate(jwt)
+func (parser *TokenEventParser) ExtractMakeMoveRequest(evt *apigatewayproxyevt.Event) (*api.MakeMoveRequest, error) {
+	mmReq, err := unmarshalMakeMoveRequest(evt)
+	if err != nil {
+		return nil, err
+	}
 
-	if !valid {
-		return "", api.ErrInvalidJWT
+	if mmReq.GameID == "" {
+		return nil, errors.New("Missing game id in make move request body")
 	}
 
-	// We know the format is correct because validation succeeded
-	rawClaims := strings.Split(jwt, ".")[1]
+	return mmReq, nil
+}
 
-	_, claims := fjv.DecodeRawClaims(rawClaims)
-	return claims.Sub, nil
+// Puzzle solutions are turns like any other, they just are not played in a game
+func (parser *TokenEventParser) ExtractPuzzleSolution(evt *apigatewayproxyevt.Event) (*api.MakeMoveRequest, error) {
+	return unmarshalMakeMoveRequest(evt)
 }
 
-func (parser *FirebaseTokenEventParser) ExtractMakeMoveRequest(evt *apigatewayproxyevt.Event) (*api.MakeMoveRequest, error) {
+func unmarshalMakeMoveRequest(evt *apigatewayproxyevt.Event) (*api.MakeMoveRequest, error) {
 	bodyContent := []byte(evt.Body)
 
 	mmReq := &api.MakeMoveRequest{}
@@ -52,10 +93,32 @@ func (parser *FirebaseTokenEventParser) ExtractMakeMoveRequest(evt *apigatewaypr
 		fmt.Printf("Error unmarshalling %v", err)
 		return nil, err
 	}
+	return mmReq, nil
+}
 
-	if mmReq.GameID == "" {
-		return nil, errors.New("Missing game id in make move request body")
+func (parser *TokenEventParser) ExtractChatMessageRequest(evt *apigatewayproxyevt.Event) (*api.ChatMessageRequest, error) {
+	bodyContent := []byte(evt.Body)
+
+	chatReq := &api.ChatMessageRequest{}
+	if err := json.Unmarshal(bodyContent, chatReq); err != nil {
+		fmt.Printf("Error unmarshalling %v", err)
+		return nil, err
 	}
 
-	return mmReq, nil
+	if chatReq.GameID == "" {
+		return nil, errors.New("Missing game id in chat message request body")
+	}
+
+	return chatReq, nil
+}
+
+func (parser *TokenEventParser) ExtractNotificationPreferences(evt *apigatewayproxyevt.Event) (*api.NotificationPreferences, error) {
+	bodyContent := []byte(evt.Body)
+
+	preferences := &api.NotificationPreferences{}
+	if err := json.Unmarshal(bodyContent, preferences); err != nil {
+		fmt.Printf("Error unmarshalling %v", err)
+		return nil, err
+	}
+	return preferences, nil
 }
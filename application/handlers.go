@@ -4,30 +4,131 @@ import (
 	"github.com/eawsy/aws-lambda-go-event/service/lambda/runtime/event/apigatewayproxyevt"
 	"github.com/eawsy/aws-lambda-go-core/service/lambda/runtime"
 	api "github.com/Morras/neutrinoapi"
+	"github.com/Morras/neutrinoapi/bot"
 	"github.com/Morras/neutrinoapi/spy"
 
-	fjv "github.com/Morras/firebaseJwtValidator"
+	"github.com/Morras/neutrinoapi/auth"
 	"net/http"
 	"errors"
 	"strconv"
 	"github.com/Morras/go-neutrino/game"
+	"encoding/json"
+	"time"
+	"os"
+	"strings"
 )
 
 var eventParser EventParser
 var gameDataStore api.GameDataStore
+var challengeDataStore api.ChallengeDataStore
+var ratingDataStore api.RatingDataStore
+var leaderboardDataStore api.LeaderboardDataStore
+var puzzleDataStore api.PuzzleDataStore
+var chatDataStore api.ChatDataStore
+var outboxDataStore api.OutboxDataStore
+var webhookDataStore api.WebhookDataStore
+var notificationDataStore api.NotificationDataStore
+var auditDataStore api.AuditDataStore
+var auditSink api.AuditSink
 
 var getGameEndpoint *api.GetGameEndpoint
 var newGameEndpoint *api.NewGameEndpoint
 var makeMoveEndpoint *api.MakeMoveEndpoint
+var newPrivateGameEndpoint *api.NewPrivateGameEndpoint
+var joinPrivateGameEndpoint *api.JoinPrivateGameEndpoint
+var newChallengeEndpoint *api.NewChallengeEndpoint
+var getChallengesEndpoint *api.GetChallengesEndpoint
+var respondToChallengeEndpoint *api.RespondToChallengeEndpoint
+var requestRematchEndpoint *api.RequestRematchEndpoint
+var resignEndpoint *api.ResignEndpoint
+var getProfileEndpoint *api.GetProfileEndpoint
+var getLeaderboardEndpoint *api.GetLeaderboardEndpoint
+var newBotGameEndpoint *api.NewBotGameEndpoint
+var analyzeEndpoint *api.AnalyzeEndpoint
+var getLiveGamesEndpoint *api.GetLiveGamesEndpoint
+var waitForGameEndpoint *api.WaitForGameEndpoint
+var postChatMessageEndpoint *api.PostChatMessageEndpoint
+var getChatMessagesEndpoint *api.GetChatMessagesEndpoint
+var muteOpponentEndpoint *api.MuteOpponentEndpoint
+var getPuzzleEndpoint *api.GetPuzzleEndpoint
+var solvePuzzleEndpoint *api.SolvePuzzleEndpoint
+var registerDeviceEndpoint *api.RegisterDeviceEndpoint
+var unregisterDeviceEndpoint *api.UnregisterDeviceEndpoint
+var getNotificationPreferencesEndpoint *api.GetNotificationPreferencesEndpoint
+var setNotificationPreferencesEndpoint *api.SetNotificationPreferencesEndpoint
+var adminGetGameEndpoint *api.AdminGetGameEndpoint
+var adminListGamesEndpoint *api.AdminListGamesEndpoint
+var adminFinishGameEndpoint *api.AdminFinishGameEndpoint
+var adminVoidGameEndpoint *api.AdminVoidGameEndpoint
+var adminReassignPlayerEndpoint *api.AdminReassignPlayerEndpoint
+var adminGetAuditLogEndpoint *api.AdminGetAuditLogEndpoint
+var puzzleMiner *api.PuzzleMiner
+var botFallback *api.BotFallback
+var webhookDispatcher *api.WebhookDispatcher
+var gameEventPublisher *api.OutboxPublisher
 
 const projectID = api.FIREBASE_PROJECT_ID
 
 func init() {
-	eventParser = NewEventParser(fjv.NewDefaultTokenValidator(projectID))
+	authenticator, err := auth.NewAuthenticator(auth.ConfigFromEnv(projectID))
+	if err != nil {
+		panic(err)
+	}
+	eventParser = NewEventParser(authenticator, api.NewAdminPolicy(api.ADMIN_CLAIM, strings.Split(os.Getenv(api.ADMIN_USER_IDS_ENV), ",")))
 	gameDataStore = &spy.GameDataStoreSpy{} //TODO substitute datastore
-	getGameEndpoint = api.NewGetGameEndpoint(gameDataStore)
-	newGameEndpoint = api.NewNewGameEndpoint(gameDataStore)
-	makeMoveEndpoint = api.NewMakeMoveEndpoint(gameDataStore)
+	challengeDataStore = &spy.ChallengeDataStoreSpy{} //TODO substitute datastore
+	ratingDataStore = &spy.RatingDataStoreSpy{} //TODO substitute datastore
+	leaderboardDataStore = &spy.LeaderboardDataStoreSpy{} //TODO substitute datastore
+	puzzleDataStore = &spy.PuzzleDataStoreSpy{} //TODO substitute datastore
+	chatDataStore = &spy.ChatDataStoreSpy{} //TODO substitute datastore
+	outboxDataStore = &spy.OutboxDataStoreSpy{} //TODO substitute datastore
+	webhookDataStore = &spy.WebhookDataStoreSpy{} //TODO substitute datastore
+	notificationDataStore = &spy.NotificationDataStoreSpy{} //TODO substitute datastore
+	auditDataStore = &spy.AuditDataStoreSpy{} //TODO substitute datastore
+	auditSink = api.NewDataStoreAuditSink(auditDataStore)
+	rater := api.NewRater(ratingDataStore, leaderboardDataStore)
+	// Events that cannot be handled wait in the outbox until RetryOutboxHandler runs
+	inProcessPublisher := api.NewInProcessGameEventPublisher()
+	webhookDispatcher = api.NewWebhookDispatcher(webhookDataStore, &http.Client{Timeout: api.WEBHOOK_TIMEOUT}, api.WEBHOOK_MAX_ATTEMPTS, api.WEBHOOK_INITIAL_BACKOFF)
+	inProcessPublisher.Subscribe(api.EVENT_HANDLER_WEBHOOKS, webhookDispatcher)
+	inProcessPublisher.Subscribe(api.EVENT_HANDLER_NOTIFICATIONS, api.NewGameEventNotifier(notificationDataStore, &api.LoggingNotifier{})) //TODO substitute FCM and APNs notifiers
+	gameEventPublisher = api.NewOutboxPublisher(inProcessPublisher, outboxDataStore)
+	getGameEndpoint = api.NewGetGameEndpoint(gameDataStore, ratingDataStore)
+	newGameEndpoint = api.NewNewGameEndpoint(gameDataStore, api.NewRatingWindowMatchmaker(gameDataStore, ratingDataStore), api.DEFAULT_RECENT_OPPONENTS_TO_AVOID, gameEventPublisher, auditSink)
+	botFallback = api.NewBotFallback(gameDataStore, api.DEFAULT_BOT_FALLBACK_AFTER, gameEventPublisher, auditSink)
+	botPlayer := bot.NewMinimaxBot(func() game.GameController { return &game.Controller{} }, api.ANALYSIS_NODE_BUDGET)
+	makeMoveEndpoint = api.NewMakeMoveEndpoint(gameDataStore, rater, botPlayer, gameEventPublisher, auditSink)
+	newPrivateGameEndpoint = api.NewNewPrivateGameEndpoint(gameDataStore, gameEventPublisher, auditSink)
+	joinPrivateGameEndpoint = api.NewJoinPrivateGameEndpoint(gameDataStore, gameEventPublisher, auditSink)
+	newChallengeEndpoint = api.NewNewChallengeEndpoint(gameDataStore, challengeDataStore, api.DEFAULT_CHALLENGE_TTL)
+	getChallengesEndpoint = api.NewGetChallengesEndpoint(challengeDataStore)
+	respondToChallengeEndpoint = api.NewRespondToChallengeEndpoint(gameDataStore, challengeDataStore, gameEventPublisher, auditSink)
+	requestRematchEndpoint = api.NewRequestRematchEndpoint(gameDataStore, challengeDataStore, api.DEFAULT_CHALLENGE_TTL)
+	resignEndpoint = api.NewResignEndpoint(gameDataStore, rater, gameEventPublisher, auditSink)
+	getProfileEndpoint = api.NewGetProfileEndpoint(gameDataStore, ratingDataStore)
+	getLeaderboardEndpoint = api.NewGetLeaderboardEndpoint(leaderboardDataStore)
+	newBotGameEndpoint = api.NewNewBotGameEndpoint(gameDataStore, gameEventPublisher, auditSink)
+	analyzeEndpoint = api.NewAnalyzeEndpoint(gameDataStore, botPlayer)
+	getPuzzleEndpoint = api.NewGetPuzzleEndpoint(puzzleDataStore)
+	solvePuzzleEndpoint = api.NewSolvePuzzleEndpoint(puzzleDataStore, botPlayer)
+	// Mining runs offline, so it gets a bigger budget than anything analysed inside a request
+	puzzleMiner = api.NewPuzzleMiner(gameDataStore, puzzleDataStore,
+		bot.NewMinimaxBot(func() game.GameController { return &game.Controller{} }, api.PUZZLE_MINING_NODE_BUDGET))
+	getLiveGamesEndpoint = api.NewGetLiveGamesEndpoint(gameDataStore, ratingDataStore)
+	waitForGameEndpoint = api.NewWaitForGameEndpoint(gameDataStore, getGameEndpoint, api.LONG_POLL_INTERVAL, api.LONG_POLL_TIMEOUT)
+	postChatMessageEndpoint = api.NewPostChatMessageEndpoint(gameDataStore, chatDataStore, api.NewWordListFilter(nil)) //TODO substitute word list
+	getChatMessagesEndpoint = api.NewGetChatMessagesEndpoint(gameDataStore, chatDataStore)
+	muteOpponentEndpoint = api.NewMuteOpponentEndpoint(gameDataStore, chatDataStore)
+	registerDeviceEndpoint = api.NewRegisterDeviceEndpoint(notificationDataStore)
+	unregisterDeviceEndpoint = api.NewUnregisterDeviceEndpoint(notificationDataStore)
+	getNotificationPreferencesEndpoint = api.NewGetNotificationPreferencesEndpoint(notificationDataStore)
+	setNotificationPreferencesEndpoint = api.NewSetNotificationPreferencesEndpoint(notificationDataStore)
+	adminGetGameEndpoint = api.NewAdminGetGameEndpoint(gameDataStore, auditSink)
+	adminListGamesEndpoint = api.NewAdminListGamesEndpoint(gameDataStore, auditSink)
+	adminFinishGameEndpoint = api.NewAdminFinishGameEndpoint(gameDataStore, rater, gameEventPublisher, auditSink)
+	adminVoidGameEndpoint = api.NewAdminVoidGameEndpoint(gameDataStore, gameEventPublisher, auditSink)
+	adminReassignPlayerEndpoint = api.NewAdminReassignPlayerEndpoint(gameDataStore, gameEventPublisher, auditSink)
+	adminGetAuditLogEndpoint = api.NewAdminGetAuditLogEndpoint(auditDataStore, auditSink)
 }
 
 func GetGameHandler(evt *apigatewayproxyevt.Event, ctx *runtime.Context) (interface{}, error) {
@@ -48,6 +149,26 @@ func GetGameHandler(evt *apigatewayproxyevt.Event, ctx *runtime.Context) (interf
 	return games, nil
 }
 
+func WaitForGameHandler(evt *apigatewayproxyevt.Event, ctx *runtime.Context) (interface{}, error) {
+	userID, err := eventParser.GetUserID(evt)
+
+	if err != nil {
+		return nil, prefixErrorMessageInStatusCode(err, http.StatusForbidden)
+	}
+
+	gameID := evt.QueryStringParameters[api.QUERY_WAIT_FOR_GAME_GAME_ID]
+	version, err := strconv.Atoi(evt.QueryStringParameters[api.QUERY_WAIT_FOR_GAME_VERSION])
+	if err != nil {
+		return nil, prefixErrorMessageInStatusCode(err, http.StatusBadRequest)
+	}
+
+	game, statusCode := waitForGameEndpoint.PerformAction(userID, gameID, version)
+	if statusCode != http.StatusOK {
+		return nil, wrapStatusCodeInError(statusCode)
+	}
+	return game, nil
+}
+
 func NewGameHandler(evt *apigatewayproxyevt.Event, ctx *runtime.Context) (interface{}, error) {
 	userID, err := eventParser.GetUserID(evt)
 
@@ -55,7 +176,12 @@ func NewGameHandler(evt *apigatewayproxyevt.Event, ctx *runtime.Context) (interf
 		return nil, prefixErrorMessageInStatusCode(err, http.StatusForbidden)
 	}
 
-	gameID, statusCode := newGameEndpoint.PerformAction(userID)
+	visibility, err := extractVisibility(evt, api.QUERY_NEW_GAME_VISIBILITY)
+	if err != nil {
+		return nil, prefixErrorMessageInStatusCode(err, http.StatusBadRequest)
+	}
+
+	gameID, statusCode := newGameEndpoint.PerformAction(requestIDs(evt, ctx), userID, visibility)
 	if statusCode != http.StatusOK {
 		return "", wrapStatusCodeInError(statusCode)
 	}
@@ -74,13 +200,499 @@ func MakeMoveHandler(evt *apigatewayproxyevt.Event, ctx *runtime.Context) (inter
 		return nil, prefixErrorMessageInStatusCode(err, http.StatusBadRequest)
 	}
 
-	statusCode := makeMoveEndpoint.PerformAction(userID, makeMoveReq, &game.Controller{})
+	statusCode := makeMoveEndpoint.PerformAction(requestIDs(evt, ctx), userID, makeMoveReq, &game.Controller{})
+	if statusCode != http.StatusOK {
+		return "", wrapStatusCodeInError(statusCode)
+	}
+	return nil, nil
+}
+
+func NewPrivateGameHandler(evt *apigatewayproxyevt.Event, ctx *runtime.Context) (interface{}, error) {
+	userID, err := eventParser.GetUserID(evt)
+
+	if err != nil {
+		return nil, prefixErrorMessageInStatusCode(err, http.StatusForbidden)
+	}
+
+	visibility, err := extractVisibility(evt, api.QUERY_NEW_PRIVATE_GAME_VISIBILITY)
+	if err != nil {
+		return nil, prefixErrorMessageInStatusCode(err, http.StatusBadRequest)
+	}
+
+	invite, statusCode := newPrivateGameEndpoint.PerformAction(requestIDs(evt, ctx), userID, visibility)
+	if statusCode != http.StatusOK {
+		return nil, wrapStatusCodeInError(statusCode)
+	}
+	return invite, nil
+}
+
+func JoinPrivateGameHandler(evt *apigatewayproxyevt.Event, ctx *runtime.Context) (interface{}, error) {
+	userID, err := eventParser.GetUserID(evt)
+
+	if err != nil {
+		return nil, prefixErrorMessageInStatusCode(err, http.StatusForbidden)
+	}
+
+	inviteCode := evt.QueryStringParameters[api.QUERY_JOIN_PRIVATE_GAME_INVITE_CODE]
+
+	gameID, statusCode := joinPrivateGameEndpoint.PerformAction(requestIDs(evt, ctx), userID, inviteCode)
+	if statusCode != http.StatusOK {
+		return "", wrapStatusCodeInError(statusCode)
+	}
+	return gameID, nil
+}
+
+func NewChallengeHandler(evt *apigatewayproxyevt.Event, ctx *runtime.Context) (interface{}, error) {
+	userID, err := eventParser.GetUserID(evt)
+
+	if err != nil {
+		return nil, prefixErrorMessageInStatusCode(err, http.StatusForbidden)
+	}
+
+	challengedID := evt.QueryStringParameters[api.QUERY_NEW_CHALLENGE_CHALLENGED_ID]
+
+	challengeID, statusCode := newChallengeEndpoint.PerformAction(userID, challengedID)
+	if statusCode != http.StatusOK {
+		return "", wrapStatusCodeInError(statusCode)
+	}
+	return challengeID, nil
+}
+
+func GetChallengesHandler(evt *apigatewayproxyevt.Event, ctx *runtime.Context) (interface{}, error) {
+	userID, err := eventParser.GetUserID(evt)
+
+	if err != nil {
+		return nil, prefixErrorMessageInStatusCode(err, http.StatusForbidden)
+	}
+
+	challenges, statusCode := getChallengesEndpoint.PerformAction(userID)
+	if statusCode != http.StatusOK {
+		return challenges, wrapStatusCodeInError(statusCode)
+	}
+	return challenges, nil
+}
+
+func RespondToChallengeHandler(evt *apigatewayproxyevt.Event, ctx *runtime.Context) (interface{}, error) {
+	userID, err := eventParser.GetUserID(evt)
+
+	if err != nil {
+		return nil, prefixErrorMessageInStatusCode(err, http.StatusForbidden)
+	}
+
+	challengeID := evt.QueryStringParameters[api.QUERY_RESPOND_TO_CHALLENGE_CHALLENGE_ID]
+	accept, err := strconv.ParseBool(evt.QueryStringParameters[api.QUERY_RESPOND_TO_CHALLENGE_ACCEPT])
+	if err != nil {
+		return nil, prefixErrorMessageInStatusCode(err, http.StatusBadRequest)
+	}
+
+	gameID, statusCode := respondToChallengeEndpoint.PerformAction(requestIDs(evt, ctx), userID, challengeID, accept)
+	if statusCode != http.StatusOK {
+		return "", wrapStatusCodeInError(statusCode)
+	}
+	return gameID, nil
+}
+
+func RequestRematchHandler(evt *apigatewayproxyevt.Event, ctx *runtime.Context) (interface{}, error) {
+	userID, err := eventParser.GetUserID(evt)
+
+	if err != nil {
+		return nil, prefixErrorMessageInStatusCode(err, http.StatusForbidden)
+	}
+
+	gameID := evt.QueryStringParameters[api.QUERY_REQUEST_REMATCH_GAME_ID]
+
+	challengeID, statusCode := requestRematchEndpoint.PerformAction(userID, gameID)
+	if statusCode != http.StatusOK {
+		return "", wrapStatusCodeInError(statusCode)
+	}
+	return challengeID, nil
+}
+
+func ResignHandler(evt *apigatewayproxyevt.Event, ctx *runtime.Context) (interface{}, error) {
+	userID, err := eventParser.GetUserID(evt)
+
+	if err != nil {
+		return nil, prefixErrorMessageInStatusCode(err, http.StatusForbidden)
+	}
+
+	gameID := evt.QueryStringParameters[api.QUERY_RESIGN_GAME_ID]
+
+	statusCode := resignEndpoint.PerformAction(requestIDs(evt, ctx), userID, gameID)
+	if statusCode != http.StatusOK {
+		return "", wrapStatusCodeInError(statusCode)
+	}
+	return nil, nil
+}
+
+func GetProfileHandler(evt *apigatewayproxyevt.Event, ctx *runtime.Context) (interface{}, error) {
+	userID, err := eventParser.GetUserID(evt)
+
+	if err != nil {
+		return nil, prefixErrorMessageInStatusCode(err, http.StatusForbidden)
+	}
+
+	// Players see their own profile unless they ask for someone else's
+	profileUserID := evt.QueryStringParameters[api.QUERY_GET_PROFILE_USER_ID]
+	if profileUserID == "" {
+		profileUserID = userID
+	}
+
+	profile, statusCode := getProfileEndpoint.PerformAction(profileUserID)
+	if statusCode != http.StatusOK {
+		return nil, wrapStatusCodeInError(statusCode)
+	}
+	return profile, nil
+}
+
+func GetLeaderboardHandler(evt *apigatewayproxyevt.Event, ctx *runtime.Context) (interface{}, error) {
+	userID, err := eventParser.GetUserID(evt)
+
+	if err != nil {
+		return nil, prefixErrorMessageInStatusCode(err, http.StatusForbidden)
+	}
+
+	// Do not care about errors as parse errors give the first page from the top anyway
+	aroundMe, _ := strconv.ParseBool(evt.QueryStringParameters[api.QUERY_GET_LEADERBOARD_AROUND_ME])
+	page, _ := strconv.Atoi(evt.QueryStringParameters[api.QUERY_GET_LEADERBOARD_PAGE])
+
+	entries, statusCode := getLeaderboardEndpoint.PerformAction(userID, aroundMe, page)
+	if statusCode != http.StatusOK {
+		return entries, wrapStatusCodeInError(statusCode)
+	}
+	return entries, nil
+}
+
+func NewBotGameHandler(evt *apigatewayproxyevt.Event, ctx *runtime.Context) (interface{}, error) {
+	userID, err := eventParser.GetUserID(evt)
+
+	if err != nil {
+		return nil, prefixErrorMessageInStatusCode(err, http.StatusForbidden)
+	}
+
+	difficulty, err := api.ParseBotDifficulty(evt.QueryStringParameters[api.QUERY_NEW_BOT_GAME_DIFFICULTY])
+	if err != nil {
+		return nil, prefixErrorMessageInStatusCode(err, http.StatusBadRequest)
+	}
+
+	gameID, statusCode := newBotGameEndpoint.PerformAction(requestIDs(evt, ctx), userID, difficulty)
+	if statusCode != http.StatusOK {
+		return "", wrapStatusCodeInError(statusCode)
+	}
+	return gameID, nil
+}
+
+func AnalyzeHandler(evt *apigatewayproxyevt.Event, ctx *runtime.Context) (interface{}, error) {
+	userID, err := eventParser.GetUserID(evt)
+
+	if err != nil {
+		return nil, prefixErrorMessageInStatusCode(err, http.StatusForbidden)
+	}
+
+	analyzeReq, err := extractAnalyzeRequest(evt)
+	if err != nil {
+		return nil, prefixErrorMessageInStatusCode(err, http.StatusBadRequest)
+	}
+
+	analysis, statusCode := analyzeEndpoint.PerformAction(userID, analyzeReq)
+	if statusCode != http.StatusOK {
+		return nil, wrapStatusCodeInError(statusCode)
+	}
+	return analysis, nil
+}
+
+// Players either ask about a turn in one of their games or send the position itself
+func extractAnalyzeRequest(evt *apigatewayproxyevt.Event) (*api.AnalyzeRequest, error) {
+	params := evt.QueryStringParameters
+	analyzeReq := &api.AnalyzeRequest{GameID: params[api.QUERY_ANALYZE_GAME_ID], Turn: -1}
+	var err error
+
+	if turn := params[api.QUERY_ANALYZE_TURN]; turn != "" {
+		if analyzeReq.Turn, err = strconv.Atoi(turn); err != nil {
+			return nil, err
+		}
+	}
+	if plies := params[api.QUERY_ANALYZE_PLIES]; plies != "" {
+		if analyzeReq.Plies, err = strconv.Atoi(plies); err != nil {
+			return nil, err
+		}
+	}
+	if analyzeReq.GameID == "" {
+		if analyzeReq.SerializedGame, err = strconv.ParseUint(params[api.QUERY_ANALYZE_POSITION], 10, 64); err != nil {
+			return nil, err
+		}
+	}
+	return analyzeReq, nil
+}
+
+func GetPuzzleHandler(evt *apigatewayproxyevt.Event, ctx *runtime.Context) (interface{}, error) {
+	userID, err := eventParser.GetUserID(evt)
+
+	if err != nil {
+		return nil, prefixErrorMessageInStatusCode(err, http.StatusForbidden)
+	}
+
+	puzzle, statusCode := getPuzzleEndpoint.PerformAction(userID)
+	if statusCode != http.StatusOK {
+		return nil, wrapStatusCodeInError(statusCode)
+	}
+	return puzzle, nil
+}
+
+func SolvePuzzleHandler(evt *apigatewayproxyevt.Event, ctx *runtime.Context) (interface{}, error) {
+	userID, err := eventParser.GetUserID(evt)
+
+	if err != nil {
+		return nil, prefixErrorMessageInStatusCode(err, http.StatusForbidden)
+	}
+
+	puzzleID := evt.QueryStringParameters[api.QUERY_SOLVE_PUZZLE_PUZZLE_ID]
+	solution, err := eventParser.ExtractPuzzleSolution(evt)
+	if err != nil {
+		return nil, prefixErrorMessageInStatusCode(err, http.StatusBadRequest)
+	}
+
+	result, statusCode := solvePuzzleEndpoint.PerformAction(userID, puzzleID, solution, &game.Controller{})
+	if statusCode != http.StatusOK {
+		return nil, wrapStatusCodeInError(statusCode)
+	}
+	return result, nil
+}
+
+// MinePuzzlesHandler is run on a schedule every PUZZLE_MINING_INTERVAL rather than by players.
+func MinePuzzlesHandler(evt json.RawMessage, ctx *runtime.Context) (interface{}, error) {
+	found, err := puzzleMiner.MineGames(time.Now().Add(-api.PUZZLE_MINING_INTERVAL))
+	if err != nil {
+		return nil, err
+	}
+	return found, nil
+}
+
+// RetryOutboxHandler is meant to run on a schedule, publishing events that failed the first time.
+func RetryOutboxHandler(evt json.RawMessage, ctx *runtime.Context) (interface{}, error) {
+	published, err := gameEventPublisher.RetryOutbox()
+	if err != nil {
+		return nil, err
+	}
+	return published, nil
+}
+
+// SubstituteBotsHandler is meant to run on a schedule every BOT_FALLBACK_INTERVAL, letting bots join
+// the games that have waited too long for an opponent.
+func SubstituteBotsHandler(evt json.RawMessage, ctx *runtime.Context) (interface{}, error) {
+	ids := api.NewRequestIDs("")
+	if ctx != nil && ctx.AWSRequestID != "" {
+		ids.ID = ctx.AWSRequestID
+	}
+	substituted, err := botFallback.SubstituteBots(ids)
+	if err != nil {
+		return nil, err
+	}
+	return substituted, nil
+}
+
+// DeliverWebhooksHandler is meant to run on a schedule, posting the webhooks game events have queued.
+func DeliverWebhooksHandler(evt json.RawMessage, ctx *runtime.Context) (interface{}, error) {
+	delivered, err := webhookDispatcher.DeliverPending()
+	if err != nil {
+		return nil, err
+	}
+	return delivered, nil
+}
+
+func GetLiveGamesHandler(evt *apigatewayproxyevt.Event, ctx *runtime.Context) (interface{}, error) {
+	_, err := eventParser.GetUserID(evt)
+
+	if err != nil {
+		return nil, prefixErrorMessageInStatusCode(err, http.StatusForbidden)
+	}
+
+	// Do not care about errors as parse errors give the first page anyway
+	page, _ := strconv.Atoi(evt.QueryStringParameters[api.QUERY_GET_LIVE_GAMES_PAGE])
+
+	games, statusCode := getLiveGamesEndpoint.PerformAction(page)
+	if statusCode != http.StatusOK {
+		return games, wrapStatusCodeInError(statusCode)
+	}
+	return games, nil
+}
+
+func PostChatMessageHandler(evt *apigatewayproxyevt.Event, ctx *runtime.Context) (interface{}, error) {
+	userID, err := eventParser.GetUserID(evt)
+
+	if err != nil {
+		return nil, prefixErrorMessageInStatusCode(err, http.StatusForbidden)
+	}
+
+	chatReq, err := eventParser.ExtractChatMessageRequest(evt)
+	if err != nil {
+		return nil, prefixErrorMessageInStatusCode(err, http.StatusBadRequest)
+	}
+
+	messageID, statusCode := postChatMessageEndpoint.PerformAction(userID, chatReq)
+	if statusCode != http.StatusOK {
+		return "", wrapStatusCodeInError(statusCode)
+	}
+	return messageID, nil
+}
+
+func GetChatMessagesHandler(evt *apigatewayproxyevt.Event, ctx *runtime.Context) (interface{}, error) {
+	userID, err := eventParser.GetUserID(evt)
+
+	if err != nil {
+		return nil, prefixErrorMessageInStatusCode(err, http.StatusForbidden)
+	}
+
+	gameID := evt.QueryStringParameters[api.QUERY_GET_CHAT_MESSAGES_GAME_ID]
+	// Do not care about errors as parse errors give the newest messages anyway
+	page, _ := strconv.Atoi(evt.QueryStringParameters[api.QUERY_GET_CHAT_MESSAGES_PAGE])
+
+	messages, statusCode := getChatMessagesEndpoint.PerformAction(userID, gameID, page)
+	if statusCode != http.StatusOK {
+		return messages, wrapStatusCodeInError(statusCode)
+	}
+	return messages, nil
+}
+
+func MuteOpponentHandler(evt *apigatewayproxyevt.Event, ctx *runtime.Context) (interface{}, error) {
+	userID, err := eventParser.GetUserID(evt)
+
+	if err != nil {
+		return nil, prefixErrorMessageInStatusCode(err, http.StatusForbidden)
+	}
+
+	gameID := evt.QueryStringParameters[api.QUERY_MUTE_OPPONENT_GAME_ID]
+	muted, err := strconv.ParseBool(evt.QueryStringParameters[api.QUERY_MUTE_OPPONENT_MUTED])
+	if err != nil {
+		return nil, prefixErrorMessageInStatusCode(err, http.StatusBadRequest)
+	}
+
+	statusCode := muteOpponentEndpoint.PerformAction(userID, gameID, muted)
+	if statusCode != http.StatusOK {
+		return "", wrapStatusCodeInError(statusCode)
+	}
+	return nil, nil
+}
+
+func AdminGetGameHandler(evt *apigatewayproxyevt.Event, ctx *runtime.Context) (interface{}, error) {
+	adminID, err := eventParser.GetAdminID(evt)
+
+	if err != nil {
+		return nil, prefixErrorMessageInStatusCode(err, http.StatusForbidden)
+	}
+
+	gameID := evt.QueryStringParameters[api.QUERY_ADMIN_GET_GAME_GAME_ID]
+	game, statusCode := adminGetGameEndpoint.PerformAction(requestIDs(evt, ctx), adminID, gameID)
+	if statusCode != http.StatusOK {
+		return nil, wrapStatusCodeInError(statusCode)
+	}
+	return game, nil
+}
+
+func AdminListGamesHandler(evt *apigatewayproxyevt.Event, ctx *runtime.Context) (interface{}, error) {
+	adminID, err := eventParser.GetAdminID(evt)
+
+	if err != nil {
+		return nil, prefixErrorMessageInStatusCode(err, http.StatusForbidden)
+	}
+
+	state, err := api.ParseState(evt.QueryStringParameters[api.QUERY_ADMIN_LIST_GAMES_STATE])
+	if err != nil {
+		return nil, prefixErrorMessageInStatusCode(err, http.StatusBadRequest)
+	}
+	// Do not care about errors as parse errors give the first page anyway
+	page, _ := strconv.Atoi(evt.QueryStringParameters[api.QUERY_ADMIN_LIST_GAMES_PAGE])
+
+	games, statusCode := adminListGamesEndpoint.PerformAction(requestIDs(evt, ctx), adminID, state, page)
+	if statusCode != http.StatusOK {
+		return nil, wrapStatusCodeInError(statusCode)
+	}
+	return games, nil
+}
+
+func AdminFinishGameHandler(evt *apigatewayproxyevt.Event, ctx *runtime.Context) (interface{}, error) {
+	adminID, err := eventParser.GetAdminID(evt)
+
+	if err != nil {
+		return nil, prefixErrorMessageInStatusCode(err, http.StatusForbidden)
+	}
+
+	gameID := evt.QueryStringParameters[api.QUERY_ADMIN_FINISH_GAME_GAME_ID]
+	winnerID := evt.QueryStringParameters[api.QUERY_ADMIN_FINISH_GAME_WINNER_ID]
+	statusCode := adminFinishGameEndpoint.PerformAction(requestIDs(evt, ctx), adminID, gameID, winnerID)
 	if statusCode != http.StatusOK {
 		return "", wrapStatusCodeInError(statusCode)
 	}
 	return nil, nil
 }
 
+func AdminVoidGameHandler(evt *apigatewayproxyevt.Event, ctx *runtime.Context) (interface{}, error) {
+	adminID, err := eventParser.GetAdminID(evt)
+
+	if err != nil {
+		return nil, prefixErrorMessageInStatusCode(err, http.StatusForbidden)
+	}
+
+	gameID := evt.QueryStringParameters[api.QUERY_ADMIN_VOID_GAME_GAME_ID]
+	statusCode := adminVoidGameEndpoint.PerformAction(requestIDs(evt, ctx), adminID, gameID)
+	if statusCode != http.StatusOK {
+		return "", wrapStatusCodeInError(statusCode)
+	}
+	return nil, nil
+}
+
+func AdminReassignPlayerHandler(evt *apigatewayproxyevt.Event, ctx *runtime.Context) (interface{}, error) {
+	adminID, err := eventParser.GetAdminID(evt)
+
+	if err != nil {
+		return nil, prefixErrorMessageInStatusCode(err, http.StatusForbidden)
+	}
+
+	gameID := evt.QueryStringParameters[api.QUERY_ADMIN_REASSIGN_PLAYER_GAME_ID]
+	fromUserID := evt.QueryStringParameters[api.QUERY_ADMIN_REASSIGN_PLAYER_FROM_USER_ID]
+	toUserID := evt.QueryStringParameters[api.QUERY_ADMIN_REASSIGN_PLAYER_TO_USER_ID]
+	statusCode := adminReassignPlayerEndpoint.PerformAction(requestIDs(evt, ctx), adminID, gameID, fromUserID, toUserID)
+	if statusCode != http.StatusOK {
+		return "", wrapStatusCodeInError(statusCode)
+	}
+	return nil, nil
+}
+
+func AdminGetAuditLogHandler(evt *apigatewayproxyevt.Event, ctx *runtime.Context) (interface{}, error) {
+	adminID, err := eventParser.GetAdminID(evt)
+
+	if err != nil {
+		return nil, prefixErrorMessageInStatusCode(err, http.StatusForbidden)
+	}
+
+	gameID := evt.QueryStringParameters[api.QUERY_ADMIN_GET_AUDIT_LOG_GAME_ID]
+	actorID := evt.QueryStringParameters[api.QUERY_ADMIN_GET_AUDIT_LOG_ACTOR_ID]
+	// Do not care about errors as parse errors give the newest entries anyway
+	page, _ := strconv.Atoi(evt.QueryStringParameters[api.QUERY_ADMIN_GET_AUDIT_LOG_PAGE])
+
+	entries, statusCode := adminGetAuditLogEndpoint.PerformAction(requestIDs(evt, ctx), adminID, gameID, actorID, page)
+	if statusCode != http.StatusOK {
+		return nil, wrapStatusCodeInError(statusCode)
+	}
+	return entries, nil
+}
+
+// Games are only visible to their players unless the creator asks for something else
+func extractVisibility(evt *apigatewayproxyevt.Event, param string) (api.Visibility, error) {
+	if evt.QueryStringParameters[param] == "" {
+		return api.PLAYERS_ONLY, nil
+	}
+	return api.ParseVisibility(evt.QueryStringParameters[param])
+}
+
+// Lambda gives every invocation an ID that also shows up in its logs, so the audit log uses that
+func requestIDs(evt *apigatewayproxyevt.Event, ctx *runtime.Context) api.RequestIDs {
+	ids := api.NewRequestIDs(evt.Headers[api.REQUEST_ID_HEADER_KEY])
+	if ctx != nil && ctx.AWSRequestID != "" {
+		ids.ID = ctx.AWSRequestID
+	}
+	return ids
+}
+
 func wrapStatusCodeInError(statusCode int) error {
 	return errors.New("[" + strconv.Itoa(statusCode) + "]")
 }
@@ -88,3 +700,72 @@ func wrapStatusCodeInError(statusCode int) error {
 func prefixErrorMessageInStatusCode(err error, httpCode int) error {
 	return errors.New("[" + strconv.Itoa(httpCode) + "]" + err.Error())
 }
+
+func RegisterDeviceHandler(evt *apigatewayproxyevt.Event, ctx *runtime.Context) (interface{}, error) {
+	userID, err := eventParser.GetUserID(evt)
+
+	if err != nil {
+		return nil, prefixErrorMessageInStatusCode(err, http.StatusForbidden)
+	}
+
+	token := evt.QueryStringParameters[api.QUERY_REGISTER_DEVICE_TOKEN]
+	platform, err := api.ParseDevicePlatform(evt.QueryStringParameters[api.QUERY_REGISTER_DEVICE_PLATFORM])
+	if err != nil {
+		return nil, prefixErrorMessageInStatusCode(err, http.StatusBadRequest)
+	}
+
+	statusCode := registerDeviceEndpoint.PerformAction(userID, token, platform)
+	if statusCode != http.StatusOK {
+		return "", wrapStatusCodeInError(statusCode)
+	}
+	return nil, nil
+}
+
+func UnregisterDeviceHandler(evt *apigatewayproxyevt.Event, ctx *runtime.Context) (interface{}, error) {
+	userID, err := eventParser.GetUserID(evt)
+
+	if err != nil {
+		return nil, prefixErrorMessageInStatusCode(err, http.StatusForbidden)
+	}
+
+	token := evt.QueryStringParameters[api.QUERY_UNREGISTER_DEVICE_TOKEN]
+
+	statusCode := unregisterDeviceEndpoint.PerformAction(userID, token)
+	if statusCode != http.StatusOK {
+		return "", wrapStatusCodeInError(statusCode)
+	}
+	return nil, nil
+}
+
+func GetNotificationPreferencesHandler(evt *apigatewayproxyevt.Event, ctx *runtime.Context) (interface{}, error) {
+	userID, err := eventParser.GetUserID(evt)
+
+	if err != nil {
+		return nil, prefixErrorMessageInStatusCode(err, http.StatusForbidden)
+	}
+
+	preferences, statusCode := getNotificationPreferencesEndpoint.PerformAction(userID)
+	if statusCode != http.StatusOK {
+		return "", wrapStatusCodeInError(statusCode)
+	}
+	return preferences, nil
+}
+
+func SetNotificationPreferencesHandler(evt *apigatewayproxyevt.Event, ctx *runtime.Context) (interface{}, error) {
+	userID, err := eventParser.GetUserID(evt)
+
+	if err != nil {
+		return nil, prefixErrorMessageInStatusCode(err, http.StatusForbidden)
+	}
+
+	preferences, err := eventParser.ExtractNotificationPreferences(evt)
+	if err != nil {
+		return nil, prefixErrorMessageInStatusCode(err, http.StatusBadRequest)
+	}
+
+	statusCode := setNotificationPreferencesEndpoint.PerformAction(userID, preferences)
+	if statusCode != http.StatusOK {
+		return "", wrapStatusCodeInError(statusCode)
+	}
+	return nil, nil
+}
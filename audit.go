@@ -0,0 +1,149 @@
+package neutrinoapi
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	AUDIT_NEW_GAME              = "newGame"
+	AUDIT_JOIN_GAME             = "joinGame"
+	AUDIT_NEW_PRIVATE_GAME      = "newPrivateGame"
+	AUDIT_JOIN_PRIVATE_GAME     = "joinPrivateGame"
+	AUDIT_ACCEPT_CHALLENGE      = "acceptChallenge"
+	AUDIT_NEW_BOT_GAME          = "newBotGame"
+	AUDIT_BOT_SUBSTITUTED       = "botSubstituted"
+	AUDIT_MAKE_MOVE             = "makeMove"
+	AUDIT_RESIGN                = "resign"
+	AUDIT_ADMIN_GET_GAME        = "adminGetGame"
+	AUDIT_ADMIN_LIST_GAMES      = "adminListGames"
+	AUDIT_ADMIN_FINISH_GAME     = "adminFinishGame"
+	AUDIT_ADMIN_VOID_GAME       = "adminVoidGame"
+	AUDIT_ADMIN_REASSIGN_PLAYER = "adminReassignPlayer"
+	AUDIT_ADMIN_GET_AUDIT_LOG   = "adminGetAuditLog"
+)
+
+// RequestIDs ties the audit entries of a request together. ID is always made up on our side, as
+// clients can send anything, ClientID is whatever ID the client or a proxy in front of us sent along.
+type RequestIDs struct {
+	ID, ClientID string
+}
+
+type AuditEntry struct {
+	ActorID         string
+	Action          string
+	RequestID       string
+	ClientRequestID string // Only for matching up with logs outside the server, it is not checked in any way
+	GameID          string // Empty for actions that are not about a single game
+	Details         string
+	Occurred        time.Time
+
+	// The game before and after the action. New games have nothing before them, and the data store
+	// sets up their board, so both are nil for those.
+	Before, After *AuditedGame
+}
+
+// AuditedGame is the part of a game that actions change.
+type AuditedGame struct {
+	State                    State
+	SerializedGame           uint64
+	PlayerOneID, PlayerTwoID string
+	WinnerID                 string
+}
+
+// AuditSink keeps a record of who did what, for sorting out disputes after the fact. Entries are
+// only ever added, never changed or removed.
+type AuditSink interface {
+	Record(entry *AuditEntry) error
+}
+
+type AuditDataStore interface {
+	AddAuditEntry(entry *AuditEntry) error
+	// AuditEntries returns the newest entries first, an empty gameID or actorID matches every entry.
+	AuditEntries(gameID string, actorID string, offset int, count int) ([]*AuditEntry, error)
+}
+
+// DataStoreAuditSink keeps the audit log in a data store, which is what the admin endpoints read it from.
+type DataStoreAuditSink struct {
+	ds AuditDataStore
+}
+
+func NewDataStoreAuditSink(ds AuditDataStore) *DataStoreAuditSink {
+	return &DataStoreAuditSink{ds: ds}
+}
+
+func (dss *DataStoreAuditSink) Record(entry *AuditEntry) error {
+	return dss.ds.AddAuditEntry(entry)
+}
+
+// JSONLinesAuditSink writes every entry as a line of JSON, for servers without an audit data store
+// or for shipping the log somewhere else.
+type JSONLinesAuditSink struct {
+	mutex  sync.Mutex
+	writer io.Writer
+}
+
+func NewJSONLinesAuditSink(writer io.Writer) *JSONLinesAuditSink {
+	return &JSONLinesAuditSink{writer: writer}
+}
+
+// OpenJSONLinesAuditSink appends to the file at path, creating it if it does not exist.
+func OpenJSONLinesAuditSink(path string) (*JSONLinesAuditSink, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return NewJSONLinesAuditSink(file), nil
+}
+
+func (jls *JSONLinesAuditSink) Record(entry *AuditEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	// A single write per entry keeps lines from several goroutines from getting mixed up
+	jls.mutex.Lock()
+	defer jls.mutex.Unlock()
+	_, err = jls.writer.Write(append(line, '\n'))
+	return err
+}
+
+// NewRequestIDs makes up an ID for a request, keeping the ID the client sent along next to it.
+func NewRequestIDs(clientID string) RequestIDs {
+	if len(clientID) > MAX_REQUEST_ID_LENGTH {
+		clientID = ""
+	}
+	return RequestIDs{ID: NewRequestID(), ClientID: clientID}
+}
+
+func NewRequestID() string {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		// Only used to tie audit entries together, so a clock based ID will do
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(id)
+}
+
+// The action has already happened when it is recorded, so a failure to record it is only logged
+// rather than failing the request.
+func recordAudit(sink AuditSink, requestIDs RequestIDs, entry *AuditEntry) {
+	entry.RequestID = requestIDs.ID
+	entry.ClientRequestID = requestIDs.ClientID
+	entry.Occurred = time.Now()
+	if err := sink.Record(entry); err != nil {
+		fmt.Printf("Error recording %v by %v in the audit log: %v\n", entry.Action, entry.ActorID, err)
+	}
+}
+
+// Takes a copy, as the game keeps changing after the entry has been made
+func auditedGame(game *Game) *AuditedGame {
+	return &AuditedGame{State: game.State, SerializedGame: game.SerializedGame, PlayerOneID: game.PlayerOneID,
+		PlayerTwoID: game.PlayerTwoID, WinnerID: game.WinnerID}
+}
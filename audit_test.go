@@ -0,0 +1,127 @@
+package neutrinoapi_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	api "github.com/Morras/neutrinoapi"
+	"github.com/Morras/neutrinoapi/spy"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+var _ = Describe("Audit", func() {
+
+	audited := func(serializedGame uint64) *api.AuditedGame {
+		return &api.AuditedGame{State: api.PLAYING, SerializedGame: serializedGame, PlayerOneID: "TestUserId", PlayerTwoID: "opponent id"}
+	}
+
+	Context("DataStoreAuditSink", func() {
+		It("Should add every entry to the data store", func() {
+			dataStoreSpy := &spy.AuditDataStoreSpy{}
+			sink := api.NewDataStoreAuditSink(dataStoreSpy)
+			entry := &api.AuditEntry{ActorID: "TestUserId", Action: api.AUDIT_MAKE_MOVE}
+			Expect(sink.Record(entry)).To(BeNil())
+			Expect(dataStoreSpy.AddAuditEntryEntries).To(Equal([]*api.AuditEntry{entry}))
+		})
+
+		It("Should return errors from the data store", func() {
+			dataStoreSpy := &spy.AuditDataStoreSpy{AddAuditEntryErr: errors.New("error adding entry")}
+			sink := api.NewDataStoreAuditSink(dataStoreSpy)
+			Expect(sink.Record(&api.AuditEntry{})).ToNot(BeNil())
+		})
+	})
+
+	Context("JSONLinesAuditSink", func() {
+		It("Should write every entry as a line of JSON", func() {
+			buffer := &bytes.Buffer{}
+			sink := api.NewJSONLinesAuditSink(buffer)
+			occurred := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+			sink.Record(&api.AuditEntry{ActorID: "TestUserId", Action: api.AUDIT_MAKE_MOVE, RequestID: "TestRequestId",
+				ClientRequestID: "client request id", GameID: "game id", Occurred: occurred, Before: audited(1), After: audited(2)})
+			sink.Record(&api.AuditEntry{ActorID: "TestUserId", Action: api.AUDIT_NEW_GAME, GameID: "other game id"})
+
+			lines := strings.Split(strings.TrimSuffix(buffer.String(), "\n"), "\n")
+			Expect(len(lines)).To(BeIdenticalTo(2))
+
+			entry := &api.AuditEntry{}
+			Expect(json.Unmarshal([]byte(lines[0]), entry)).To(BeNil())
+			Expect(entry.RequestID).To(BeIdenticalTo("TestRequestId"))
+			Expect(entry.ClientRequestID).To(BeIdenticalTo("client request id"))
+			Expect(entry.Occurred.Equal(occurred)).To(BeTrue())
+			Expect(entry.Before).To(Equal(audited(1)))
+			Expect(entry.After).To(Equal(audited(2)))
+
+			entry = &api.AuditEntry{}
+			Expect(json.Unmarshal([]byte(lines[1]), entry)).To(BeNil())
+			Expect(entry.Action).To(BeIdenticalTo(api.AUDIT_NEW_GAME))
+			Expect(entry.Before).To(BeNil())
+		})
+
+		It("Should not mix up lines recorded at the same time", func() {
+			buffer := &bytes.Buffer{}
+			sink := api.NewJSONLinesAuditSink(buffer)
+			var wg sync.WaitGroup
+			for i := 0; i < 20; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					sink.Record(&api.AuditEntry{ActorID: strings.Repeat("a", 1000), Action: api.AUDIT_MAKE_MOVE})
+				}()
+			}
+			wg.Wait()
+
+			lines := strings.Split(strings.TrimSuffix(buffer.String(), "\n"), "\n")
+			Expect(len(lines)).To(BeIdenticalTo(20))
+			for _, line := range lines {
+				Expect(json.Unmarshal([]byte(line), &api.AuditEntry{})).To(BeNil())
+			}
+		})
+
+		It("Should append to an existing audit log file", func() {
+			dir, err := ioutil.TempDir("", "audit")
+			Expect(err).To(BeNil())
+			defer os.RemoveAll(dir)
+			path := filepath.Join(dir, "audit.jsonl")
+			Expect(ioutil.WriteFile(path, []byte("{}\n"), 0600)).To(BeNil())
+
+			sink, err := api.OpenJSONLinesAuditSink(path)
+			Expect(err).To(BeNil())
+			Expect(sink.Record(&api.AuditEntry{Action: api.AUDIT_RESIGN})).To(BeNil())
+
+			content, _ := ioutil.ReadFile(path)
+			lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+			Expect(len(lines)).To(BeIdenticalTo(2))
+			Expect(lines[0]).To(BeIdenticalTo("{}"))
+			Expect(lines[1]).To(ContainSubstring(api.AUDIT_RESIGN))
+		})
+	})
+
+	Context("NewRequestID", func() {
+		It("Should make up a different ID every time", func() {
+			first := api.NewRequestID()
+			Expect(first).ToNot(BeEmpty())
+			Expect(api.NewRequestID()).ToNot(BeIdenticalTo(first))
+		})
+	})
+
+	Context("NewRequestIDs", func() {
+		It("Should make up its own ID and keep the one from the client next to it", func() {
+			ids := api.NewRequestIDs("client request id")
+			Expect(ids.ID).ToNot(BeEmpty())
+			Expect(ids.ID).ToNot(BeIdenticalTo("client request id"))
+			Expect(ids.ClientID).To(BeIdenticalTo("client request id"))
+		})
+
+		It("Should leave out client IDs that are too long", func() {
+			ids := api.NewRequestIDs(strings.Repeat("a", api.MAX_REQUEST_ID_LENGTH+1))
+			Expect(ids.ClientID).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,155 @@
+package auth
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var ErrInvalidToken = errors.New("Invalid token.")
+var ErrMissingSubject = errors.New("Token does not say who it was issued to.")
+var ErrTokenFromFuture = errors.New("Token was issued in the future.")
+var ErrExpiredToken = errors.New("Token has expired.")
+var ErrWrongIssuer = errors.New("Token was issued by someone else.")
+var ErrWrongAudience = errors.New("Token was issued for someone else.")
+var ErrUnsupportedAlgorithm = errors.New("Token is signed with an unsupported algorithm.")
+
+// Real tokens are around a kilobyte, anything much bigger is not worth decoding.
+const maxTokenLength = 8192
+
+// Clocks on the identity provider and on our servers are never quite in sync.
+const clockSkew = time.Minute
+
+// Authenticator checks that a token was issued by a provider we trust, and returns the claims in it.
+type Authenticator interface {
+	Authenticate(token string) (*Claims, error)
+}
+
+type Claims struct {
+	Subject   string
+	Issuer    string
+	Audience  []string
+	ExpiresAt time.Time
+	IssuedAt  time.Time
+	// All holds every claim in the token, including the ones specific to a provider.
+	All map[string]interface{}
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid,omitempty"`
+}
+
+type jwtClaims struct {
+	Sub string   `json:"sub"`
+	Iss string   `json:"iss"`
+	Aud audience `json:"aud"`
+	Exp int64    `json:"exp"`
+	Iat int64    `json:"iat"`
+}
+
+// audience is a single string in most tokens, but the JWT spec also allows a list.
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*a = list
+	return nil
+}
+
+// jwt is a token split into its parts, before the signature has been checked.
+type jwt struct {
+	header       *jwtHeader
+	claims       *Claims
+	signingInput string
+	signature    []byte
+}
+
+func parseJWT(token string) (*jwt, error) {
+	if len(token) > maxTokenLength {
+		return nil, ErrInvalidToken
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidToken
+	}
+
+	header := &jwtHeader{}
+	if err := decodeSegment(parts[0], header); err != nil {
+		return nil, err
+	}
+
+	known := &jwtClaims{}
+	if err := decodeSegment(parts[1], known); err != nil {
+		return nil, err
+	}
+	all := map[string]interface{}{}
+	if err := decodeSegment(parts[1], &all); err != nil {
+		return nil, err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	claims := &Claims{Subject: known.Sub, Issuer: known.Iss, Audience: known.Aud, All: all}
+	if known.Exp != 0 {
+		claims.ExpiresAt = time.Unix(known.Exp, 0)
+	}
+	if known.Iat != 0 {
+		claims.IssuedAt = time.Unix(known.Iat, 0)
+	}
+	return &jwt{header: header, claims: claims, signingInput: parts[0] + "." + parts[1], signature: signature}, nil
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return ErrInvalidToken
+	}
+	if err = json.Unmarshal(data, v); err != nil {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+// checkClaims makes sure the token names a user, is still valid and is meant for us. Empty issuer
+// or audience skip that check.
+func checkClaims(claims *Claims, issuer string, audience string, now time.Time) error {
+	if strings.TrimSpace(claims.Subject) == "" {
+		return ErrMissingSubject
+	}
+	if claims.ExpiresAt.IsZero() || !now.Before(claims.ExpiresAt) {
+		return ErrExpiredToken
+	}
+	if claims.IssuedAt.IsZero() || claims.IssuedAt.After(now.Add(clockSkew)) {
+		return ErrTokenFromFuture
+	}
+	if issuer != "" && claims.Issuer != issuer {
+		return ErrWrongIssuer
+	}
+	if audience != "" && !claims.hasAudience(audience) {
+		return ErrWrongAudience
+	}
+	return nil
+}
+
+func (c *Claims) hasAudience(audience string) bool {
+	for _, aud := range c.Audience {
+		if aud == audience {
+			return true
+		}
+	}
+	return false
+}
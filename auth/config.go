@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"errors"
+	fjv "github.com/Morras/firebaseJwtValidator"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const FIREBASE_PROVIDER = "firebase"
+const OIDC_PROVIDER = "oidc"
+const HMAC_PROVIDER = "hmac"
+const DEV_PROVIDER = "dev" // Never in production, see DevAuthenticator
+
+// Environment variables read by ConfigFromEnv
+const ENV_PROVIDER = "NEUTRINO_AUTH_PROVIDER"
+const ENV_FIREBASE_PROJECT_ID = "NEUTRINO_AUTH_FIREBASE_PROJECT_ID"
+const ENV_JWKS_URL = "NEUTRINO_AUTH_JWKS_URL"
+const ENV_ISSUER = "NEUTRINO_AUTH_ISSUER"
+const ENV_AUDIENCE = "NEUTRINO_AUTH_AUDIENCE"
+const ENV_HMAC_SECRET = "NEUTRINO_AUTH_HMAC_SECRET"
+const ENV_PRODUCTION = "NEUTRINO_PRODUCTION"
+
+const jwksFetchTimeout = 10 * time.Second
+
+// Config picks the authentication provider and holds the settings it needs, the others are ignored.
+type Config struct {
+	Provider          string
+	FirebaseProjectID string
+	JWKSURL           string
+	Issuer            string
+	Audience          string
+	HMACSecret        string
+	Production        bool
+}
+
+// ConfigFromEnv reads the config from the environment, using Firebase with the given project when
+// nothing else has been configured.
+func ConfigFromEnv(defaultFirebaseProjectID string) *Config {
+	config := &Config{
+		Provider:          strings.ToLower(os.Getenv(ENV_PROVIDER)),
+		FirebaseProjectID: os.Getenv(ENV_FIREBASE_PROJECT_ID),
+		JWKSURL:           os.Getenv(ENV_JWKS_URL),
+		Issuer:            os.Getenv(ENV_ISSUER),
+		Audience:          os.Getenv(ENV_AUDIENCE),
+		HMACSecret:        os.Getenv(ENV_HMAC_SECRET),
+		Production:        true,
+	}
+	// Anything but a clear no counts as production, so a typo or a forgotten variable cannot turn on
+	// development authentication
+	if production := os.Getenv(ENV_PRODUCTION); production != "" {
+		isProduction, err := strconv.ParseBool(production)
+		config.Production = err != nil || isProduction
+	}
+	if config.Provider == "" {
+		config.Provider = FIREBASE_PROVIDER
+	}
+	if config.FirebaseProjectID == "" {
+		config.FirebaseProjectID = defaultFirebaseProjectID
+	}
+	return config
+}
+
+func NewAuthenticator(config *Config) (Authenticator, error) {
+	switch config.Provider {
+	case FIREBASE_PROVIDER:
+		if config.FirebaseProjectID == "" {
+			return nil, errors.New("Firebase authentication needs a project id")
+		}
+		return NewFirebaseAuthenticator(fjv.NewDefaultTokenValidator(config.FirebaseProjectID), config.FirebaseProjectID), nil
+	case OIDC_PROVIDER:
+		if config.JWKSURL == "" || config.Issuer == "" || config.Audience == "" {
+			return nil, errors.New("OIDC authentication needs a JWKS URL, an issuer and an audience")
+		}
+		return NewOIDCAuthenticator(config.JWKSURL, config.Issuer, config.Audience, &http.Client{Timeout: jwksFetchTimeout}), nil
+	case HMAC_PROVIDER:
+		if config.HMACSecret == "" {
+			return nil, errors.New("HMAC authentication needs a secret")
+		}
+		return NewHMACAuthenticator([]byte(config.HMACSecret), config.Issuer, config.Audience), nil
+	case DEV_PROVIDER:
+		authenticator, err := NewDevAuthenticator(config.Production)
+		if err != nil {
+			return nil, err
+		}
+		return authenticator, nil
+	}
+	return nil, errors.New("Unknown authentication provider " + config.Provider)
+}
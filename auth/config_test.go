@@ -0,0 +1,53 @@
+package auth_test
+
+import (
+	"github.com/Morras/neutrinoapi/auth"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"os"
+)
+
+var _ = Describe("Config", func() {
+
+	AfterEach(func() {
+		os.Unsetenv(auth.ENV_PROVIDER)
+		os.Unsetenv(auth.ENV_HMAC_SECRET)
+	})
+
+	It("Should use Firebase with the default project when nothing is configured", func() {
+		config := auth.ConfigFromEnv("default-project")
+		Expect(config.Provider).To(BeIdenticalTo(auth.FIREBASE_PROVIDER))
+		Expect(config.FirebaseProjectID).To(BeIdenticalTo("default-project"))
+	})
+
+	It("Should pick the provider from the environment", func() {
+		os.Setenv(auth.ENV_PROVIDER, "HMAC")
+		os.Setenv(auth.ENV_HMAC_SECRET, "test secret")
+		authenticator, err := auth.NewAuthenticator(auth.ConfigFromEnv("default-project"))
+		Expect(err).To(BeNil())
+		Expect(authenticator).To(BeAssignableToTypeOf(&auth.HMACAuthenticator{}))
+	})
+
+	It("Should create an OIDC authenticator", func() {
+		authenticator, err := auth.NewAuthenticator(&auth.Config{Provider: auth.OIDC_PROVIDER,
+			JWKSURL: "https://idp.example.com/jwks", Issuer: "https://idp.example.com", Audience: "neutrino"})
+		Expect(err).To(BeNil())
+		Expect(authenticator).To(BeAssignableToTypeOf(&auth.OIDCAuthenticator{}))
+	})
+
+	It("Should refuse to create authenticators that are missing settings", func() {
+		for _, config := range []*auth.Config{
+			{Provider: auth.FIREBASE_PROVIDER},
+			{Provider: auth.OIDC_PROVIDER, JWKSURL: "https://idp.example.com/jwks"},
+			{Provider: auth.HMAC_PROVIDER},
+		} {
+			_, err := auth.NewAuthenticator(config)
+			Expect(err).ToNot(BeNil())
+		}
+	})
+
+	It("Should refuse unknown providers", func() {
+		_, err := auth.NewAuthenticator(&auth.Config{Provider: "carrier pigeon"})
+		Expect(err).ToNot(BeNil())
+	})
+})
@@ -0,0 +1,96 @@
+package auth_test
+
+import (
+	"github.com/Morras/neutrinoapi/auth"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"os"
+	"time"
+)
+
+var _ = Describe("DevAuthenticator", func() {
+
+	var authenticator *auth.DevAuthenticator
+
+	BeforeEach(func() {
+		authenticator, _ = auth.NewDevAuthenticator(false)
+	})
+
+	It("Should refuse to be created in production", func() {
+		authenticator, err := auth.NewDevAuthenticator(true)
+		Expect(authenticator).To(BeNil())
+		Expect(err).To(BeIdenticalTo(auth.ErrDevAuthInProduction))
+	})
+
+	It("Should accept a plain user ID", func() {
+		claims, err := authenticator.Authenticate("player-1")
+		Expect(err).To(BeNil())
+		Expect(claims.Subject).To(BeIdenticalTo("player-1"))
+	})
+
+	It("Should accept tokens signed with the bundled key", func() {
+		token, _ := auth.SignDevToken("player-2", time.Hour, map[string]interface{}{"admin": true})
+		claims, err := authenticator.Authenticate(token)
+		Expect(err).To(BeNil())
+		Expect(claims.Subject).To(BeIdenticalTo("player-2"))
+		Expect(claims.All["admin"]).To(BeTrue())
+	})
+
+	It("Should reject expired development tokens", func() {
+		token, _ := auth.SignDevToken("player-2", -time.Minute, nil)
+		_, err := authenticator.Authenticate(token)
+		Expect(err).To(BeIdenticalTo(auth.ErrExpiredToken))
+	})
+
+	It("Should reject tokens signed with any other key", func() {
+		token, _ := auth.SignHMACToken([]byte("other secret"), map[string]interface{}{"sub": "player-3", "iss": auth.DEV_TOKEN_ISSUER})
+		_, err := authenticator.Authenticate(token)
+		Expect(err).To(BeIdenticalTo(auth.ErrInvalidToken))
+	})
+
+	It("Should not let anyone act as a bot", func() {
+		_, err := authenticator.Authenticate("neutrino-bot-medium")
+		Expect(err).To(BeIdenticalTo(auth.ErrInvalidToken))
+		token, _ := auth.SignDevToken("neutrino-bot-medium", time.Hour, nil)
+		_, err = authenticator.Authenticate(token)
+		Expect(err).To(BeIdenticalTo(auth.ErrInvalidToken))
+	})
+
+	It("Should reject empty user IDs", func() {
+		_, err := authenticator.Authenticate("")
+		Expect(err).ToNot(BeNil())
+	})
+
+	Context("Given it is configured from the environment", func() {
+		BeforeEach(func() {
+			os.Setenv(auth.ENV_PROVIDER, auth.DEV_PROVIDER)
+		})
+
+		AfterEach(func() {
+			os.Unsetenv(auth.ENV_PROVIDER)
+			os.Unsetenv(auth.ENV_PRODUCTION)
+		})
+
+		It("Should refuse to be created when the production flag is not set", func() {
+			authenticator, err := auth.NewAuthenticator(auth.ConfigFromEnv("default-project"))
+			Expect(authenticator).To(BeNil())
+			Expect(err).To(BeIdenticalTo(auth.ErrDevAuthInProduction))
+		})
+
+		It("Should be created when the production flag is clearly off", func() {
+			os.Setenv(auth.ENV_PRODUCTION, "false")
+			authenticator, err := auth.NewAuthenticator(auth.ConfigFromEnv("default-project"))
+			Expect(err).To(BeNil())
+			Expect(authenticator).To(BeAssignableToTypeOf(&auth.DevAuthenticator{}))
+		})
+
+		It("Should refuse to be created when the production flag is set", func() {
+			for _, value := range []string{"true", "1", "yes please"} {
+				os.Setenv(auth.ENV_PRODUCTION, value)
+				authenticator, err := auth.NewAuthenticator(auth.ConfigFromEnv("default-project"))
+				Expect(authenticator).To(BeNil())
+				Expect(err).To(BeIdenticalTo(auth.ErrDevAuthInProduction))
+			}
+		})
+	})
+})
@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"fmt"
+	fjv "github.com/Morras/firebaseJwtValidator"
+	"time"
+)
+
+const firebaseIssuerPrefix = "https://securetoken.google.com/"
+
+// FirebaseAuthenticator accepts the ID tokens Firebase Authentication issues for one project.
+type FirebaseAuthenticator struct {
+	validator fjv.TokenValidator
+	projectID string
+}
+
+func NewFirebaseAuthenticator(validator fjv.TokenValidator, projectID string) *FirebaseAuthenticator {
+	return &FirebaseAuthenticator{validator: validator, projectID: projectID}
+}
+
+func (fa *FirebaseAuthenticator) Authenticate(token string) (*Claims, error) {
+	valid, err := fa.validator.Validate(token)
+	if err != nil {
+		fmt.Printf("Error validating Firebase token: %v\n", err)
+		return nil, ErrInvalidToken
+	}
+	if !valid {
+		return nil, ErrInvalidToken
+	}
+
+	parsed, err := parseJWT(token)
+	if err != nil {
+		return nil, err
+	}
+	// The validator checks these as well, but it is not ours, and a user ID missing from the token
+	// would otherwise make every request without one act as the same empty user
+	if err = checkClaims(parsed.claims, firebaseIssuerPrefix+fa.projectID, fa.projectID, time.Now()); err != nil {
+		return nil, err
+	}
+	return parsed.claims, nil
+}
@@ -0,0 +1,67 @@
+package auth_test
+
+import (
+	"errors"
+	"github.com/Morras/neutrinoapi/auth"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"time"
+)
+
+type validatorStub struct {
+	valid bool
+	err   error
+}
+
+func (v *validatorStub) Validate(token string) (bool, error) {
+	return v.valid, v.err
+}
+
+var _ = Describe("FirebaseAuthenticator", func() {
+
+	const projectID = "test-project"
+
+	var claims map[string]interface{}
+
+	BeforeEach(func() {
+		claims = map[string]interface{}{
+			"sub": "1234567890",
+			"iss": "https://securetoken.google.com/" + projectID,
+			"aud": projectID,
+			"exp": time.Now().Add(time.Hour).Unix(),
+			"iat": time.Now().Unix(),
+		}
+	})
+
+	It("Should return the claims of a token the validator accepts", func() {
+		authenticator := auth.NewFirebaseAuthenticator(&validatorStub{valid: true}, projectID)
+		// The signature is for the validator to check, which is stubbed out here
+		token, _ := auth.SignHMACToken([]byte("not checked"), claims)
+		parsed, err := authenticator.Authenticate(token)
+		Expect(err).To(BeNil())
+		Expect(parsed.Subject).To(BeIdenticalTo("1234567890"))
+	})
+
+	It("Should reject a token the validator rejects", func() {
+		authenticator := auth.NewFirebaseAuthenticator(&validatorStub{valid: false, err: errors.New("Validator error")}, projectID)
+		token, _ := auth.SignHMACToken([]byte("not checked"), claims)
+		_, err := authenticator.Authenticate(token)
+		Expect(err).To(BeIdenticalTo(auth.ErrInvalidToken))
+	})
+
+	It("Should reject a token without a subject even if the validator accepts it", func() {
+		authenticator := auth.NewFirebaseAuthenticator(&validatorStub{valid: true}, projectID)
+		delete(claims, "sub")
+		token, _ := auth.SignHMACToken([]byte("not checked"), claims)
+		_, err := authenticator.Authenticate(token)
+		Expect(err).To(BeIdenticalTo(auth.ErrMissingSubject))
+	})
+
+	It("Should reject a token for another project even if the validator accepts it", func() {
+		authenticator := auth.NewFirebaseAuthenticator(&validatorStub{valid: true}, projectID)
+		claims["aud"] = "other-project"
+		token, _ := auth.SignHMACToken([]byte("not checked"), claims)
+		_, err := authenticator.Authenticate(token)
+		Expect(err).To(BeIdenticalTo(auth.ErrWrongAudience))
+	})
+})
@@ -0,0 +1,104 @@
+package auth_test
+
+import (
+	"github.com/Morras/neutrinoapi/auth"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"time"
+)
+
+var _ = Describe("HMACAuthenticator", func() {
+
+	secret := []byte("test secret")
+
+	var authenticator *auth.HMACAuthenticator
+	var claims map[string]interface{}
+
+	BeforeEach(func() {
+		authenticator = auth.NewHMACAuthenticator(secret, "neutrino-tests", "neutrino")
+		claims = map[string]interface{}{
+			"sub": "TestUserId",
+			"iss": "neutrino-tests",
+			"aud": "neutrino",
+			"exp": time.Now().Add(time.Hour).Unix(),
+			"iat": time.Now().Unix(),
+		}
+	})
+
+	It("Should accept a token signed with the secret", func() {
+		token, _ := auth.SignHMACToken(secret, claims)
+		parsed, err := authenticator.Authenticate(token)
+		Expect(err).To(BeNil())
+		Expect(parsed.Subject).To(BeIdenticalTo("TestUserId"))
+		Expect(parsed.Audience).To(Equal([]string{"neutrino"}))
+		Expect(parsed.All["sub"]).To(Equal("TestUserId"))
+	})
+
+	It("Should accept a list of audiences", func() {
+		claims["aud"] = []string{"someone else", "neutrino"}
+		token, _ := auth.SignHMACToken(secret, claims)
+		_, err := authenticator.Authenticate(token)
+		Expect(err).To(BeNil())
+	})
+
+	It("Should reject a token signed with another secret", func() {
+		token, _ := auth.SignHMACToken([]byte("other secret"), claims)
+		_, err := authenticator.Authenticate(token)
+		Expect(err).To(BeIdenticalTo(auth.ErrInvalidToken))
+	})
+
+	It("Should reject an expired token", func() {
+		claims["exp"] = time.Now().Add(-time.Minute).Unix()
+		token, _ := auth.SignHMACToken(secret, claims)
+		_, err := authenticator.Authenticate(token)
+		Expect(err).To(BeIdenticalTo(auth.ErrExpiredToken))
+	})
+
+	It("Should reject a token without an expiry", func() {
+		delete(claims, "exp")
+		token, _ := auth.SignHMACToken(secret, claims)
+		_, err := authenticator.Authenticate(token)
+		Expect(err).To(BeIdenticalTo(auth.ErrExpiredToken))
+	})
+
+	It("Should reject a token without a subject", func() {
+		claims["sub"] = ""
+		token, _ := auth.SignHMACToken(secret, claims)
+		_, err := authenticator.Authenticate(token)
+		Expect(err).To(BeIdenticalTo(auth.ErrMissingSubject))
+	})
+
+	It("Should reject a token issued in the future", func() {
+		claims["iat"] = time.Now().Add(time.Hour).Unix()
+		token, _ := auth.SignHMACToken(secret, claims)
+		_, err := authenticator.Authenticate(token)
+		Expect(err).To(BeIdenticalTo(auth.ErrTokenFromFuture))
+	})
+
+	It("Should allow for clocks being a little out of sync", func() {
+		claims["iat"] = time.Now().Add(10 * time.Second).Unix()
+		token, _ := auth.SignHMACToken(secret, claims)
+		_, err := authenticator.Authenticate(token)
+		Expect(err).To(BeNil())
+	})
+
+	It("Should reject a token from another issuer", func() {
+		claims["iss"] = "someone else"
+		token, _ := auth.SignHMACToken(secret, claims)
+		_, err := authenticator.Authenticate(token)
+		Expect(err).To(BeIdenticalTo(auth.ErrWrongIssuer))
+	})
+
+	It("Should reject a token for another audience", func() {
+		claims["aud"] = "someone else"
+		token, _ := auth.SignHMACToken(secret, claims)
+		_, err := authenticator.Authenticate(token)
+		Expect(err).To(BeIdenticalTo(auth.ErrWrongAudience))
+	})
+
+	It("Should reject tokens that are not signed with HS256", func() {
+		// {"alg":"none"}.{"sub":"TestUserId"}.
+		_, err := authenticator.Authenticate("eyJhbGciOiJub25lIn0.eyJzdWIiOiJUZXN0VXNlcklkIn0.")
+		Expect(err).To(BeIdenticalTo(auth.ErrUnsupportedAlgorithm))
+	})
+})
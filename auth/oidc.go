@@ -0,0 +1,156 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Identity providers rotate their keys now and then, but a token with an unknown key id should not
+// let anyone make us fetch the keys on every request.
+const jwksMinRefreshInterval = 5 * time.Minute
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+// OIDCAuthenticator accepts RS256 tokens from any OpenID Connect provider, checking them against
+// the public keys the provider publishes at its JWKS URL.
+type OIDCAuthenticator struct {
+	jwksURL  string
+	issuer   string
+	audience string
+	client   *http.Client
+
+	mutex       sync.RWMutex
+	keys        map[string]*rsa.PublicKey
+	lastFetched time.Time
+	fetching    chan struct{} // Closed once the fetch in progress is done, nil when nothing is being fetched
+}
+
+func NewOIDCAuthenticator(jwksURL string, issuer string, audience string, client *http.Client) *OIDCAuthenticator {
+	return &OIDCAuthenticator{jwksURL: jwksURL, issuer: issuer, audience: audience, client: client, keys: map[string]*rsa.PublicKey{}}
+}
+
+func (oa *OIDCAuthenticator) Authenticate(token string) (*Claims, error) {
+	parsed, err := parseJWT(token)
+	if err != nil {
+		return nil, err
+	}
+	if parsed.header.Alg != "RS256" {
+		return nil, ErrUnsupportedAlgorithm
+	}
+
+	key, err := oa.key(parsed.header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	hashed := sha256.Sum256([]byte(parsed.signingInput))
+	if err = rsa.VerifyPKCS1v15(key, crypto.SHA256, hashed[:], parsed.signature); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if err = checkClaims(parsed.claims, oa.issuer, oa.audience, time.Now()); err != nil {
+		return nil, err
+	}
+	return parsed.claims, nil
+}
+
+// The keys are fetched without holding the lock, so a slow provider only holds up the requests
+// with unknown key ids, and those wait for the fetch in progress rather than starting their own.
+func (oa *OIDCAuthenticator) key(kid string) (*rsa.PublicKey, error) {
+	if key, err := oa.cachedKey(kid); err == nil {
+		return key, nil
+	}
+
+	oa.mutex.Lock()
+	if key, found := oa.keys[kid]; found {
+		oa.mutex.Unlock()
+		return key, nil
+	}
+	if done := oa.fetching; done != nil {
+		oa.mutex.Unlock()
+		<-done
+		return oa.cachedKey(kid)
+	}
+	if time.Since(oa.lastFetched) < jwksMinRefreshInterval {
+		oa.mutex.Unlock()
+		return nil, ErrInvalidToken
+	}
+	// Failed fetches count too, or a provider that is down would be asked again on every request
+	oa.lastFetched = time.Now()
+	done := make(chan struct{})
+	oa.fetching = done
+	oa.mutex.Unlock()
+
+	keys, err := oa.fetchKeys()
+
+	oa.mutex.Lock()
+	if err == nil {
+		oa.keys = keys
+	}
+	oa.fetching = nil
+	close(done)
+	oa.mutex.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+	return oa.cachedKey(kid)
+}
+
+func (oa *OIDCAuthenticator) cachedKey(kid string) (*rsa.PublicKey, error) {
+	oa.mutex.RLock()
+	defer oa.mutex.RUnlock()
+	if key, found := oa.keys[kid]; found {
+		return key, nil
+	}
+	return nil, ErrInvalidToken
+}
+
+func (oa *OIDCAuthenticator) fetchKeys() (map[string]*rsa.PublicKey, error) {
+	resp, err := oa.client.Get(oa.jwksURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Fetching keys from %v responded with status %v", oa.jwksURL, resp.StatusCode)
+	}
+
+	set := &jsonWebKeySet{}
+	if err = json.NewDecoder(resp.Body).Decode(set); err != nil {
+		return nil, err
+	}
+
+	keys := map[string]*rsa.PublicKey{}
+	for _, jwk := range set.Keys {
+		if jwk.Kty != "RSA" {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		keys[jwk.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+	return keys, nil
+}
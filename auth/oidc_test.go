@@ -0,0 +1,155 @@
+package auth_test
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/Morras/neutrinoapi/auth"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+)
+
+func signRS256(key *rsa.PrivateKey, kid string, claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": kid})
+	payload, _ := json.Marshal(claims)
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	hashed := sha256.Sum256([]byte(signingInput))
+	signature, _ := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hashed[:])
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func jwksFor(kid string, key *rsa.PublicKey) string {
+	return fmt.Sprintf(`{"keys": [{"kty": "RSA", "kid": "%v", "n": "%v", "e": "%v"}]}`, kid,
+		base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()))
+}
+
+var _ = Describe("OIDCAuthenticator", func() {
+
+	const issuer = "https://idp.example.com"
+	const audience = "neutrino"
+
+	var key *rsa.PrivateKey
+	var fetches int
+	var failing bool
+	var jwksServer *httptest.Server
+	var authenticator *auth.OIDCAuthenticator
+	var claims map[string]interface{}
+
+	BeforeEach(func() {
+		key, _ = rsa.GenerateKey(rand.Reader, 2048)
+		fetches = 0
+		failing = false
+		jwksServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fetches++
+			if failing {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			// Slow enough for requests arriving together to find the fetch still going
+			time.Sleep(20 * time.Millisecond)
+			fmt.Fprint(w, jwksFor("key one", &key.PublicKey))
+		}))
+		authenticator = auth.NewOIDCAuthenticator(jwksServer.URL, issuer, audience, jwksServer.Client())
+		claims = map[string]interface{}{
+			"sub": "TestUserId",
+			"iss": issuer,
+			"aud": audience,
+			"exp": time.Now().Add(time.Hour).Unix(),
+			"iat": time.Now().Unix(),
+		}
+	})
+
+	AfterEach(func() {
+		jwksServer.Close()
+	})
+
+	It("Should accept a token signed with one of the providers keys", func() {
+		parsed, err := authenticator.Authenticate(signRS256(key, "key one", claims))
+		Expect(err).To(BeNil())
+		Expect(parsed.Subject).To(BeIdenticalTo("TestUserId"))
+	})
+
+	It("Should only fetch the keys once", func() {
+		authenticator.Authenticate(signRS256(key, "key one", claims))
+		authenticator.Authenticate(signRS256(key, "key one", claims))
+		Expect(fetches).To(BeIdenticalTo(1))
+	})
+
+	It("Should reject a token signed with another key", func() {
+		otherKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+		_, err := authenticator.Authenticate(signRS256(otherKey, "key one", claims))
+		Expect(err).To(BeIdenticalTo(auth.ErrInvalidToken))
+	})
+
+	It("Should not fetch the keys again for every unknown key id", func() {
+		authenticator.Authenticate(signRS256(key, "key one", claims))
+		_, err := authenticator.Authenticate(signRS256(key, "unknown key", claims))
+		Expect(err).To(BeIdenticalTo(auth.ErrInvalidToken))
+		authenticator.Authenticate(signRS256(key, "unknown key", claims))
+		Expect(fetches).To(BeIdenticalTo(1))
+	})
+
+	It("Should reject a token from another issuer", func() {
+		claims["iss"] = "https://evil.example.com"
+		_, err := authenticator.Authenticate(signRS256(key, "key one", claims))
+		Expect(err).To(BeIdenticalTo(auth.ErrWrongIssuer))
+	})
+
+	It("Should reject a token for another audience", func() {
+		claims["aud"] = "someone else"
+		_, err := authenticator.Authenticate(signRS256(key, "key one", claims))
+		Expect(err).To(BeIdenticalTo(auth.ErrWrongAudience))
+	})
+
+	It("Should reject an expired token", func() {
+		claims["exp"] = time.Now().Add(-time.Minute).Unix()
+		_, err := authenticator.Authenticate(signRS256(key, "key one", claims))
+		Expect(err).To(BeIdenticalTo(auth.ErrExpiredToken))
+	})
+
+	It("Should reject tokens signed with a shared secret", func() {
+		token, _ := auth.SignHMACToken([]byte("secret"), claims)
+		_, err := authenticator.Authenticate(token)
+		Expect(err).To(BeIdenticalTo(auth.ErrUnsupportedAlgorithm))
+	})
+
+	It("Should return an error if the keys cannot be fetched", func() {
+		jwksServer.Close()
+		_, err := authenticator.Authenticate(signRS256(key, "key one", claims))
+		Expect(err).ToNot(BeNil())
+	})
+
+	It("Should not fetch the keys again right after failing to", func() {
+		failing = true
+		authenticator.Authenticate(signRS256(key, "key one", claims))
+		_, err := authenticator.Authenticate(signRS256(key, "key one", claims))
+		Expect(err).To(BeIdenticalTo(auth.ErrInvalidToken))
+		Expect(fetches).To(BeIdenticalTo(1))
+	})
+
+	It("Should fetch the keys once for requests that arrive together", func() {
+		token := signRS256(key, "key one", claims)
+		var wg sync.WaitGroup
+		errs := make([]error, 5)
+		for i := range errs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, errs[i] = authenticator.Authenticate(token)
+			}(i)
+		}
+		wg.Wait()
+		Expect(errs).To(Equal(make([]error, 5)))
+		Expect(fetches).To(BeIdenticalTo(1))
+	})
+})
@@ -0,0 +1,76 @@
+package bot
+
+import (
+	"github.com/Morras/go-neutrino/game"
+	api "github.com/Morras/neutrinoapi"
+)
+
+// How many turns ahead the bot looks on each difficulty
+var searchPlies = map[api.BotDifficulty]int{
+	api.EASY:   1,
+	api.MEDIUM: 2,
+	api.HARD:   3,
+}
+
+// The bot plays inside the players request, so every search stops deepening once it has tried this
+// many moves. Searching one turn ahead is never limited, so EASY has no budget.
+var turnNodeBudgets = map[api.BotDifficulty]int{
+	api.MEDIUM: api.BOT_MEDIUM_NODE_BUDGET,
+	api.HARD:   api.BOT_HARD_NODE_BUDGET,
+}
+
+// MinimaxBot searches the game tree with alpha-beta pruning, asking a game controller about every
+// move it tries so the rules stay in go-neutrino. Analysing positions does not hold up anyone's move,
+// so it gets a budget of its own.
+type MinimaxBot struct {
+	newController  func() game.GameController
+	analysisBudget int
+}
+
+func NewMinimaxBot(newController func() game.GameController, analysisBudget int) *MinimaxBot {
+	return &MinimaxBot{newController: newController, analysisBudget: analysisBudget}
+}
+
+func (b *MinimaxBot) NextTurn(g *game.Game, difficulty api.BotDifficulty) (*api.MakeMoveRequest, error) {
+	plies, found := searchPlies[difficulty]
+	if !found {
+		difficulty = api.EASY
+		plies = searchPlies[api.EASY]
+	}
+
+	result, err := b.Search(g, plies, turnNodeBudgets[difficulty])
+	if err != nil {
+		return nil, err
+	}
+	// The API always expects a neutrino move, which the first turn of the game does not have
+	if !result.Turn.NeutrinoMoved {
+		return nil, ErrNoLegalTurn
+	}
+	return result.Turn.MakeMoveRequest(), nil
+}
+
+func (t *Turn) MakeMoveRequest() *api.MakeMoveRequest {
+	return &api.MakeMoveRequest{
+		NeutrinoFromX: t.NeutrinoMove.FromX, NeutrinoFromY: t.NeutrinoMove.FromY,
+		NeutrinoToX: t.NeutrinoMove.ToX, NeutrinoToY: t.NeutrinoMove.ToY,
+		PieceFromX: t.PieceMove.FromX, PieceFromY: t.PieceMove.FromY,
+		PieceToX: t.PieceMove.ToX, PieceToY: t.PieceMove.ToY,
+	}
+}
+
+// Analyze lets the bot review positions, using the same search it plays with.
+func (b *MinimaxBot) Analyze(g *game.Game, plies int) (*api.Analysis, error) {
+	result, err := b.Search(g, plies, b.analysisBudget)
+	if err != nil {
+		return nil, err
+	}
+	return &api.Analysis{
+		BestTurn:         result.Turn.MakeMoveRequest(),
+		Score:            result.Score,
+		ForcedWin:        result.ForcedWin,
+		ForcedLoss:       result.ForcedLoss,
+		WinningCondition: result.WinningCondition,
+		Plies:            result.Plies,
+		Depth:            result.Depth,
+	}, nil
+}
@@ -0,0 +1,149 @@
+package bot_test
+
+import (
+	"github.com/Morras/go-neutrino/game"
+	api "github.com/Morras/neutrinoapi"
+	"github.com/Morras/neutrinoapi/bot"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// Counts the moves the search tries
+type countingController struct {
+	game.GameController
+	tried *int
+}
+
+func (cc *countingController) MakeMove(move game.Move) (game.State, error) {
+	*cc.tried++
+	return cc.GameController.MakeMove(move)
+}
+
+var _ = Describe("MinimaxBot", func() {
+
+	var minimaxBot *bot.MinimaxBot
+
+	newController := func() game.GameController {
+		return &game.Controller{}
+	}
+
+	// Plays the turn with the real rules, failing the test if any part of it is illegal
+	playTurn := func(g *game.Game, turn *bot.Turn) game.State {
+		controller := newController()
+		controller.PlayGame(g)
+		state := g.State
+		var err error
+		if turn.NeutrinoMoved {
+			state, err = controller.MakeMove(game.NewMove(turn.NeutrinoMove.FromX, turn.NeutrinoMove.FromY, turn.NeutrinoMove.ToX, turn.NeutrinoMove.ToY))
+			Expect(err).To(BeNil())
+		}
+		if turn.PieceMoved {
+			state, err = controller.MakeMove(game.NewMove(turn.PieceMove.FromX, turn.PieceMove.FromY, turn.PieceMove.ToX, turn.PieceMove.ToY))
+			Expect(err).To(BeNil())
+		}
+		return state
+	}
+
+	// The bot plays both sides for a few turns, stopping before the game is over
+	midgame := func() *game.Game {
+		g := game.NewStandardGame()
+		for turn := 0; turn < 8; turn++ {
+			result, err := minimaxBot.Search(g, 2, api.BOT_MEDIUM_NODE_BUDGET)
+			Expect(err).To(BeNil())
+			next := game.UInt64ToGame(game.GameToUInt64(g))
+			if state := playTurn(next, result.Turn); state == game.Player1Win || state == game.Player2Win {
+				break
+			}
+			g = next
+		}
+		return g
+	}
+
+	BeforeEach(func() {
+		minimaxBot = bot.NewMinimaxBot(newController, api.ANALYSIS_NODE_BUDGET)
+	})
+
+	Context("Search", func() {
+		for _, plies := range []int{1, 2} {
+			plies := plies
+			It("Should find a legal turn from the starting position", func() {
+				result, err := minimaxBot.Search(game.NewStandardGame(), plies, api.BOT_HARD_NODE_BUDGET)
+				Expect(err).To(BeNil())
+				Expect(result.Turn).ToNot(BeNil())
+				Expect(result.Turn.PieceMoved).To(BeTrue())
+				playTurn(game.NewStandardGame(), result.Turn)
+			})
+		}
+
+		It("Should fall back to a shallower search when it runs out of nodes", func() {
+			tried := 0
+			counting := func() game.GameController {
+				return &countingController{GameController: newController(), tried: &tried}
+			}
+			bot.NewMinimaxBot(counting, 0).Search(game.NewStandardGame(), 1, 0)
+			onePly := tried
+
+			tried = 0
+			result, err := bot.NewMinimaxBot(counting, 0).Search(game.NewStandardGame(), 3, 10)
+			Expect(err).To(BeNil())
+			Expect(result.Turn).ToNot(BeNil())
+			Expect(result.Depth).To(BeIdenticalTo(1))
+			Expect(tried).To(BeNumerically("<=", onePly+10))
+		})
+
+		It("Should search as deep as MEDIUM and HARD play from a midgame position within their budgets", func() {
+			g := midgame()
+			for plies, budget := range map[int]int{2: api.BOT_MEDIUM_NODE_BUDGET, 3: api.BOT_HARD_NODE_BUDGET} {
+				result, err := minimaxBot.Search(g, plies, budget)
+				Expect(err).To(BeNil())
+				// Deepening stops early, within the budget, once the game is decided
+				if !result.ForcedWin && !result.ForcedLoss {
+					Expect(result.Depth).To(BeIdenticalTo(plies))
+				}
+			}
+		})
+
+		It("Should not change the game it searches", func() {
+			g := game.NewStandardGame()
+			before := game.GameToUInt64(g)
+			minimaxBot.Search(g, 2, api.BOT_MEDIUM_NODE_BUDGET)
+			Expect(game.GameToUInt64(g)).To(BeIdenticalTo(before))
+		})
+	})
+
+	Context("Analyze", func() {
+		It("Should report the best turn and score found by the search", func() {
+			analysis, err := minimaxBot.Analyze(game.NewStandardGame(), 1)
+			Expect(err).To(BeNil())
+			Expect(analysis.BestTurn).ToNot(BeNil())
+			Expect(analysis.ForcedLoss).To(BeFalse())
+		})
+
+		It("Should report how deep the search got", func() {
+			analysis, err := minimaxBot.Analyze(game.NewStandardGame(), 2)
+			Expect(err).To(BeNil())
+			Expect(analysis.Depth).To(BeIdenticalTo(2))
+
+			analysis, err = bot.NewMinimaxBot(newController, 10).Analyze(game.NewStandardGame(), 2)
+			Expect(err).To(BeNil())
+			Expect(analysis.Depth).To(BeIdenticalTo(1))
+		})
+
+		It("Should fail for a game that is already over", func() {
+			g := game.NewStandardGame()
+			g.State = game.Player1Win
+			_, err := minimaxBot.Analyze(g, 1)
+			Expect(err).To(BeIdenticalTo(bot.ErrNoLegalTurn))
+		})
+	})
+
+	Context("NextTurn", func() {
+		It("Should turn the best turn into a move request", func() {
+			g := game.NewStandardGame()
+			g.State = game.Player2NeutrinoMove
+			result, err := minimaxBot.NextTurn(g, api.EASY)
+			Expect(err).To(BeNil())
+			Expect(result).ToNot(BeNil())
+		})
+	})
+})
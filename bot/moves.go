@@ -0,0 +1,199 @@
+package bot
+
+import "github.com/Morras/go-neutrino/game"
+
+const boardSize = 5
+const piecesPerPlayer = 5
+
+var directions = [8][2]int{{0, 1}, {1, 1}, {1, 0}, {1, -1}, {0, -1}, {-1, -1}, {-1, 0}, {-1, 1}}
+
+// Move keeps the coordinates of a move around, game.Move is only used to talk to the controller.
+type Move struct {
+	FromX, FromY, ToX, ToY byte
+}
+
+func (m Move) gameMove() game.Move {
+	return game.NewMove(m.FromX, m.FromY, m.ToX, m.ToY)
+}
+
+type square struct {
+	x, y int
+}
+
+// position is a game together with where its pieces are. The bot only knows the rules through the
+// game controller, so it keeps track of the pieces itself rather than try every square on the board.
+type position struct {
+	game                 *game.Game
+	neutrino             square
+	playerOne, playerTwo []square // Can hold squares that turned out to be empty, no move from those is legal
+}
+
+// halfMove is either a neutrino move or a piece move, together with the game it results in.
+type halfMove struct {
+	move  Move
+	game  *game.Game
+	state game.State
+}
+
+// movesFrom asks the controller about every move the piece on the square could make. Pieces always
+// slide as far as they can, so only the longest legal move in each direction is kept.
+func (s *search) movesFrom(g *game.Game, from square) []halfMove {
+	moves := []halfMove{}
+	for _, direction := range directions {
+		for distance := boardSize - 1; distance > 0; distance-- {
+			toX, toY := from.x+direction[0]*distance, from.y+direction[1]*distance
+			if !onBoard(toX, toY) {
+				continue
+			}
+			move := Move{FromX: byte(from.x), FromY: byte(from.y), ToX: byte(toX), ToY: byte(toY)}
+			if result, state, legal := s.tryMove(g, move); legal {
+				moves = append(moves, halfMove{move: move, game: result, state: state})
+				break
+			}
+		}
+	}
+	return moves
+}
+
+// pieceMoves only tries the pieces of the player to move.
+func (p *position) pieceMoves(s *search) []halfMove {
+	pieces := p.playerTwo
+	if isPlayerOnesTurn(p.game.State) {
+		pieces = p.playerOne
+	}
+	moves := []halfMove{}
+	for _, piece := range pieces {
+		moves = append(moves, s.movesFrom(p.game, piece)...)
+	}
+	return moves
+}
+
+func (p *position) afterNeutrinoMove(move halfMove) *position {
+	return &position{game: move.game, neutrino: square{int(move.move.ToX), int(move.move.ToY)}, playerOne: p.playerOne, playerTwo: p.playerTwo}
+}
+
+func (p *position) afterPieceMove(move halfMove) *position {
+	after := &position{game: move.game, neutrino: p.neutrino, playerOne: p.playerOne, playerTwo: p.playerTwo}
+	from, to := square{int(move.move.FromX), int(move.move.FromY)}, square{int(move.move.ToX), int(move.move.ToY)}
+	if isPlayerOnesTurn(p.game.State) {
+		after.playerOne = moved(p.playerOne, from, to)
+	} else {
+		after.playerTwo = moved(p.playerTwo, from, to)
+	}
+	return after
+}
+
+func moved(pieces []square, from square, to square) []square {
+	after := make([]square, len(pieces))
+	for i, piece := range pieces {
+		after[i] = piece
+		if piece == from {
+			after[i] = to
+		}
+	}
+	return after
+}
+
+// readPosition finds the pieces once at the start of a search, by asking the controller about every
+// square as if each kind of piece was to move. Pieces that cannot move at all do not show up that way,
+// but something has to stop every slide, so those are found as the squares slides stop in front of.
+// Returns false if the neutrino cannot move, which in a neutrino move means the game is over.
+func (s *search) readPosition(g *game.Game) (*position, bool) {
+	p := &position{game: g}
+	foundNeutrino := false
+	owned := map[square]bool{}
+	occupied := map[square]bool{}
+	for x := 0; x < boardSize; x++ {
+		for y := 0; y < boardSize; y++ {
+			from := square{x, y}
+			for _, state := range []game.State{game.Player1NeutrinoMove, game.Player1Move, game.Player2Move} {
+				moves := s.movesFrom(withState(g, state), from)
+				if len(moves) == 0 {
+					continue
+				}
+				owned[from] = true
+				switch state {
+				case game.Player1NeutrinoMove:
+					p.neutrino, foundNeutrino = from, true
+				case game.Player1Move:
+					p.playerOne = append(p.playerOne, from)
+				case game.Player2Move:
+					p.playerTwo = append(p.playerTwo, from)
+				}
+				for _, move := range moves {
+					dx, dy := sign(int(move.move.ToX)-x), sign(int(move.move.ToY)-y)
+					if stopX, stopY := int(move.move.ToX)+dx, int(move.move.ToY)+dy; onBoard(stopX, stopY) {
+						occupied[square{stopX, stopY}] = true
+					}
+				}
+			}
+		}
+	}
+
+	// Whose the stuck pieces are is not known, so they are tried for both players
+	for stuck := range occupied {
+		if owned[stuck] {
+			continue
+		}
+		if len(p.playerOne) < piecesPerPlayer {
+			p.playerOne = append(p.playerOne, stuck)
+		}
+		if len(p.playerTwo) < piecesPerPlayer {
+			p.playerTwo = append(p.playerTwo, stuck)
+		}
+	}
+	return p, foundNeutrino
+}
+
+func withState(g *game.Game, state game.State) *game.Game {
+	copied := copyGame(g)
+	copied.State = state
+	return copied
+}
+
+func onBoard(x int, y int) bool {
+	return x >= 0 && y >= 0 && x < boardSize && y < boardSize
+}
+
+func sign(value int) int {
+	switch {
+	case value > 0:
+		return 1
+	case value < 0:
+		return -1
+	}
+	return 0
+}
+
+// The controller is reused for every move tried, playing a copy of the game each time starts it
+// over without touching the games found so far.
+func (s *search) tryMove(g *game.Game, move Move) (*game.Game, game.State, bool) {
+	if s.limited && s.nodesLeft <= 0 {
+		s.outOfNodes = true
+		return nil, g.State, false
+	}
+	s.nodesLeft--
+
+	s.controller.PlayGame(copyGame(g))
+	state, err := s.controller.MakeMove(move.gameMove())
+	if err != nil {
+		return nil, state, false
+	}
+	return s.controller.Game(), state, true
+}
+
+func copyGame(g *game.Game) *game.Game {
+	return game.UInt64ToGame(game.GameToUInt64(g))
+}
+
+func isNeutrinoMove(state game.State) bool {
+	return state == game.Player1NeutrinoMove || state == game.Player2NeutrinoMove
+}
+
+func isPlayerOnesTurn(state game.State) bool {
+	return state == game.Player1NeutrinoMove || state == game.Player1Move
+}
+
+func isGameOver(state game.State) bool {
+	return state == game.Player1Win || state == game.Player2Win
+}
@@ -0,0 +1,164 @@
+package bot
+
+import (
+	"errors"
+	"github.com/Morras/go-neutrino/game"
+	api "github.com/Morras/neutrinoapi"
+	"math/rand"
+)
+
+const winScore = 1000000
+const mobilityWeight = 10
+
+var ErrNoLegalTurn = errors.New("There is no legal turn to play")
+
+// Turn is a full turn for one player. The first turn of a game has no neutrino move, and a
+// neutrino move that wins the game right away has no piece move.
+type Turn struct {
+	NeutrinoMove, PieceMove   Move
+	NeutrinoMoved, PieceMoved bool
+	position                  *position
+	state                     game.State
+	winningCondition          api.WinningCondition
+}
+
+// Result is the outcome of searching a position, seen from the player to move.
+type Result struct {
+	Turn             *Turn
+	Score            int
+	ForcedWin        bool
+	ForcedLoss       bool
+	WinningCondition api.WinningCondition // Only meaningful for forced wins and losses
+	Plies            int                  // Turns until the game ends, for forced wins and losses
+	Depth            int                  // Turns searched, less than asked for if the node budget ran out first
+}
+
+// search holds the state of a single search, so one bot can search several games at once.
+type search struct {
+	controller game.GameController
+	nodesLeft  int
+	limited    bool // The one ply search is never limited, or the bot could end up with nothing to play
+	outOfNodes bool
+}
+
+// Search looks plies turns ahead, one ply deeper at a time. If the node budget runs out, the result
+// of the deepest search that finished is used instead, which the Depth of the result tells.
+func (b *MinimaxBot) Search(g *game.Game, plies int, nodeBudget int) (*Result, error) {
+	if isGameOver(g.State) {
+		return nil, ErrNoLegalTurn
+	}
+	s := &search{controller: b.newController(), nodesLeft: nodeBudget}
+	root, found := s.readPosition(g)
+	if !found && isNeutrinoMove(g.State) {
+		return nil, ErrNoLegalTurn
+	}
+
+	var best *Result
+	for depth := 1; depth <= plies; depth++ {
+		s.limited = depth > 1
+		result := s.negamax(root, depth, -winScore-1, winScore+1, true)
+		if s.outOfNodes {
+			break
+		}
+		best = result
+		best.Depth = depth
+		// Looking deeper does not change a forced result
+		if best.ForcedWin || best.ForcedLoss {
+			break
+		}
+	}
+	if best == nil || best.Turn == nil {
+		return nil, ErrNoLegalTurn
+	}
+	return best, nil
+}
+
+func (s *search) negamax(p *position, plies int, alpha int, beta int, root bool) *Result {
+	turns := s.turns(p)
+	if root {
+		// Shuffling means the bot does not play the exact same game every time among equally good turns
+		rand.Shuffle(len(turns), func(i, j int) { turns[i], turns[j] = turns[j], turns[i] })
+	}
+
+	playerOneToMove := isPlayerOnesTurn(p.game.State)
+	var best *Result
+	for _, turn := range turns {
+		if s.outOfNodes {
+			break
+		}
+		var result *Result
+		if isGameOver(turn.state) {
+			won := (turn.state == game.Player1Win) == playerOneToMove
+			result = &Result{Score: -winScore + 1, ForcedLoss: true, WinningCondition: turn.winningCondition, Plies: 1}
+			if won {
+				result = &Result{Score: winScore - 1, ForcedWin: true, WinningCondition: turn.winningCondition, Plies: 1}
+			}
+		} else if plies <= 1 {
+			result = &Result{Score: -s.evaluate(turn.position)}
+		} else {
+			reply := s.negamax(turn.position, plies-1, -beta, -alpha, false)
+			result = &Result{Score: -reply.Score, ForcedWin: reply.ForcedLoss, ForcedLoss: reply.ForcedWin, WinningCondition: reply.WinningCondition}
+			if reply.ForcedWin || reply.ForcedLoss {
+				result.Plies = reply.Plies + 1
+				// Prefer the quickest win and the slowest loss
+				if result.Score > 0 {
+					result.Score--
+				} else {
+					result.Score++
+				}
+			}
+		}
+		result.Turn = turn
+
+		if best == nil || result.Score > best.Score {
+			best = result
+		}
+		if best.Score > alpha {
+			alpha = best.Score
+		}
+		if alpha >= beta {
+			break
+		}
+	}
+
+	if best == nil {
+		// No legal turn means the player to move is trapped
+		return &Result{Score: -winScore, ForcedLoss: true, WinningCondition: api.TRAP}
+	}
+	return best
+}
+
+// evaluate scores a position that is not over for the player to move. The more ways the player
+// has to move the neutrino, the harder it is to trap them.
+func (s *search) evaluate(p *position) int {
+	if !isNeutrinoMove(p.game.State) {
+		return 0
+	}
+	return mobilityWeight * len(s.movesFrom(p.game, p.neutrino))
+}
+
+func (s *search) turns(p *position) []*Turn {
+	turns := []*Turn{}
+
+	if !isNeutrinoMove(p.game.State) {
+		for _, pieceMove := range p.pieceMoves(s) {
+			turns = append(turns, &Turn{PieceMove: pieceMove.move, PieceMoved: true, position: p.afterPieceMove(pieceMove),
+				state: pieceMove.state, winningCondition: api.TRAP})
+		}
+		return turns
+	}
+
+	for _, neutrinoMove := range s.movesFrom(p.game, p.neutrino) {
+		moved := p.afterNeutrinoMove(neutrinoMove)
+		if isGameOver(neutrinoMove.state) {
+			turns = append(turns, &Turn{NeutrinoMove: neutrinoMove.move, NeutrinoMoved: true, position: moved,
+				state: neutrinoMove.state, winningCondition: api.BACK_LINE})
+			continue
+		}
+		for _, pieceMove := range moved.pieceMoves(s) {
+			turns = append(turns, &Turn{NeutrinoMove: neutrinoMove.move, PieceMove: pieceMove.move, NeutrinoMoved: true, PieceMoved: true,
+				position: moved.afterPieceMove(pieceMove), state: pieceMove.state, winningCondition: api.TRAP})
+		}
+	}
+	return turns
+}
@@ -0,0 +1,74 @@
+package neutrinoapi
+
+import (
+	"fmt"
+	"time"
+)
+
+// BotFallback hands games that have waited too long for a human opponent to a bot, so players are
+// never left waiting for good. It is meant to be run on a schedule, every BOT_FALLBACK_INTERVAL, so a
+// player waits somewhere between after and after plus the interval. The game keeps its ID, and the
+// player hears about the bot joining like they would about anyone else.
+type BotFallback struct {
+	ds        GameDataStore
+	after     time.Duration
+	publisher GameEventPublisher
+	audit     AuditSink
+}
+
+func NewBotFallback(ds GameDataStore, after time.Duration, publisher GameEventPublisher, audit AuditSink) *BotFallback {
+	return &BotFallback{ds: ds, after: after, publisher: publisher, audit: audit}
+}
+
+// SubstituteBots returns how many games a bot joined.
+func (bf *BotFallback) SubstituteBots(requestIDs RequestIDs) (int, error) {
+	substituted := 0
+	// Games a bot joins are no longer waiting, so only the games left waiting move the offset along
+	offset := 0
+	for {
+		games, err := bf.ds.GamesByState(INITIALIZING, offset, BOT_FALLBACK_BATCH_SIZE)
+		if err != nil {
+			return substituted, err
+		}
+
+		for _, game := range games {
+			if !bf.waitedTooLong(game) {
+				offset++
+				continue
+			}
+			if err = bf.substituteBot(requestIDs, game); err != nil {
+				// One game that cannot be saved should not keep the bot from the rest
+				fmt.Printf("Error handing game %v to a bot: %v\n", game.GameID, err)
+				offset++
+				continue
+			}
+			substituted++
+		}
+
+		if len(games) < BOT_FALLBACK_BATCH_SIZE {
+			return substituted, nil
+		}
+	}
+}
+
+// Private games are waiting for a friend, so they never get a bot
+func (bf *BotFallback) waitedTooLong(game *Game) bool {
+	return game.State == INITIALIZING && game.PlayerTwoID == "" && game.InviteCode == "" && time.Since(game.CreatedAt) >= bf.after
+}
+
+func (bf *BotFallback) substituteBot(requestIDs RequestIDs, game *Game) error {
+	// Same race as joining a game, a human joining right now would be overwritten by the bot
+	before := auditedGame(game)
+	game.PlayerTwoID = BotUserID(BOT_FALLBACK_DIFFICULTY)
+	game.State = PLAYING
+	game.BotPlayed = true
+	game.Version++
+	event := newGameEvent(PLAYER_JOINED, game.PlayerTwoID, game)
+	if err := bf.ds.UpdateGame(game, event); err != nil {
+		return err
+	}
+	publishGameEvent(bf.publisher, event)
+	recordAudit(bf.audit, requestIDs, &AuditEntry{ActorID: game.PlayerTwoID, Action: AUDIT_BOT_SUBSTITUTED, GameID: game.GameID,
+		Details: "waited since " + game.CreatedAt.Format(time.RFC3339), Before: before, After: auditedGame(game)})
+	return nil
+}
@@ -0,0 +1,98 @@
+package neutrinoapi_test
+
+import (
+	"errors"
+	api "github.com/Morras/neutrinoapi"
+	"github.com/Morras/neutrinoapi/spy"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"time"
+)
+
+var _ = Describe("botFallback", func() {
+
+	testUserID := "TestUserId"
+	testRequestID := api.RequestIDs{ID: "TestRequestId"}
+	botID := api.BotUserID(api.BOT_FALLBACK_DIFFICULTY)
+
+	var gameDataStoreSpy *spy.GameDataStoreSpy
+	var publisherSpy *spy.GameEventPublisherSpy
+	var auditSpy *spy.AuditSinkSpy
+	var fallback *api.BotFallback
+
+	BeforeEach(func() {
+		gameDataStoreSpy = &spy.GameDataStoreSpy{}
+		publisherSpy = &spy.GameEventPublisherSpy{}
+		auditSpy = &spy.AuditSinkSpy{}
+		fallback = api.NewBotFallback(gameDataStoreSpy, time.Minute, publisherSpy, auditSpy)
+	})
+
+	Context("SubstituteBots method", func() {
+
+		It("Should let a bot join games that have waited too long", func() {
+			oldGame := &api.Game{GameID: "old game", PlayerOneID: testUserID, State: api.INITIALIZING, CreatedAt: time.Now().Add(-2 * time.Minute)}
+			gameDataStoreSpy.GamesByStateReturn = []*api.Game{oldGame}
+
+			substituted, err := fallback.SubstituteBots(testRequestID)
+			Expect(err).To(BeNil())
+			Expect(substituted).To(BeIdenticalTo(1))
+			Expect(gameDataStoreSpy.GamesByStateState).To(BeIdenticalTo(api.INITIALIZING))
+			Expect(gameDataStoreSpy.UpdateGameGame).To(BeIdenticalTo(oldGame))
+			Expect(oldGame.PlayerTwoID).To(BeIdenticalTo(botID))
+			Expect(oldGame.State).To(BeIdenticalTo(api.PLAYING))
+			Expect(oldGame.BotPlayed).To(BeTrue())
+			Expect(oldGame.Version).To(BeIdenticalTo(1))
+		})
+
+		It("Should save the event with the game and tell the waiting player", func() {
+			gameDataStoreSpy.GamesByStateReturn = []*api.Game{{GameID: "old game", PlayerOneID: testUserID, State: api.INITIALIZING, CreatedAt: time.Now().Add(-time.Hour)}}
+			fallback.SubstituteBots(testRequestID)
+			Expect(gameDataStoreSpy.UpdateGameEvents).To(Equal(publisherSpy.PublishEvents))
+			Expect(publisherSpy.PublishEvents[0].Type).To(BeIdenticalTo(api.PLAYER_JOINED))
+			Expect(publisherSpy.PublishEvents[0].UserID).To(BeIdenticalTo(botID))
+			Expect(publisherSpy.PublishEvents[0].Game.GameID).To(BeIdenticalTo("old game"))
+		})
+
+		It("Should record the bot joining in the audit log", func() {
+			gameDataStoreSpy.GamesByStateReturn = []*api.Game{{GameID: "old game", PlayerOneID: testUserID, State: api.INITIALIZING, CreatedAt: time.Now().Add(-time.Hour)}}
+			fallback.SubstituteBots(testRequestID)
+			Expect(auditSpy.RecordEntries[0].Action).To(BeIdenticalTo(api.AUDIT_BOT_SUBSTITUTED))
+			Expect(auditSpy.RecordEntries[0].ActorID).To(BeIdenticalTo(botID))
+			Expect(auditSpy.RecordEntries[0].RequestID).To(BeIdenticalTo(testRequestID.ID))
+			Expect(auditSpy.RecordEntries[0].Before.PlayerTwoID).To(BeIdenticalTo(""))
+			Expect(auditSpy.RecordEntries[0].After.PlayerTwoID).To(BeIdenticalTo(botID))
+		})
+
+		It("Should leave games that have not waited long enough alone", func() {
+			gameDataStoreSpy.GamesByStateReturn = []*api.Game{{GameID: "recent game", PlayerOneID: testUserID, State: api.INITIALIZING, CreatedAt: time.Now()}}
+			substituted, _ := fallback.SubstituteBots(testRequestID)
+			Expect(substituted).To(BeIdenticalTo(0))
+			Expect(gameDataStoreSpy.UpdateGameGame).To(BeNil())
+		})
+
+		It("Should never hand private games to a bot", func() {
+			gameDataStoreSpy.GamesByStateReturn = []*api.Game{{GameID: "private game", PlayerOneID: testUserID, State: api.INITIALIZING, InviteCode: "ABC234", CreatedAt: time.Now().Add(-time.Hour)}}
+			fallback.SubstituteBots(testRequestID)
+			Expect(gameDataStoreSpy.UpdateGameGame).To(BeNil())
+		})
+
+		It("Should keep going if a bot cannot join one of the games", func() {
+			gameDataStoreSpy.GamesByStateReturn = []*api.Game{
+				{GameID: "first game", PlayerOneID: testUserID, State: api.INITIALIZING, CreatedAt: time.Now().Add(-time.Hour)},
+				{GameID: "second game", PlayerOneID: "other player", State: api.INITIALIZING, CreatedAt: time.Now().Add(-time.Hour)},
+			}
+			gameDataStoreSpy.UpdateGameErr = errors.New("Error updating game")
+			substituted, err := fallback.SubstituteBots(testRequestID)
+			Expect(err).To(BeNil())
+			Expect(substituted).To(BeIdenticalTo(0))
+			Expect(gameDataStoreSpy.UpdateGameGame.GameID).To(BeIdenticalTo("second game"))
+			Expect(publisherSpy.PublishEvents).To(BeEmpty())
+		})
+
+		It("Should return an error if the waiting games cannot be looked up", func() {
+			gameDataStoreSpy.GamesByStateErr = errors.New("Error getting games")
+			_, err := fallback.SubstituteBots(testRequestID)
+			Expect(err).ToNot(BeNil())
+		})
+	})
+})
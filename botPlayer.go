@@ -0,0 +1,56 @@
+package neutrinoapi
+
+import (
+	"errors"
+	"github.com/Morras/go-neutrino/game"
+	"strings"
+)
+
+type BotDifficulty int8
+
+const (
+	EASY BotDifficulty = iota
+	MEDIUM
+	HARD
+)
+
+var botDifficultyNames = map[BotDifficulty]string{
+	EASY:   "easy",
+	MEDIUM: "medium",
+	HARD:   "hard",
+}
+
+// BotPlayer plays the turns of the server side opponent.
+type BotPlayer interface {
+	// NextTurn returns the turn the bot wants to play for whoever is to move in the game.
+	NextTurn(g *game.Game, difficulty BotDifficulty) (*MakeMoveRequest, error)
+}
+
+func ParseBotDifficulty(name string) (BotDifficulty, error) {
+	for difficulty, difficultyName := range botDifficultyNames {
+		if strings.EqualFold(name, difficultyName) {
+			return difficulty, nil
+		}
+	}
+	return EASY, errors.New("Unknown bot difficulty " + name)
+}
+
+// Bots take part in games as players with these user IDs, so the rest of the API does not need
+// to know the difference.
+func BotUserID(difficulty BotDifficulty) string {
+	return BOT_USER_ID_PREFIX + botDifficultyNames[difficulty]
+}
+
+func botDifficulty(userID string) (BotDifficulty, bool) {
+	if !strings.HasPrefix(userID, BOT_USER_ID_PREFIX) {
+		return EASY, false
+	}
+	difficulty, err := ParseBotDifficulty(strings.TrimPrefix(userID, BOT_USER_ID_PREFIX))
+	return difficulty, err == nil
+}
+
+func isBotGame(g *Game) bool {
+	_, playerOneIsBot := botDifficulty(g.PlayerOneID)
+	_, playerTwoIsBot := botDifficulty(g.PlayerTwoID)
+	return g.BotPlayed || playerOneIsBot || playerTwoIsBot
+}
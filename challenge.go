@@ -0,0 +1,13 @@
+package neutrinoapi
+
+import "time"
+
+type Challenge struct {
+	ChallengeID, ChallengerID, ChallengedID string
+	Expires                                 time.Time
+	RematchOfGameID                         string // Set if the challenge is a rematch of a finished game
+}
+
+func (c *Challenge) expired() bool {
+	return time.Now().After(c.Expires)
+}
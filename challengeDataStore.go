@@ -0,0 +1,9 @@
+package neutrinoapi
+
+type ChallengeDataStore interface {
+	CreateChallenge(challenge *Challenge) (string, error)
+	Challenge(challengeID string) (*Challenge, error)
+	// PendingChallenges returns the challenges userID has received and not yet responded to.
+	PendingChallenges(userID string) ([]*Challenge, error)
+	DeleteChallenge(challengeID string) error
+}
@@ -0,0 +1,48 @@
+package neutrinoapi
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+type ChatMessage struct {
+	MessageID, GameID, SenderID string
+	Text                        string
+	Sent                        time.Time
+}
+
+type ChatMessageRequest struct {
+	GameID, Text string
+}
+
+// MessageFilter checks chat messages before they are stored. It can clean up the text, or reject
+// the message entirely by returning an error.
+type MessageFilter interface {
+	Filter(text string) (string, error)
+}
+
+// WordListFilter masks every word on its list with asterisks, ignoring case.
+type WordListFilter struct {
+	words *regexp.Regexp
+}
+
+func NewWordListFilter(words []string) *WordListFilter {
+	if len(words) == 0 {
+		return &WordListFilter{}
+	}
+	quoted := make([]string, len(words))
+	for i, word := range words {
+		quoted[i] = regexp.QuoteMeta(word)
+	}
+	return &WordListFilter{words: regexp.MustCompile(`(?i)\b(` + strings.Join(quoted, "|") + `)\b`)}
+}
+
+func (f *WordListFilter) Filter(text string) (string, error) {
+	if f.words == nil {
+		return text, nil
+	}
+	return f.words.ReplaceAllStringFunc(text, func(word string) string {
+		return strings.Repeat("*", len([]rune(word)))
+	}), nil
+}
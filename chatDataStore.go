@@ -0,0 +1,13 @@
+package neutrinoapi
+
+type ChatDataStore interface {
+	// AddChatMessage stores the message with the rest of the game, and returns the ID it was given.
+	AddChatMessage(message *ChatMessage) (string, error)
+	// ChatMessages returns up to count messages in the game, newest first, skipping the first offset
+	// and leaving out anything sent by the excluded senders.
+	ChatMessages(gameID string, excludedSenderIDs []string, offset int, count int) ([]*ChatMessage, error)
+
+	// SetMuted records whether userID has muted their opponent in the game.
+	SetMuted(gameID string, userID string, muted bool) error
+	Muted(gameID string, userID string) (bool, error)
+}
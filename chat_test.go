@@ -0,0 +1,27 @@
+package neutrinoapi_test
+
+import (
+	api "github.com/Morras/neutrinoapi"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WordListFilter", func() {
+
+	It("Should mask words on the list whatever their case", func() {
+		filtered, err := api.NewWordListFilter([]string{"darn", "heck"}).Filter("Darn it, what the heck")
+		Expect(err).To(BeNil())
+		Expect(filtered).To(BeIdenticalTo("**** it, what the ****"))
+	})
+
+	It("Should leave words that only contain a word on the list alone", func() {
+		filtered, _ := api.NewWordListFilter([]string{"ass"}).Filter("Nice pass")
+		Expect(filtered).To(BeIdenticalTo("Nice pass"))
+	})
+
+	It("Should leave the text alone without a word list", func() {
+		filtered, err := api.NewWordListFilter(nil).Filter("Good game")
+		Expect(err).To(BeNil())
+		Expect(filtered).To(BeIdenticalTo("Good game"))
+	})
+})
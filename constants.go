@@ -1,19 +1,125 @@
 package neutrinoapi
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 // Platform config
 const FIREBASE_PROJECT_ID = "neutrino-1151"
 const DEFAULT_PORT = "5000"
+const GAME_EVENT_BUFFER_SIZE = 16
+const EVENT_STREAM_HEARTBEAT = 30 * time.Second // Keeps proxies from closing event streams that have been quiet for a while
+const LONG_POLL_TIMEOUT = 25 * time.Second     // API Gateway gives up on requests after 29 seconds
+const LONG_POLL_INTERVAL = time.Second
+const OUTBOX_RETRY_BATCH_SIZE = 50
+const OUTBOX_RETRY_AFTER = time.Minute
+const OUTBOX_RETRY_INTERVAL = time.Minute
+const EVENT_HANDLER_STREAMS = "streams" // Handler names are saved with outbox entries, so changing them makes old entries go to the handler again
+const EVENT_HANDLER_WEBHOOKS = "webhooks"
+const EVENT_HANDLER_NOTIFICATIONS = "notifications"
 const JWT_HEADER_KEY = "neutrino-user"
+const WEB_SOCKET_TOKEN_PROTOCOL = "neutrino-token"
+const ADMIN_CLAIM = "admin"                          // Custom claim that makes the user an admin when it is true
+const ADMIN_USER_IDS_ENV = "NEUTRINO_ADMIN_USER_IDS" // Comma separated user IDs that are admins regardless of their claims
+const AUDIT_LOG_FILE_ENV = "NEUTRINO_AUDIT_LOG_FILE" // The server appends its audit log to this file as JSON lines
+const REQUEST_ID_HEADER_KEY = "X-Request-ID"
+const MAX_REQUEST_ID_LENGTH = 128 // Longer request IDs from clients are left out of the audit log
+const WEBHOOK_SIGNATURE_HEADER_KEY = "X-Neutrino-Signature"
+const WEBHOOK_EVENT_HEADER_KEY = "X-Neutrino-Event"
+const WEBHOOK_MAX_ATTEMPTS = 6
+const WEBHOOK_INITIAL_BACKOFF = time.Minute // Doubled after every failed attempt
+const WEBHOOK_TIMEOUT = 5 * time.Second
+const WEBHOOK_DELIVERY_INTERVAL = 15 * time.Second
+const WEBHOOK_DELIVERY_BATCH_SIZE = 50
 
 // Gameplay config
 const MAX_ACTIVE_GAMES = 5
+const DEFAULT_RECENT_OPPONENTS_TO_AVOID = 0 // Players can be matched against anyone until the player base is big enough to be picky
+const INVITE_CODE_LENGTH = 6
+const INVITE_CODE_ALPHABET = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789" // Leaves out 0, O, 1 and I as they are easily confused
+const DEFAULT_CHALLENGE_TTL = 24 * time.Hour
+const BOT_USER_ID_PREFIX = "neutrino-bot-"
+const DEFAULT_BOT_FALLBACK_AFTER = 2 * time.Minute
+const BOT_FALLBACK_DIFFICULTY = MEDIUM
+const BOT_FALLBACK_INTERVAL = 15 * time.Second
+const BOT_FALLBACK_BATCH_SIZE = 50
+const BOT_MEDIUM_NODE_BUDGET = 200000 // Moves the bot may try per turn beyond its one turn lookahead, as it plays inside the players request
+const BOT_HARD_NODE_BUDGET = 2000000  // Open midgames take up to about 35000 moves to search two turns ahead and 400000 for three
+const ANALYSIS_DEFAULT_PLIES = 3
+const ANALYSIS_MAX_PLIES = 4
+const ANALYSIS_NODE_BUDGET = 20000000 // Open midgames take up to about 3 million moves to search four turns ahead
+const PUZZLE_MAX_PLIES = 3
+const PUZZLE_MINING_NODE_BUDGET = 5000000 // Mining runs on a schedule, so it can search well past the 400000 moves three turns may take
+const PUZZLE_RATING_PER_PLY = 200         // Puzzles start out rated higher the more turns they take to solve
+const PUZZLE_MINING_INTERVAL = time.Hour
+
+// Chat config
+const MAX_CHAT_MESSAGE_LENGTH = 200 // Counted in characters, not bytes
+const CHAT_PAGE_SIZE = 50
+
+// Notification config
+const MAX_DEVICE_TOKEN_LENGTH = 4096
+
+// Rating config
+const INITIAL_RATING = 1500
+const ELO_K_FACTOR = 32
+const LEADERBOARD_PAGE_SIZE = 25
+const LIVE_GAMES_PAGE_SIZE = 25
+const ADMIN_GAMES_PAGE_SIZE = 50
+const AUDIT_LOG_PAGE_SIZE = 100
+
+// Matchmaking config, the rating window starts narrow and widens the longer a game waits for an opponent
+const RATING_WINDOW_INITIAL = 100
+const RATING_WINDOW_GROWTH_PER_MINUTE = 50
+const RATING_WINDOW_MAX = 800
 
 // Errors
 var ErrInvalidJWT = errors.New("Invalid JWT supplied.")
 var ErrMissingJWT = errors.New("No JWT supplied.")
+var ErrInviteCodeInUse = errors.New("Invite code is already in use.")
+var ErrNotAdmin = errors.New("Only admins can do that.")
 
 // Query parameters
 const QUERY_GET_GAME_GAME_ID = "gameID"
-const QUERY_GET_GAME_INCLUDE_INACTIVE = "includeInactive"
\ No newline at end of file
+const QUERY_GET_GAME_INCLUDE_INACTIVE = "includeInactive"
+const QUERY_WAIT_FOR_GAME_GAME_ID = "gameID"
+const QUERY_WAIT_FOR_GAME_VERSION = "version"
+const QUERY_NEW_GAME_VISIBILITY = "visibility"
+const QUERY_NEW_PRIVATE_GAME_VISIBILITY = "visibility"
+const QUERY_GET_LIVE_GAMES_PAGE = "page"
+const QUERY_JOIN_PRIVATE_GAME_INVITE_CODE = "inviteCode"
+const QUERY_NEW_CHALLENGE_CHALLENGED_ID = "challengedID"
+const QUERY_RESPOND_TO_CHALLENGE_CHALLENGE_ID = "challengeID"
+const QUERY_RESPOND_TO_CHALLENGE_ACCEPT = "accept"
+const QUERY_REQUEST_REMATCH_GAME_ID = "gameID"
+const QUERY_RESIGN_GAME_ID = "gameID"
+const QUERY_GET_PROFILE_USER_ID = "userID"
+const QUERY_GET_LEADERBOARD_AROUND_ME = "aroundMe"
+const QUERY_GET_LEADERBOARD_PAGE = "page"
+const QUERY_NEW_BOT_GAME_DIFFICULTY = "difficulty"
+const QUERY_ANALYZE_GAME_ID = "gameID"
+const QUERY_ANALYZE_TURN = "turn"
+const QUERY_ANALYZE_POSITION = "position"
+const QUERY_ANALYZE_PLIES = "plies"
+const QUERY_SOLVE_PUZZLE_PUZZLE_ID = "puzzleID"
+const QUERY_GET_CHAT_MESSAGES_GAME_ID = "gameID"
+const QUERY_GET_CHAT_MESSAGES_PAGE = "page"
+const QUERY_MUTE_OPPONENT_GAME_ID = "gameID"
+const QUERY_MUTE_OPPONENT_MUTED = "muted"
+const QUERY_REGISTER_DEVICE_TOKEN = "token"
+const QUERY_REGISTER_DEVICE_PLATFORM = "platform"
+const QUERY_UNREGISTER_DEVICE_TOKEN = "token"
+const QUERY_ADMIN_GET_GAME_GAME_ID = "gameID"
+const QUERY_ADMIN_FINISH_GAME_GAME_ID = "gameID"
+const QUERY_ADMIN_FINISH_GAME_WINNER_ID = "winnerID"
+const QUERY_ADMIN_VOID_GAME_GAME_ID = "gameID"
+const QUERY_ADMIN_REASSIGN_PLAYER_GAME_ID = "gameID"
+const QUERY_ADMIN_REASSIGN_PLAYER_FROM_USER_ID = "fromUserID"
+const QUERY_ADMIN_REASSIGN_PLAYER_TO_USER_ID = "toUserID"
+const QUERY_ADMIN_LIST_GAMES_STATE = "state"
+const QUERY_ADMIN_LIST_GAMES_PAGE = "page"
+const QUERY_ADMIN_GET_AUDIT_LOG_GAME_ID = "gameID"
+const QUERY_ADMIN_GET_AUDIT_LOG_ACTOR_ID = "actorID"
+const QUERY_ADMIN_GET_AUDIT_LOG_PAGE = "page"
+const QUERY_WEB_SOCKET_TOKEN = "token"
\ No newline at end of file
@@ -1,14 +1,40 @@
 package neutrinoapi
 
+import "time"
+
+// Games should get their CreatedAt set by the data store when they are started or created.
+//
+// The methods that change games take the events about the change. The data store should add them
+// to the outbox in the same transaction as the change, so an event cannot be lost when the server
+// stops between saving a game and publishing the event. It should also fill in the OutboxEntryID
+// of every event, and the GameID of events about games it creates.
 type GameDataStore interface {
 	ActiveGames(userID string) ([]*Game, error)
 	NumberOfActiveGames(userID string) (int, error)
-	GameWaitingForPlayers() (*Game, error)
-	StartNewGame(userID string) (string, error)
-	JoinGame(userID string, gameID string) error
+	// GameWaitingForPlayers should never return a private game, or a game started by userID or by any of the excluded opponents.
+	GameWaitingForPlayers(userID string, excludedOpponentIDs []string) (*Game, error)
+	// GamesWaitingForPlayers filters like GameWaitingForPlayers, but returns every match, oldest first.
+	GamesWaitingForPlayers(userID string, excludedOpponentIDs []string) ([]*Game, error)
+	StartNewGame(userID string, visibility Visibility, events ...*GameEvent) (string, error)
+	// JoinGame should bump the Version of the game, like the endpoints do before calling UpdateGame.
+	JoinGame(userID string, gameID string, events ...*GameEvent) error
+
+	// StartPrivateGame should return ErrInviteCodeInUse if another game already uses the invite code.
+	StartPrivateGame(userID string, inviteCode string, visibility Visibility, events ...*GameEvent) (string, error)
+	GameByInviteCode(inviteCode string) (*Game, error)
+
+	// CreateGame starts a game between two specific players, it should go straight to PLAYING and only be visible to them.
+	CreateGame(playerOneID string, playerTwoID string, events ...*GameEvent) (string, error)
 
 	Game(gameID string) (*Game, error)
 	Games(userID string) ([]*Game, error)
+	// LiveGames returns up to count PUBLIC games that are PLAYING, most recently started first, skipping the first offset.
+	LiveGames(offset int, count int) ([]*Game, error)
+	// GamesByState returns up to count games in the given state, most recently created first, skipping the first offset.
+	GamesByState(state State, offset int, count int) ([]*Game, error)
+
+	UpdateGame(game *Game, events ...*GameEvent) error
 
-	UpdateGame(game *Game) error
+	// FinishedGames returns every game that became DONE after since.
+	FinishedGames(since time.Time) ([]*Game, error)
 }
@@ -0,0 +1,118 @@
+package neutrinoapi
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+type GameEventType int8
+
+const (
+	GAME_CREATED  GameEventType = iota
+	PLAYER_JOINED               // The game has both its players and can be played
+	MOVE_MADE
+	GAME_FINISHED
+	GAME_CANCELLED
+	PLAYER_REASSIGNED // An admin gave the seat of one player to another user
+)
+
+var gameEventTypeNames = map[GameEventType]string{
+	GAME_CREATED:      "gameCreated",
+	PLAYER_JOINED:     "playerJoined",
+	MOVE_MADE:         "moveMade",
+	GAME_FINISHED:     "gameFinished",
+	GAME_CANCELLED:    "gameCancelled",
+	PLAYER_REASSIGNED: "playerReassigned",
+}
+
+type GameEvent struct {
+	Type     GameEventType
+	Game     *Game  // The game as it was saved by the action that caused the event
+	UserID   string // The player, or bot, whose action caused the event
+	Occurred time.Time
+
+	OutboxEntryID string   // Filled in by the data store when the event is saved along with the game
+	HandledBy     []string // Names of the handlers that already have the event, publishing it again skips them
+}
+
+// GameEventPublisher is told about changes to games once they have been saved.
+type GameEventPublisher interface {
+	Publish(event *GameEvent) error
+}
+
+type GameEventHandler interface {
+	HandleGameEvent(event *GameEvent) error
+}
+
+// InProcessGameEventPublisher hands events to its handlers one after the other, on the goroutine
+// publishing them. Handlers should be quick, and hand anything slow off to somewhere else.
+type InProcessGameEventPublisher struct {
+	mutex    sync.RWMutex
+	handlers []namedGameEventHandler
+}
+
+type namedGameEventHandler struct {
+	name    string
+	handler GameEventHandler
+}
+
+func NewInProcessGameEventPublisher() *InProcessGameEventPublisher {
+	return &InProcessGameEventPublisher{}
+}
+
+// Subscribe adds a handler under a name that should stay the same between releases, as events
+// waiting in the outbox remember the handlers that already have them by name.
+func (p *InProcessGameEventPublisher) Subscribe(name string, handler GameEventHandler) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.handlers = append(p.handlers, namedGameEventHandler{name: name, handler: handler})
+}
+
+// Publish gives the event to every handler that does not already have it, even if some of them
+// fail, and returns the first error. Handlers that succeed are added to the HandledBy of the event,
+// so publishing it again only reaches the ones that failed.
+func (p *InProcessGameEventPublisher) Publish(event *GameEvent) error {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	var firstErr error
+	for _, named := range p.handlers {
+		if event.handledBy(named.name) {
+			continue
+		}
+		if err := named.handler.HandleGameEvent(event); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		event.HandledBy = append(event.HandledBy, named.name)
+	}
+	return firstErr
+}
+
+func (event *GameEvent) handledBy(name string) bool {
+	for _, handled := range event.HandledBy {
+		if handled == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Endpoints keep changing their games after publishing, a bot can play right after the player
+// for example, so handlers get a copy of the game as it was. Events are made before the change is
+// saved, so the data store can save them along with it.
+func newGameEvent(eventType GameEventType, userID string, game *Game) *GameEvent {
+	snapshot := *game
+	return &GameEvent{Type: eventType, Game: &snapshot, UserID: userID, Occurred: time.Now()}
+}
+
+// The change has already been saved when this is called, so a failure to publish is only logged
+// rather than failing the request.
+func publishGameEvent(publisher GameEventPublisher, event *GameEvent) {
+	if err := publisher.Publish(event); err != nil {
+		fmt.Printf("Error publishing event %v for game %v: %v\n", event.Type, event.Game.GameID, err)
+	}
+}
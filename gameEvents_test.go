@@ -0,0 +1,67 @@
+package neutrinoapi_test
+
+import (
+	"errors"
+	api "github.com/Morras/neutrinoapi"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type gameEventHandlerStub struct {
+	events []*api.GameEvent
+	err    error
+}
+
+func (h *gameEventHandlerStub) HandleGameEvent(event *api.GameEvent) error {
+	h.events = append(h.events, event)
+	return h.err
+}
+
+var _ = Describe("inProcessGameEventPublisher", func() {
+
+	var publisher *api.InProcessGameEventPublisher
+	var event *api.GameEvent
+
+	BeforeEach(func() {
+		publisher = api.NewInProcessGameEventPublisher()
+		event = &api.GameEvent{Type: api.GAME_CREATED, Game: &api.Game{GameID: "game id"}}
+	})
+
+	It("Should do nothing when there are no handlers", func() {
+		Expect(publisher.Publish(event)).To(BeNil())
+	})
+
+	It("Should give the event to every handler", func() {
+		first := &gameEventHandlerStub{}
+		second := &gameEventHandlerStub{}
+		publisher.Subscribe("first", first)
+		publisher.Subscribe("second", second)
+		Expect(publisher.Publish(event)).To(BeNil())
+		Expect(first.events).To(Equal([]*api.GameEvent{event}))
+		Expect(second.events).To(Equal([]*api.GameEvent{event}))
+	})
+
+	It("Should keep going when a handler fails and return its error", func() {
+		failing := &gameEventHandlerStub{err: errors.New("error handling event")}
+		other := &gameEventHandlerStub{}
+		publisher.Subscribe("failing", failing)
+		publisher.Subscribe("other", other)
+		Expect(publisher.Publish(event)).To(BeIdenticalTo(failing.err))
+		Expect(other.events).To(Equal([]*api.GameEvent{event}))
+	})
+
+	It("Should remember which handlers got the event and skip them when it is published again", func() {
+		failing := &gameEventHandlerStub{err: errors.New("error handling event")}
+		other := &gameEventHandlerStub{}
+		publisher.Subscribe("failing", failing)
+		publisher.Subscribe("other", other)
+		publisher.Publish(event)
+		Expect(event.HandledBy).To(Equal([]string{"other"}))
+
+		failing.err = nil
+		Expect(publisher.Publish(event)).To(BeNil())
+		Expect(len(failing.events)).To(BeIdenticalTo(2))
+		Expect(len(other.events)).To(BeIdenticalTo(1))
+		Expect(event.HandledBy).To(Equal([]string{"other", "failing"}))
+	})
+})
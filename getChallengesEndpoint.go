@@ -0,0 +1,27 @@
+package neutrinoapi
+
+import "net/http"
+
+type GetChallengesEndpoint struct {
+	cds ChallengeDataStore
+}
+
+func NewGetChallengesEndpoint(cds ChallengeDataStore) *GetChallengesEndpoint {
+	return &GetChallengesEndpoint{cds: cds}
+}
+
+func (gce *GetChallengesEndpoint) PerformAction(userID string) ([]*Challenge, int) {
+	challenges, err := gce.cds.PendingChallenges(userID)
+	if err != nil {
+		return nil, http.StatusInternalServerError
+	}
+
+	// Expired challenges are cleaned up when someone responds to them, until then we just hide them
+	pending := []*Challenge{}
+	for _, challenge := range challenges {
+		if !challenge.expired() {
+			pending = append(pending, challenge)
+		}
+	}
+	return pending, http.StatusOK
+}
@@ -0,0 +1,50 @@
+package neutrinoapi_test
+
+import (
+	"errors"
+	api "github.com/Morras/neutrinoapi"
+	"github.com/Morras/neutrinoapi/spy"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+	"time"
+)
+
+var _ = Describe("getChallengesEndpoint", func() {
+
+	testUserID := "TestUserId"
+
+	var challengeDataStoreSpy *spy.ChallengeDataStoreSpy
+	var endpoint *api.GetChallengesEndpoint
+
+	BeforeEach(func() {
+		challengeDataStoreSpy = &spy.ChallengeDataStoreSpy{}
+		endpoint = api.NewGetChallengesEndpoint(challengeDataStoreSpy)
+	})
+
+	Context("performAction method", func() {
+
+		It("Should ask the data store for the challenges sent to the user", func() {
+			endpoint.PerformAction(testUserID)
+			Expect(challengeDataStoreSpy.PendingChallengesUserID).To(BeIdenticalTo(testUserID))
+		})
+
+		It("Should only return challenges that have not expired", func() {
+			pending := &api.Challenge{ChallengeID: "pending", Expires: time.Now().Add(time.Hour)}
+			expired := &api.Challenge{ChallengeID: "expired", Expires: time.Now().Add(-time.Hour)}
+			challengeDataStoreSpy.PendingChallengesReturn = []*api.Challenge{expired, pending}
+
+			challenges, code := endpoint.PerformAction(testUserID)
+			Expect(code).To(BeIdenticalTo(http.StatusOK))
+			Expect(len(challenges)).To(BeIdenticalTo(1))
+			Expect(challenges[0]).To(BeIdenticalTo(pending))
+		})
+
+		It("Should return an internal server error if there is a problem talking with the datastore", func() {
+			challengeDataStoreSpy.PendingChallengesErr = errors.New("Error getting challenges")
+			challenges, code := endpoint.PerformAction(testUserID)
+			Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
+			Expect(challenges).To(BeEmpty())
+		})
+	})
+})
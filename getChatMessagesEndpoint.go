@@ -0,0 +1,53 @@
+package neutrinoapi
+
+import "net/http"
+
+type GetChatMessagesEndpoint struct {
+	ds  GameDataStore
+	cds ChatDataStore
+}
+
+func NewGetChatMessagesEndpoint(ds GameDataStore, cds ChatDataStore) *GetChatMessagesEndpoint {
+	return &GetChatMessagesEndpoint{ds: ds, cds: cds}
+}
+
+// PerformAction returns a page of the games chat, newest first. Players who have muted their
+// opponent only see their own messages.
+func (gce *GetChatMessagesEndpoint) PerformAction(userID string, gameID string, page int) ([]*ChatMessage, int) {
+	if page < 0 {
+		return nil, http.StatusBadRequest
+	}
+
+	dsGame, err := gce.ds.Game(gameID)
+	if err != nil {
+		return nil, http.StatusInternalServerError
+	}
+	if dsGame == nil {
+		return nil, http.StatusNotFound
+	}
+
+	var opponentID string
+	switch userID {
+	case dsGame.PlayerOneID:
+		opponentID = dsGame.PlayerTwoID
+	case dsGame.PlayerTwoID:
+		opponentID = dsGame.PlayerOneID
+	default:
+		return nil, http.StatusForbidden
+	}
+
+	muted, err := gce.cds.Muted(gameID, userID)
+	if err != nil {
+		return nil, http.StatusInternalServerError
+	}
+	excludedSenderIDs := []string{}
+	if muted {
+		excludedSenderIDs = append(excludedSenderIDs, opponentID)
+	}
+
+	messages, err := gce.cds.ChatMessages(gameID, excludedSenderIDs, page*CHAT_PAGE_SIZE, CHAT_PAGE_SIZE)
+	if err != nil {
+		return nil, http.StatusInternalServerError
+	}
+	return messages, http.StatusOK
+}
@@ -0,0 +1,70 @@
+package neutrinoapi_test
+
+import (
+	"errors"
+	api "github.com/Morras/neutrinoapi"
+	"github.com/Morras/neutrinoapi/spy"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+)
+
+var _ = Describe("getChatMessagesEndpoint", func() {
+
+	testUserID := "TestUserId"
+
+	var dataStoreSpy *spy.GameDataStoreSpy
+	var chatDataStoreSpy *spy.ChatDataStoreSpy
+	var endpoint *api.GetChatMessagesEndpoint
+
+	BeforeEach(func() {
+		dataStoreSpy = &spy.GameDataStoreSpy{}
+		chatDataStoreSpy = &spy.ChatDataStoreSpy{}
+		endpoint = api.NewGetChatMessagesEndpoint(dataStoreSpy, chatDataStoreSpy)
+		dataStoreSpy.GameReturn = &api.Game{GameID: "game id", PlayerOneID: testUserID, PlayerTwoID: "opponent"}
+	})
+
+	Context("performAction method", func() {
+
+		It("Should return the requested page of messages", func() {
+			chatDataStoreSpy.ChatMessagesReturn = []*api.ChatMessage{{MessageID: "message id"}}
+			messages, code := endpoint.PerformAction(testUserID, "game id", 1)
+			Expect(code).To(BeIdenticalTo(http.StatusOK))
+			Expect(messages).To(Equal(chatDataStoreSpy.ChatMessagesReturn))
+			Expect(chatDataStoreSpy.ChatMessagesGameID).To(BeIdenticalTo("game id"))
+			Expect(chatDataStoreSpy.ChatMessagesOffset).To(BeIdenticalTo(api.CHAT_PAGE_SIZE))
+			Expect(chatDataStoreSpy.ChatMessagesCount).To(BeIdenticalTo(api.CHAT_PAGE_SIZE))
+			Expect(chatDataStoreSpy.ChatMessagesExcludedSenderIDs).To(BeEmpty())
+		})
+
+		It("Should leave out the opponents messages if the player muted them", func() {
+			chatDataStoreSpy.MutedReturn = true
+			endpoint.PerformAction(testUserID, "game id", 0)
+			Expect(chatDataStoreSpy.MutedGameID).To(BeIdenticalTo("game id"))
+			Expect(chatDataStoreSpy.MutedUserID).To(BeIdenticalTo(testUserID))
+			Expect(chatDataStoreSpy.ChatMessagesExcludedSenderIDs).To(Equal([]string{"opponent"}))
+		})
+
+		It("Should return bad request for a negative page", func() {
+			_, code := endpoint.PerformAction(testUserID, "game id", -1)
+			Expect(code).To(BeIdenticalTo(http.StatusBadRequest))
+		})
+
+		It("Should return forbidden to users who are not playing the game", func() {
+			_, code := endpoint.PerformAction("spectator", "game id", 0)
+			Expect(code).To(BeIdenticalTo(http.StatusForbidden))
+		})
+
+		It("Should return not found if the game does not exist", func() {
+			dataStoreSpy.GameReturn = nil
+			_, code := endpoint.PerformAction(testUserID, "game id", 0)
+			Expect(code).To(BeIdenticalTo(http.StatusNotFound))
+		})
+
+		It("Should return an internal server error if the messages cannot be looked up", func() {
+			chatDataStoreSpy.ChatMessagesErr = errors.New("Error getting messages")
+			_, code := endpoint.PerformAction(testUserID, "game id", 0)
+			Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
+		})
+	})
+})
@@ -5,11 +5,12 @@ import (
 )
 
 type GetGameEndpoint struct {
-	ds GameDataStore
+	ds  GameDataStore
+	rds RatingDataStore
 }
 
-func NewGetGameEndpoint(ds GameDataStore) *GetGameEndpoint {
-	return &GetGameEndpoint{ds: ds}
+func NewGetGameEndpoint(ds GameDataStore, rds RatingDataStore) *GetGameEndpoint {
+	return &GetGameEndpoint{ds: ds, rds: rds}
 }
 
 func (ge *GetGameEndpoint) PerformAction(userID string, gameID string, includeInactive bool) ([]*Game, int) { //TODO Should change this away from http response codes I think
@@ -27,7 +28,7 @@ func (ge *GetGameEndpoint) getActiveGamesFromDataStoreAndReturn(gameID string, u
 	if err != nil {
 		return nil, http.StatusInternalServerError
 	}
-	return games, http.StatusOK
+	return ge.withRatings(games)
 }
 
 func (ge *GetGameEndpoint) getAllGamesFromDataStoreAndReturn(gameID string, userID string) ([]*Game, int) {
@@ -35,7 +36,7 @@ func (ge *GetGameEndpoint) getAllGamesFromDataStoreAndReturn(gameID string, user
 	if err != nil {
 		return nil, http.StatusInternalServerError
 	}
-	return games, http.StatusOK
+	return ge.withRatings(games)
 }
 
 func (ge *GetGameEndpoint) getSingleGameFromDataStoreAndReturn(gameID string, userID string) ([]*Game, int) {
@@ -46,9 +47,36 @@ func (ge *GetGameEndpoint) getSingleGameFromDataStoreAndReturn(gameID string, us
 	if game == nil {
 		return nil, http.StatusNotFound
 	}
-	if game.PlayerOneID != userID && game.PlayerTwoID != userID {
-		return nil, http.StatusForbidden
+	// Spectators can watch public games, moves are still only accepted from the players
+	if !game.isPlayer(userID) {
+		if game.Visibility != PUBLIC {
+			return nil, http.StatusForbidden
+		}
+		game = game.SpectatorView()
 	}
 	games := []*Game{game}
+	return ge.withRatings(games)
+}
+
+func (ge *GetGameEndpoint) withRatings(games []*Game) ([]*Game, int) {
+	return withRatings(ge.rds, games)
+}
+
+func withRatings(rds RatingDataStore, games []*Game) ([]*Game, int) {
+	ratings := map[string]int{}
+	for _, game := range games {
+		for _, playerID := range []string{game.PlayerOneID, game.PlayerTwoID} {
+			if _, found := ratings[playerID]; found || playerID == "" {
+				continue
+			}
+			rating, err := ratingOrDefault(rds, playerID)
+			if err != nil {
+				return nil, http.StatusInternalServerError
+			}
+			ratings[playerID] = rating.Rating
+		}
+		game.PlayerOneRating = ratings[game.PlayerOneID]
+		game.PlayerTwoRating = ratings[game.PlayerTwoID]
+	}
 	return games, http.StatusOK
 }
\ No newline at end of file
@@ -12,6 +12,7 @@ import (
 var _ = Describe("getGameEndpoint", func() {
 
 	var dataStoreSpy *spy.GameDataStoreSpy
+	var ratingDataStoreSpy *spy.RatingDataStoreSpy
 	var endpoint *api.GetGameEndpoint
 
 	testUserID := "test user id"
@@ -20,7 +21,8 @@ var _ = Describe("getGameEndpoint", func() {
 
 	BeforeEach(func() {
 		dataStoreSpy = &spy.GameDataStoreSpy{}
-		endpoint = api.NewGetGameEndpoint(dataStoreSpy)
+		ratingDataStoreSpy = &spy.RatingDataStoreSpy{}
+		endpoint = api.NewGetGameEndpoint(dataStoreSpy, ratingDataStoreSpy)
 	})
 
 	Context("performAction method", func() {
@@ -67,6 +69,23 @@ var _ = Describe("getGameEndpoint", func() {
 				Expect(games[0]).To(BeIdenticalTo(testGame))
 			})
 
+			It("Should include the ratings of both players", func() {
+				dataStoreSpy.GameReturn = &api.Game{GameID: gameID, PlayerOneID: userID, PlayerTwoID: "other id"}
+				ratingDataStoreSpy.RatingReturn = map[string]*api.Rating{"other id": {UserID: "other id", Rating: 1600}}
+				games, code := endpoint.PerformAction(userID, gameID, includeInactive)
+				Expect(code).To(BeIdenticalTo(http.StatusOK))
+				Expect(games[0].PlayerOneRating).To(BeIdenticalTo(api.INITIAL_RATING))
+				Expect(games[0].PlayerTwoRating).To(BeIdenticalTo(1600))
+			})
+
+			It("Should return internal server error if the ratings cannot be looked up", func() {
+				dataStoreSpy.GameReturn = &api.Game{GameID: gameID, PlayerOneID: userID, PlayerTwoID: "other id"}
+				ratingDataStoreSpy.RatingErr = errors.New("Error getting rating")
+				games, code := endpoint.PerformAction(userID, gameID, includeInactive)
+				Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
+				Expect(games).To(BeEmpty())
+			})
+
 			It("Should return forbidden if the player is not part of the requested game", func() {
 				dataStoreSpy.GameReturn = testGame2
 				endpoint.PerformAction(userID, gameID, includeInactive)
@@ -74,6 +93,26 @@ var _ = Describe("getGameEndpoint", func() {
 				Expect(code).To(BeIdenticalTo(http.StatusForbidden))
 				Expect(games).To(BeEmpty())
 			})
+
+			It("Should let spectators see public games", func() {
+				dataStoreSpy.GameReturn = &api.Game{GameID: gameID, PlayerOneID: "not test id", PlayerTwoID: "other id", Visibility: api.PUBLIC}
+				games, code := endpoint.PerformAction(userID, gameID, includeInactive)
+				Expect(code).To(BeIdenticalTo(http.StatusOK))
+				Expect(games[0].GameID).To(BeIdenticalTo(gameID))
+			})
+
+			It("Should not show spectators the invite code", func() {
+				dataStoreSpy.GameReturn = &api.Game{GameID: gameID, PlayerOneID: "not test id", InviteCode: "ABC234", Visibility: api.PUBLIC}
+				games, _ := endpoint.PerformAction(userID, gameID, includeInactive)
+				Expect(games[0].InviteCode).To(BeEmpty())
+				Expect(dataStoreSpy.GameReturn.InviteCode).To(BeIdenticalTo("ABC234"))
+			})
+
+			It("Should show the players the invite code", func() {
+				dataStoreSpy.GameReturn = &api.Game{GameID: gameID, PlayerOneID: userID, InviteCode: "ABC234", Visibility: api.PUBLIC}
+				games, _ := endpoint.PerformAction(userID, gameID, includeInactive)
+				Expect(games[0].InviteCode).To(BeIdenticalTo("ABC234"))
+			})
 		})
 
 		Context("and inactive games are requested", func() {
@@ -0,0 +1,40 @@
+package neutrinoapi
+
+import "net/http"
+
+type GetLeaderboardEndpoint struct {
+	lds LeaderboardDataStore
+}
+
+func NewGetLeaderboardEndpoint(lds LeaderboardDataStore) *GetLeaderboardEndpoint {
+	return &GetLeaderboardEndpoint{lds: lds}
+}
+
+// PerformAction returns the requested page from the top of the leaderboard, or if aroundMe is set,
+// a page with the player in the middle of it.
+func (gle *GetLeaderboardEndpoint) PerformAction(userID string, aroundMe bool, page int) ([]*LeaderboardEntry, int) {
+	if page < 0 {
+		return nil, http.StatusBadRequest
+	}
+	offset := page * LEADERBOARD_PAGE_SIZE
+
+	if aroundMe {
+		rank, err := gle.lds.LeaderboardRank(userID)
+		if err != nil {
+			return nil, http.StatusInternalServerError
+		}
+		if rank == 0 {
+			return nil, http.StatusNotFound
+		}
+		offset = rank - 1 - LEADERBOARD_PAGE_SIZE/2
+		if offset < 0 {
+			offset = 0
+		}
+	}
+
+	entries, err := gle.lds.LeaderboardEntries(offset, LEADERBOARD_PAGE_SIZE)
+	if err != nil {
+		return nil, http.StatusInternalServerError
+	}
+	return entries, http.StatusOK
+}
@@ -0,0 +1,86 @@
+package neutrinoapi_test
+
+import (
+	"errors"
+	api "github.com/Morras/neutrinoapi"
+	"github.com/Morras/neutrinoapi/spy"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+)
+
+var _ = Describe("getLeaderboardEndpoint", func() {
+
+	testUserID := "TestUserId"
+
+	var leaderboardDataStoreSpy *spy.LeaderboardDataStoreSpy
+	var endpoint *api.GetLeaderboardEndpoint
+
+	BeforeEach(func() {
+		leaderboardDataStoreSpy = &spy.LeaderboardDataStoreSpy{}
+		endpoint = api.NewGetLeaderboardEndpoint(leaderboardDataStoreSpy)
+	})
+
+	Context("performAction method", func() {
+
+		Context("Given the top of the leaderboard is requested", func() {
+			It("Should return the first page", func() {
+				top := []*api.LeaderboardEntry{{Rank: 1, UserID: "best", Rating: 2000}}
+				leaderboardDataStoreSpy.LeaderboardEntriesReturn = top
+				entries, code := endpoint.PerformAction(testUserID, false, 0)
+				Expect(code).To(BeIdenticalTo(http.StatusOK))
+				Expect(entries).To(Equal(top))
+				Expect(leaderboardDataStoreSpy.LeaderboardEntriesOffset).To(BeIdenticalTo(0))
+				Expect(leaderboardDataStoreSpy.LeaderboardEntriesCount).To(BeIdenticalTo(api.LEADERBOARD_PAGE_SIZE))
+			})
+
+			It("Should skip the earlier pages", func() {
+				endpoint.PerformAction(testUserID, false, 2)
+				Expect(leaderboardDataStoreSpy.LeaderboardEntriesOffset).To(BeIdenticalTo(2 * api.LEADERBOARD_PAGE_SIZE))
+			})
+
+			It("Should reject negative pages", func() {
+				_, code := endpoint.PerformAction(testUserID, false, -1)
+				Expect(code).To(BeIdenticalTo(http.StatusBadRequest))
+			})
+
+			It("Should not look up the players rank", func() {
+				endpoint.PerformAction(testUserID, false, 0)
+				Expect(leaderboardDataStoreSpy.LeaderboardRankUserID).To(BeEmpty())
+			})
+		})
+
+		Context("Given the leaderboard around the player is requested", func() {
+			It("Should center the page on the player", func() {
+				leaderboardDataStoreSpy.LeaderboardRankReturn = 100
+				endpoint.PerformAction(testUserID, true, 0)
+				Expect(leaderboardDataStoreSpy.LeaderboardRankUserID).To(BeIdenticalTo(testUserID))
+				Expect(leaderboardDataStoreSpy.LeaderboardEntriesOffset).To(BeIdenticalTo(99 - api.LEADERBOARD_PAGE_SIZE/2))
+			})
+
+			It("Should not go above the top of the leaderboard", func() {
+				leaderboardDataStoreSpy.LeaderboardRankReturn = 2
+				endpoint.PerformAction(testUserID, true, 0)
+				Expect(leaderboardDataStoreSpy.LeaderboardEntriesOffset).To(BeIdenticalTo(0))
+			})
+
+			It("Should return not found if the player is not on the leaderboard", func() {
+				_, code := endpoint.PerformAction(testUserID, true, 0)
+				Expect(code).To(BeIdenticalTo(http.StatusNotFound))
+			})
+
+			It("Should return an internal server error if the rank cannot be looked up", func() {
+				leaderboardDataStoreSpy.LeaderboardRankErr = errors.New("Error getting rank")
+				_, code := endpoint.PerformAction(testUserID, true, 0)
+				Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
+			})
+		})
+
+		It("Should return an internal server error if the leaderboard cannot be read", func() {
+			leaderboardDataStoreSpy.LeaderboardEntriesErr = errors.New("Error getting leaderboard")
+			entries, code := endpoint.PerformAction(testUserID, false, 0)
+			Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
+			Expect(entries).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,25 @@
+package neutrinoapi
+
+import "net/http"
+
+type GetLiveGamesEndpoint struct {
+	ds  GameDataStore
+	rds RatingDataStore
+}
+
+func NewGetLiveGamesEndpoint(ds GameDataStore, rds RatingDataStore) *GetLiveGamesEndpoint {
+	return &GetLiveGamesEndpoint{ds: ds, rds: rds}
+}
+
+// PerformAction returns a page of public games being played right now, for spectators to pick from.
+func (gle *GetLiveGamesEndpoint) PerformAction(page int) ([]*Game, int) {
+	if page < 0 {
+		return nil, http.StatusBadRequest
+	}
+
+	games, err := gle.ds.LiveGames(page*LIVE_GAMES_PAGE_SIZE, LIVE_GAMES_PAGE_SIZE)
+	if err != nil {
+		return nil, http.StatusInternalServerError
+	}
+	return withRatings(gle.rds, games)
+}
@@ -0,0 +1,49 @@
+package neutrinoapi_test
+
+import (
+	"errors"
+	api "github.com/Morras/neutrinoapi"
+	"github.com/Morras/neutrinoapi/spy"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+)
+
+var _ = Describe("getLiveGamesEndpoint", func() {
+
+	var dataStoreSpy *spy.GameDataStoreSpy
+	var ratingDataStoreSpy *spy.RatingDataStoreSpy
+	var endpoint *api.GetLiveGamesEndpoint
+
+	BeforeEach(func() {
+		dataStoreSpy = &spy.GameDataStoreSpy{}
+		ratingDataStoreSpy = &spy.RatingDataStoreSpy{}
+		endpoint = api.NewGetLiveGamesEndpoint(dataStoreSpy, ratingDataStoreSpy)
+	})
+
+	Context("performAction method", func() {
+
+		It("Should return the requested page of live games with the players ratings", func() {
+			dataStoreSpy.LiveGamesReturn = []*api.Game{{GameID: "live game", PlayerOneID: "one", PlayerTwoID: "two", State: api.PLAYING, Visibility: api.PUBLIC}}
+			ratingDataStoreSpy.RatingReturn = map[string]*api.Rating{"one": {Rating: 1600}}
+			games, code := endpoint.PerformAction(2)
+			Expect(code).To(BeIdenticalTo(http.StatusOK))
+			Expect(dataStoreSpy.LiveGamesOffset).To(BeIdenticalTo(2 * api.LIVE_GAMES_PAGE_SIZE))
+			Expect(dataStoreSpy.LiveGamesCount).To(BeIdenticalTo(api.LIVE_GAMES_PAGE_SIZE))
+			Expect(games[0].GameID).To(BeIdenticalTo("live game"))
+			Expect(games[0].PlayerOneRating).To(BeIdenticalTo(1600))
+			Expect(games[0].PlayerTwoRating).To(BeIdenticalTo(api.INITIAL_RATING))
+		})
+
+		It("Should return bad request for a negative page", func() {
+			_, code := endpoint.PerformAction(-1)
+			Expect(code).To(BeIdenticalTo(http.StatusBadRequest))
+		})
+
+		It("Should return an internal server error if the games cannot be looked up", func() {
+			dataStoreSpy.LiveGamesErr = errors.New("Error getting live games")
+			_, code := endpoint.PerformAction(0)
+			Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
+		})
+	})
+})
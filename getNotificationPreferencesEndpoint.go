@@ -0,0 +1,22 @@
+package neutrinoapi
+
+import "net/http"
+
+type GetNotificationPreferencesEndpoint struct {
+	nds NotificationDataStore
+}
+
+func NewGetNotificationPreferencesEndpoint(nds NotificationDataStore) *GetNotificationPreferencesEndpoint {
+	return &GetNotificationPreferencesEndpoint{nds: nds}
+}
+
+func (gpe *GetNotificationPreferencesEndpoint) PerformAction(userID string) (*NotificationPreferences, int) {
+	preferences, err := gpe.nds.NotificationPreferences(userID)
+	if err != nil {
+		return nil, http.StatusInternalServerError
+	}
+	if preferences == nil {
+		return DefaultNotificationPreferences(), http.StatusOK
+	}
+	return preferences, http.StatusOK
+}
@@ -0,0 +1,45 @@
+package neutrinoapi_test
+
+import (
+	"errors"
+	api "github.com/Morras/neutrinoapi"
+	"github.com/Morras/neutrinoapi/spy"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+)
+
+var _ = Describe("getNotificationPreferencesEndpoint", func() {
+
+	testUserID := "TestUserId"
+
+	var dataStoreSpy *spy.NotificationDataStoreSpy
+	var endpoint *api.GetNotificationPreferencesEndpoint
+
+	BeforeEach(func() {
+		dataStoreSpy = &spy.NotificationDataStoreSpy{}
+		endpoint = api.NewGetNotificationPreferencesEndpoint(dataStoreSpy)
+	})
+
+	Context("performAction method", func() {
+		It("Should return the users preferences", func() {
+			saved := &api.NotificationPreferences{GameEnded: true}
+			dataStoreSpy.NotificationPreferencesReturn = saved
+			preferences, code := endpoint.PerformAction(testUserID)
+			Expect(code).To(BeIdenticalTo(http.StatusOK))
+			Expect(dataStoreSpy.NotificationPreferencesUserID).To(BeIdenticalTo(testUserID))
+			Expect(preferences).To(BeIdenticalTo(saved))
+		})
+
+		It("Should turn everything on for users who never changed their preferences", func() {
+			preferences, _ := endpoint.PerformAction(testUserID)
+			Expect(preferences).To(Equal(api.DefaultNotificationPreferences()))
+		})
+
+		It("Should return an internal server error if the preferences could not be fetched", func() {
+			dataStoreSpy.NotificationPreferencesErr = errors.New("error getting preferences")
+			_, code := endpoint.PerformAction(testUserID)
+			Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
+		})
+	})
+})
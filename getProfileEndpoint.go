@@ -0,0 +1,71 @@
+package neutrinoapi
+
+import "net/http"
+
+// Profile only holds what any player is allowed to see about any other player.
+type Profile struct {
+	UserID        string
+	Rating        int
+	RatedGames    int
+	RatingHistory []*RatingChange
+	Stats         *Stats
+}
+
+type GetProfileEndpoint struct {
+	ds  GameDataStore
+	rds RatingDataStore
+}
+
+func NewGetProfileEndpoint(ds GameDataStore, rds RatingDataStore) *GetProfileEndpoint {
+	return &GetProfileEndpoint{ds: ds, rds: rds}
+}
+
+func (gpe *GetProfileEndpoint) PerformAction(profileUserID string) (*Profile, int) {
+	if profileUserID == "" {
+		return nil, http.StatusBadRequest
+	}
+
+	rating, err := ratingOrDefault(gpe.rds, profileUserID)
+	if err != nil {
+		return nil, http.StatusInternalServerError
+	}
+
+	history, err := gpe.rds.RatingHistory(profileUserID)
+	if err != nil {
+		return nil, http.StatusInternalServerError
+	}
+
+	games, err := gpe.ds.Games(profileUserID)
+	if err != nil {
+		return nil, http.StatusInternalServerError
+	}
+
+	profile := &Profile{
+		UserID:        profileUserID,
+		Rating:        rating.Rating,
+		RatedGames:    rating.RatedGames,
+		RatingHistory: publicRatingHistory(history, games),
+		Stats:         computeStats(profileUserID, games),
+	}
+	return profile, http.StatusOK
+}
+
+// The rating changes of games that are not PUBLIC are still shown, as the rating itself is public,
+// but not which game they came from or who the opponent was.
+func publicRatingHistory(history []*RatingChange, games []*Game) []*RatingChange {
+	publicGames := map[string]bool{}
+	for _, game := range games {
+		if game.Visibility == PUBLIC {
+			publicGames[game.GameID] = true
+		}
+	}
+
+	public := make([]*RatingChange, len(history))
+	for i, change := range history {
+		public[i] = change
+		if !publicGames[change.GameID] {
+			public[i] = &RatingChange{UserID: change.UserID, Before: change.Before, After: change.After, Time: change.Time}
+		}
+	}
+	return public
+}
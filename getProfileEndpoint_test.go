@@ -0,0 +1,140 @@
+package neutrinoapi_test
+
+import (
+	"errors"
+	api "github.com/Morras/neutrinoapi"
+	"github.com/Morras/neutrinoapi/spy"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+	"time"
+)
+
+var _ = Describe("getProfileEndpoint", func() {
+
+	profileUserID := "ProfileUserId"
+
+	var dataStoreSpy *spy.GameDataStoreSpy
+	var ratingDataStoreSpy *spy.RatingDataStoreSpy
+	var endpoint *api.GetProfileEndpoint
+
+	BeforeEach(func() {
+		dataStoreSpy = &spy.GameDataStoreSpy{}
+		ratingDataStoreSpy = &spy.RatingDataStoreSpy{}
+		endpoint = api.NewGetProfileEndpoint(dataStoreSpy, ratingDataStoreSpy)
+	})
+
+	Context("performAction method", func() {
+
+		It("Should return the rating and rating history of the player", func() {
+			history := []*api.RatingChange{{UserID: profileUserID, Before: 1500, After: 1516}}
+			ratingDataStoreSpy.RatingReturn = map[string]*api.Rating{profileUserID: {UserID: profileUserID, Rating: 1516, RatedGames: 1}}
+			ratingDataStoreSpy.RatingHistoryReturn = history
+
+			profile, code := endpoint.PerformAction(profileUserID)
+			Expect(code).To(BeIdenticalTo(http.StatusOK))
+			Expect(profile.UserID).To(BeIdenticalTo(profileUserID))
+			Expect(profile.Rating).To(BeIdenticalTo(1516))
+			Expect(profile.RatedGames).To(BeIdenticalTo(1))
+			Expect(profile.RatingHistory).To(Equal(history))
+			Expect(ratingDataStoreSpy.RatingHistoryUserID).To(BeIdenticalTo(profileUserID))
+		})
+
+		It("Should not show which private games the rating changes came from", func() {
+			ratingDataStoreSpy.RatingHistoryReturn = []*api.RatingChange{
+				{UserID: profileUserID, GameID: "public game", OpponentID: "opponent", Before: 1500, After: 1516},
+				{UserID: profileUserID, GameID: "private game", OpponentID: "friend", Before: 1516, After: 1530},
+			}
+			dataStoreSpy.GamesReturn = []*api.Game{{GameID: "public game", Visibility: api.PUBLIC}, {GameID: "private game", Visibility: api.PLAYERS_ONLY}}
+
+			profile, _ := endpoint.PerformAction(profileUserID)
+			Expect(profile.RatingHistory[0].GameID).To(BeIdenticalTo("public game"))
+			Expect(profile.RatingHistory[0].OpponentID).To(BeIdenticalTo("opponent"))
+			Expect(profile.RatingHistory[1].GameID).To(BeIdenticalTo(""))
+			Expect(profile.RatingHistory[1].OpponentID).To(BeIdenticalTo(""))
+			Expect(profile.RatingHistory[1].After).To(BeIdenticalTo(1530))
+			Expect(ratingDataStoreSpy.RatingHistoryReturn[1].GameID).To(BeIdenticalTo("private game"))
+		})
+
+		It("Should return the initial rating for new players", func() {
+			profile, _ := endpoint.PerformAction(profileUserID)
+			Expect(profile.Rating).To(BeIdenticalTo(api.INITIAL_RATING))
+		})
+
+		Context("Given the player has played some games", func() {
+			var now time.Time
+			finished := func(playerOneID string, playerTwoID string, winnerID string, winningCondition api.WinningCondition, turns int, minutesAgo int) *api.Game {
+				return &api.Game{PlayerOneID: playerOneID, PlayerTwoID: playerTwoID, WinnerID: winnerID, WinningCondition: winningCondition,
+					State: api.DONE, Turns: turns, FinishedAt: now.Add(-time.Duration(minutesAgo) * time.Minute)}
+			}
+
+			BeforeEach(func() {
+				now = time.Now()
+				dataStoreSpy.GamesReturn = []*api.Game{
+					finished(profileUserID, "a", profileUserID, api.BACK_LINE, 10, 50),
+					finished("b", profileUserID, "b", api.TRAP, 20, 40),
+					finished(profileUserID, "c", "c", api.DEFAULT, 5, 30),
+					// Deliberately out of order, the streak should follow when games finished
+					finished("d", profileUserID, profileUserID, api.TRAP, 15, 10),
+					finished(profileUserID, "e", profileUserID, api.TRAP, 30, 20),
+					{PlayerOneID: profileUserID, PlayerTwoID: "f", State: api.PLAYING, Turns: 100},
+				}
+			})
+
+			It("Should ask the data store for all the players games", func() {
+				endpoint.PerformAction(profileUserID)
+				Expect(dataStoreSpy.GamesUserID).To(BeIdenticalTo(profileUserID))
+			})
+
+			It("Should only count finished games", func() {
+				profile, _ := endpoint.PerformAction(profileUserID)
+				Expect(profile.Stats.GamesPlayed).To(BeIdenticalTo(5))
+				Expect(profile.Stats.AverageGameLength).To(BeNumerically("==", 16))
+			})
+
+			It("Should split wins and losses by winning condition", func() {
+				profile, _ := endpoint.PerformAction(profileUserID)
+				Expect(profile.Stats.Wins).To(Equal(api.ResultCounts{BackLine: 1, Trap: 2}))
+				Expect(profile.Stats.Losses).To(Equal(api.ResultCounts{Trap: 1, Default: 1}))
+				Expect(profile.Stats.Draws).To(BeIdenticalTo(0))
+			})
+
+			It("Should calculate the win rate as each player", func() {
+				profile, _ := endpoint.PerformAction(profileUserID)
+				Expect(profile.Stats.WinRateAsPlayerOne).To(BeNumerically("~", 2.0/3.0))
+				Expect(profile.Stats.WinRateAsPlayerTwo).To(BeNumerically("~", 0.5))
+			})
+
+			It("Should calculate the current streak", func() {
+				profile, _ := endpoint.PerformAction(profileUserID)
+				Expect(profile.Stats.CurrentStreak).To(BeIdenticalTo(2))
+			})
+
+			It("Should count losing streaks as negative", func() {
+				dataStoreSpy.GamesReturn = append(dataStoreSpy.GamesReturn, finished("g", profileUserID, "g", api.BACK_LINE, 8, 0))
+				profile, _ := endpoint.PerformAction(profileUserID)
+				Expect(profile.Stats.CurrentStreak).To(BeIdenticalTo(-1))
+			})
+		})
+
+		It("Should return empty stats for players without any games", func() {
+			profile, code := endpoint.PerformAction(profileUserID)
+			Expect(code).To(BeIdenticalTo(http.StatusOK))
+			Expect(profile.Stats).To(Equal(&api.Stats{}))
+		})
+
+		It("Should return internal server error if the games cannot be looked up", func() {
+			dataStoreSpy.GamesErr = errors.New("Error getting games")
+			profile, code := endpoint.PerformAction(profileUserID)
+			Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
+			Expect(profile).To(BeNil())
+		})
+
+		It("Should return internal server error if there is a problem talking with the datastore", func() {
+			ratingDataStoreSpy.RatingHistoryErr = errors.New("Error getting history")
+			profile, code := endpoint.PerformAction(profileUserID)
+			Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
+			Expect(profile).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,28 @@
+package neutrinoapi
+
+import "net/http"
+
+type GetPuzzleEndpoint struct {
+	pds PuzzleDataStore
+}
+
+func NewGetPuzzleEndpoint(pds PuzzleDataStore) *GetPuzzleEndpoint {
+	return &GetPuzzleEndpoint{pds: pds}
+}
+
+// PerformAction returns a puzzle the player has not tried yet, as close to their puzzle rating as possible.
+func (gpe *GetPuzzleEndpoint) PerformAction(userID string) (*Puzzle, int) {
+	rating, err := puzzleRatingOrDefault(gpe.pds, userID)
+	if err != nil {
+		return nil, http.StatusInternalServerError
+	}
+
+	puzzle, err := gpe.pds.NextPuzzle(userID, rating.Rating)
+	if err != nil {
+		return nil, http.StatusInternalServerError
+	}
+	if puzzle == nil {
+		return nil, http.StatusNotFound
+	}
+	return puzzle, http.StatusOK
+}
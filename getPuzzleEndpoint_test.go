@@ -0,0 +1,58 @@
+package neutrinoapi_test
+
+import (
+	"errors"
+	api "github.com/Morras/neutrinoapi"
+	"github.com/Morras/neutrinoapi/spy"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+)
+
+var _ = Describe("getPuzzleEndpoint", func() {
+
+	testUserID := "TestUserId"
+
+	var puzzleDataStoreSpy *spy.PuzzleDataStoreSpy
+	var endpoint *api.GetPuzzleEndpoint
+
+	BeforeEach(func() {
+		puzzleDataStoreSpy = &spy.PuzzleDataStoreSpy{}
+		endpoint = api.NewGetPuzzleEndpoint(puzzleDataStoreSpy)
+	})
+
+	Context("performAction method", func() {
+
+		It("Should return a puzzle close to the players puzzle rating", func() {
+			puzzleDataStoreSpy.PuzzleRatingReturn = &api.Rating{UserID: testUserID, Rating: 1720}
+			puzzleDataStoreSpy.NextPuzzleReturn = &api.Puzzle{PuzzleID: "puzzle id"}
+			puzzle, code := endpoint.PerformAction(testUserID)
+			Expect(code).To(BeIdenticalTo(http.StatusOK))
+			Expect(puzzle).To(BeIdenticalTo(puzzleDataStoreSpy.NextPuzzleReturn))
+			Expect(puzzleDataStoreSpy.NextPuzzleUserID).To(BeIdenticalTo(testUserID))
+			Expect(puzzleDataStoreSpy.NextPuzzleRating).To(BeIdenticalTo(1720))
+		})
+
+		It("Should use the initial rating for players who have not tried any puzzles", func() {
+			endpoint.PerformAction(testUserID)
+			Expect(puzzleDataStoreSpy.NextPuzzleRating).To(BeIdenticalTo(api.INITIAL_RATING))
+		})
+
+		It("Should return not found when the player has tried every puzzle", func() {
+			_, code := endpoint.PerformAction(testUserID)
+			Expect(code).To(BeIdenticalTo(http.StatusNotFound))
+		})
+
+		It("Should return an internal server error if the rating cannot be looked up", func() {
+			puzzleDataStoreSpy.PuzzleRatingErr = errors.New("Error getting rating")
+			_, code := endpoint.PerformAction(testUserID)
+			Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
+		})
+
+		It("Should return an internal server error if the puzzle cannot be looked up", func() {
+			puzzleDataStoreSpy.NextPuzzleErr = errors.New("Error getting puzzle")
+			_, code := endpoint.PerformAction(testUserID)
+			Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
+		})
+	})
+})
@@ -0,0 +1,69 @@
+package neutrinoapi
+
+import (
+	"net/http"
+	"strings"
+)
+
+type JoinPrivateGameEndpoint struct {
+	ds        GameDataStore
+	publisher GameEventPublisher
+	audit     AuditSink
+}
+
+func NewJoinPrivateGameEndpoint(ds GameDataStore, publisher GameEventPublisher, audit AuditSink) *JoinPrivateGameEndpoint {
+	return &JoinPrivateGameEndpoint{ds: ds, publisher: publisher, audit: audit}
+}
+
+func (jpe *JoinPrivateGameEndpoint) PerformAction(requestIDs RequestIDs, userID string, inviteCode string) (string, int) {
+	inviteCode = strings.ToUpper(strings.TrimSpace(inviteCode))
+	if !isValidInviteCode(inviteCode) {
+		return "", http.StatusBadRequest
+	}
+
+	if eligible, statusCode := isEligibleForNewGame(jpe.ds, userID); !eligible {
+		return "", statusCode
+	}
+
+	game, err := jpe.ds.GameByInviteCode(inviteCode)
+	if err != nil {
+		return "", http.StatusInternalServerError
+	}
+	if game == nil {
+		return "", http.StatusNotFound
+	}
+	if game.PlayerOneID == userID {
+		return "", http.StatusBadRequest
+	}
+	// Same race as when joining a random game, two players using the same code at the same time
+	// can both get past this check.
+	if game.PlayerTwoID != "" || game.State != INITIALIZING {
+		return "", http.StatusConflict
+	}
+
+	before := auditedGame(game)
+	game.PlayerTwoID = userID
+	game.State = PLAYING
+	game.Version++
+	event := newGameEvent(PLAYER_JOINED, userID, game)
+	if err = jpe.ds.JoinGame(userID, game.GameID, event); err != nil {
+		return "", http.StatusInternalServerError
+	}
+	publishGameEvent(jpe.publisher, event)
+	recordAudit(jpe.audit, requestIDs, &AuditEntry{ActorID: userID, Action: AUDIT_JOIN_PRIVATE_GAME, GameID: game.GameID,
+		Before: before, After: auditedGame(game)})
+
+	return game.GameID, http.StatusOK
+}
+
+func isValidInviteCode(inviteCode string) bool {
+	if len(inviteCode) != INVITE_CODE_LENGTH {
+		return false
+	}
+	for _, c := range inviteCode {
+		if !strings.ContainsRune(INVITE_CODE_ALPHABET, c) {
+			return false
+		}
+	}
+	return true
+}
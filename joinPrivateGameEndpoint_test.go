@@ -0,0 +1,114 @@
+package neutrinoapi_test
+
+import (
+	"errors"
+	api "github.com/Morras/neutrinoapi"
+	"github.com/Morras/neutrinoapi/spy"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+)
+
+var _ = Describe("joinPrivateGameEndpoint", func() {
+
+	testUserID := "TestUserId"
+	testRequestID := api.RequestIDs{ID: "TestRequestId"}
+	const inviteCode = "ABC234"
+
+	var dataStoreSpy *spy.GameDataStoreSpy
+	var publisherSpy *spy.GameEventPublisherSpy
+	var auditSpy *spy.AuditSinkSpy
+	var endpoint *api.JoinPrivateGameEndpoint
+
+	BeforeEach(func() {
+		dataStoreSpy = &spy.GameDataStoreSpy{}
+		publisherSpy = &spy.GameEventPublisherSpy{}
+		auditSpy = &spy.AuditSinkSpy{}
+		endpoint = api.NewJoinPrivateGameEndpoint(dataStoreSpy, publisherSpy, auditSpy)
+	})
+
+	Context("performAction method", func() {
+
+		It("Should reject malformed invite codes without asking the data store", func() {
+			for _, code := range []string{"", "ABC", "ABC2345", "ABC10O"} {
+				_, statusCode := endpoint.PerformAction(testRequestID, testUserID, code)
+				Expect(statusCode).To(BeIdenticalTo(http.StatusBadRequest))
+			}
+			Expect(dataStoreSpy.GameByInviteCodeInviteCode).To(BeEmpty())
+		})
+
+		It("Should accept lower case invite codes", func() {
+			endpoint.PerformAction(testRequestID, testUserID, "abc234")
+			Expect(dataStoreSpy.GameByInviteCodeInviteCode).To(BeIdenticalTo(inviteCode))
+		})
+
+		It("Should return a client error if the user already has the maximum number of active games", func() {
+			dataStoreSpy.NumberOfActiveGamesReturn = api.MAX_ACTIVE_GAMES
+			_, code := endpoint.PerformAction(testRequestID, testUserID, inviteCode)
+			Expect(code).To(BeIdenticalTo(http.StatusBadRequest))
+			Expect(dataStoreSpy.JoinGameGameID).To(BeEmpty())
+		})
+
+		It("Should return not found if no game uses the invite code", func() {
+			_, code := endpoint.PerformAction(testRequestID, testUserID, inviteCode)
+			Expect(code).To(BeIdenticalTo(http.StatusNotFound))
+		})
+
+		It("Should return an internal server error if the game cannot be looked up", func() {
+			dataStoreSpy.GameByInviteCodeErr = errors.New("Error looking up invite code")
+			_, code := endpoint.PerformAction(testRequestID, testUserID, inviteCode)
+			Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
+		})
+
+		It("Should not let the user join their own game", func() {
+			dataStoreSpy.GameByInviteCodeReturn = &api.Game{GameID: "game id", PlayerOneID: testUserID, InviteCode: inviteCode}
+			_, code := endpoint.PerformAction(testRequestID, testUserID, inviteCode)
+			Expect(code).To(BeIdenticalTo(http.StatusBadRequest))
+			Expect(dataStoreSpy.JoinGameGameID).To(BeEmpty())
+		})
+
+		It("Should reject a third player", func() {
+			dataStoreSpy.GameByInviteCodeReturn = &api.Game{GameID: "game id", PlayerOneID: "friend", PlayerTwoID: "other friend", State: api.PLAYING, InviteCode: inviteCode}
+			_, code := endpoint.PerformAction(testRequestID, testUserID, inviteCode)
+			Expect(code).To(BeIdenticalTo(http.StatusConflict))
+			Expect(dataStoreSpy.JoinGameGameID).To(BeEmpty())
+		})
+
+		Context("Given the game is waiting for the invited player", func() {
+			BeforeEach(func() {
+				dataStoreSpy.GameByInviteCodeReturn = &api.Game{GameID: "game id", PlayerOneID: "friend", State: api.INITIALIZING, InviteCode: inviteCode}
+			})
+
+			It("Should join the game", func() {
+				gameID, code := endpoint.PerformAction(testRequestID, testUserID, inviteCode)
+				Expect(code).To(BeIdenticalTo(http.StatusOK))
+				Expect(gameID).To(BeIdenticalTo("game id"))
+				Expect(dataStoreSpy.JoinGameGameID).To(BeIdenticalTo("game id"))
+				Expect(dataStoreSpy.JoinGameUserID).To(BeIdenticalTo(testUserID))
+			})
+
+			It("Should tell the friend waiting in the game that it has started", func() {
+				endpoint.PerformAction(testRequestID, testUserID, inviteCode)
+				Expect(len(publisherSpy.PublishEvents)).To(BeIdenticalTo(1))
+				event := publisherSpy.PublishEvents[0]
+				Expect(event.Type).To(BeIdenticalTo(api.PLAYER_JOINED))
+				Expect(event.Game.PlayerOneID).To(BeIdenticalTo("friend"))
+				Expect(event.Game.PlayerTwoID).To(BeIdenticalTo(testUserID))
+				Expect(event.Game.State).To(BeIdenticalTo(api.PLAYING))
+			})
+
+			It("Should record joining the game in the audit log", func() {
+				endpoint.PerformAction(testRequestID, testUserID, inviteCode)
+				Expect(len(auditSpy.RecordEntries)).To(BeIdenticalTo(1))
+				Expect(auditSpy.RecordEntries[0].Action).To(BeIdenticalTo(api.AUDIT_JOIN_PRIVATE_GAME))
+				Expect(auditSpy.RecordEntries[0].GameID).To(BeIdenticalTo("game id"))
+			})
+
+			It("Should return an internal server error if the game cannot be joined", func() {
+				dataStoreSpy.JoinGameErr = errors.New("Error joining game")
+				_, code := endpoint.PerformAction(testRequestID, testUserID, inviteCode)
+				Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
+			})
+		})
+	})
+})
@@ -0,0 +1,18 @@
+package neutrinoapi
+
+type LeaderboardEntry struct {
+	Rank               int
+	UserID             string
+	Rating, RatedGames int
+}
+
+// LeaderboardDataStore keeps players sorted by rating as ratings change, so reading a page of the
+// leaderboard never has to look at any games.
+type LeaderboardDataStore interface {
+	// LeaderboardEntries returns up to count entries, starting offset places from the top.
+	LeaderboardEntries(offset int, count int) ([]*LeaderboardEntry, error)
+	// LeaderboardRank should return 0 if the player is not on the leaderboard.
+	LeaderboardRank(userID string) (int, error)
+	// UpdateLeaderboard adds or moves the players, the data store works out their new ranks.
+	UpdateLeaderboard(entries []*LeaderboardEntry) error
+}
@@ -1,8 +1,10 @@
 package neutrinoapi
 
 import (
+	"fmt"
 	"github.com/Morras/go-neutrino/game"
 	"net/http"
+	"time"
 )
 
 type MakeMoveRequest struct {
@@ -12,40 +14,116 @@ type MakeMoveRequest struct {
 }
 
 type MakeMoveEndpoint struct {
-	ds             GameDataStore
-	gameController game.GameController
+	ds        GameDataStore
+	rater     *Rater
+	bot       BotPlayer
+	publisher GameEventPublisher
+	audit     AuditSink
 }
 
-func NewMakeMoveEndpoint(ds GameDataStore) *MakeMoveEndpoint {
-	return &MakeMoveEndpoint{ds: ds}
+func NewMakeMoveEndpoint(ds GameDataStore, rater *Rater, bot BotPlayer, publisher GameEventPublisher, audit AuditSink) *MakeMoveEndpoint {
+	return &MakeMoveEndpoint{ds: ds, rater: rater, bot: bot, publisher: publisher, audit: audit}
 }
 
-func (mme *MakeMoveEndpoint) PerformAction(userID string, makeMoveReq *MakeMoveRequest, gameController game.GameController) int {
+func (mme *MakeMoveEndpoint) PerformAction(requestIDs RequestIDs, userID string, makeMoveReq *MakeMoveRequest, gameController game.GameController) int {
 	dsGame, err := mme.ds.Game(makeMoveReq.GameID)
 	if err != nil {
 		return http.StatusInternalServerError
 	}
+	if dsGame == nil {
+		return http.StatusNotFound
+	}
 
 	actualGame := game.UInt64ToGame(dsGame.SerializedGame)
+	difficulty, isBot := botDifficulty(dsGame.PlayerTwoID)
+
+	// A bot turn that failed after the players last move is retried before anything else, as the
+	// player could never move again otherwise. The move in the request was made against the board
+	// from before the bots turn, so the player has to look at the game again either way.
+	if isBot && userID == dsGame.PlayerOneID && dsGame.State == PLAYING && isPlayersTurn(dsGame.PlayerTwoID, dsGame, actualGame) {
+		if played := mme.playBotTurn(requestIDs, dsGame, difficulty, gameController); !played {
+			return http.StatusInternalServerError
+		}
+		return http.StatusConflict
+	}
 
 	if playersTurn := isPlayersTurn(userID, dsGame, actualGame); !playersTurn {
 		return http.StatusForbidden
 	}
 
-	mme.gameController.PlayGame(actualGame)
+	if statusCode := mme.playTurn(requestIDs, userID, dsGame, actualGame, makeMoveReq, gameController); statusCode != http.StatusOK {
+		return statusCode
+	}
+
+	// Bots answer right away, so the player never has to wait for them
+	if isBot && dsGame.State != DONE {
+		mme.playBotTurn(requestIDs, dsGame, difficulty, gameController)
+	}
+
+	return http.StatusOK
+}
 
-	if err = mme.makeMoves(makeMoveReq); err != nil {
+func (mme *MakeMoveEndpoint) playTurn(requestIDs RequestIDs, userID string, dsGame *Game, actualGame *game.Game, makeMoveReq *MakeMoveRequest, gameController game.GameController) int {
+	gameController.PlayGame(actualGame)
+
+	state, winningCondition, err := makeMoves(gameController, makeMoveReq)
+	if err != nil {
 		return http.StatusBadRequest
 	}
 
-	dsGame.SerializedGame = game.GameToUInt64(mme.gameController.Game())
+	before := auditedGame(dsGame)
+	dsGame.History = append(dsGame.History, dsGame.SerializedGame)
+	dsGame.SerializedGame = game.GameToUInt64(gameController.Game())
+	dsGame.Turns++
+	dsGame.Version++
+	if isGameOver(state) {
+		finishGame(dsGame, winnerID(dsGame, state), winningCondition)
+	}
 
-	if err = mme.ds.UpdateGame(dsGame); err != nil {
+	eventType := MOVE_MADE
+	if dsGame.State == DONE {
+		eventType = GAME_FINISHED
+	}
+	event := newGameEvent(eventType, userID, dsGame)
+	if err = mme.ds.UpdateGame(dsGame, event); err != nil {
 		return http.StatusInternalServerError
 	}
+	recordAudit(mme.audit, requestIDs, &AuditEntry{ActorID: userID, Action: AUDIT_MAKE_MOVE, GameID: dsGame.GameID,
+		Before: before, After: auditedGame(dsGame)})
+
+	if dsGame.State == DONE {
+		// The move has been saved at this point, so failing the request would only confuse the player
+		if err = mme.rater.RateGame(dsGame); err != nil {
+			fmt.Printf("Error rating game %v: %v\n", dsGame.GameID, err)
+		}
+	}
+	publishGameEvent(mme.publisher, event)
 
 	return http.StatusOK
 }
+
+// The players own move has already been saved when the bot plays, so errors are only logged. The
+// game is left waiting for the bot, which gets another go the next time the player tries to move.
+func (mme *MakeMoveEndpoint) playBotTurn(requestIDs RequestIDs, dsGame *Game, difficulty BotDifficulty, gameController game.GameController) bool {
+	actualGame := game.UInt64ToGame(dsGame.SerializedGame)
+	if !isPlayersTurn(dsGame.PlayerTwoID, dsGame, actualGame) {
+		return false
+	}
+
+	botMoveReq, err := mme.bot.NextTurn(actualGame, difficulty)
+	if err != nil {
+		fmt.Printf("Error finding a bot move in game %v: %v\n", dsGame.GameID, err)
+		return false
+	}
+	botMoveReq.GameID = dsGame.GameID
+
+	if statusCode := mme.playTurn(requestIDs, dsGame.PlayerTwoID, dsGame, actualGame, botMoveReq, gameController); statusCode != http.StatusOK {
+		fmt.Printf("Error playing bot move in game %v: %v\n", dsGame.GameID, statusCode)
+		return false
+	}
+	return true
+}
+
 func isPlayersTurn(userID string, datastoreGame *Game, actualGame *game.Game) bool {
 	if userID == datastoreGame.PlayerOneID &&
 		(actualGame.State == game.Player1NeutrinoMove || actualGame.State == game.Player1Move) {
@@ -57,13 +135,37 @@ func isPlayersTurn(userID string, datastoreGame *Game, actualGame *game.Game) bo
 	return false
 }
 
-func (mme *MakeMoveEndpoint) makeMoves(makeMoveReq *MakeMoveRequest) error {
+// makeMoves returns the state of the game after the turn, and if the game is over, how it was won.
+// Moving the neutrino to a back line wins the game right away, so the piece move is skipped in that case.
+func makeMoves(gameController game.GameController, makeMoveReq *MakeMoveRequest) (game.State, WinningCondition, error) {
 	neutrinoMove := game.NewMove(makeMoveReq.NeutrinoFromX, makeMoveReq.NeutrinoFromY, makeMoveReq.NeutrinoToX, makeMoveReq.NeutrinoToY)
-	if _, err := mme.gameController.MakeMove(neutrinoMove); err != nil {
-		return err
+	state, err := gameController.MakeMove(neutrinoMove)
+	if err != nil {
+		return state, DEFAULT, err
+	}
+	if isGameOver(state) {
+		return state, BACK_LINE, nil
 	}
 
 	pieceMove := game.NewMove(makeMoveReq.PieceFromX, makeMoveReq.PieceFromY, makeMoveReq.PieceToX, makeMoveReq.PieceToY)
-	_, err := mme.gameController.MakeMove(pieceMove)
-	return err
+	state, err = gameController.MakeMove(pieceMove)
+	return state, TRAP, err
+}
+
+func isGameOver(state game.State) bool {
+	return state == game.Player1Win || state == game.Player2Win
+}
+
+func winnerID(datastoreGame *Game, state game.State) string {
+	if state == game.Player1Win {
+		return datastoreGame.PlayerOneID
+	}
+	return datastoreGame.PlayerTwoID
+}
+
+func finishGame(datastoreGame *Game, winnerID string, winningCondition WinningCondition) {
+	datastoreGame.State = DONE
+	datastoreGame.WinnerID = winnerID
+	datastoreGame.WinningCondition = winningCondition
+	datastoreGame.FinishedAt = time.Now()
 }
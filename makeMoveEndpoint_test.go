@@ -1,7 +1,6 @@
 package neutrinoapi_test
 
 import (
-	"bytes"
 	"errors"
 	g "github.com/Morras/go-neutrino/game"
 	api "github.com/Morras/neutrinoapi"
@@ -9,158 +8,304 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"net/http"
-	"net/http/httptest"
 )
 
-var _ = Describe("newGameEndpoint", func() {
+var _ = Describe("makeMoveEndpoint", func() {
 
 	testUserID := "TestUserId"
+	testRequestID := api.RequestIDs{ID: "TestRequestId"}
+	opponentID := "OpponentUserId"
 
-	var request *http.Request
-	var response *httptest.ResponseRecorder
-	var requestParserSpy *spy.RequestParserSpy
 	var dataStoreSpy *spy.GameDataStoreSpy
+	var ratingDataStoreSpy *spy.RatingDataStoreSpy
 	var gameControllerSpy *spy.GameControllerSpy
+	var botPlayerSpy *spy.BotPlayerSpy
+	var publisherSpy *spy.GameEventPublisherSpy
+	var auditSpy *spy.AuditSinkSpy
 	var endpoint *api.MakeMoveEndpoint
-
-	const validBodyJSON = `{
-		"GameID": "TestGameID",
-		"NeutrinoFromX": 1,
-		"NeutrinoToX": 2,
-		"NeutrinoFromY": 3,
-		"NeutrinoToY": 4,
-		"PieceFromX": 1,
-		"PieceToX": 2,
-		"PieceFromY": 3,
-		"PieceToY": 4
-	}`
-
-	var validBody *bytes.Reader
-
-	// Invalid due to missing GameID
-	const invalidBodyJSON = `{
-		"NeutrinoFromX": 1,
-		"NeutrinoToX": 2,
-		"NeutrinoFromY": 3,
-		"NeutrinoToY": 4,
-		"PieceFromX": 1,
-		"PieceToX": 2,
-		"PieceFromY": 3,
-		"PieceToY": 4
-	}`
-
-	var invalidBody *bytes.Reader
+	var makeMoveReq *api.MakeMoveRequest
 
 	BeforeEach(func() {
-		//invalidBody = bytes.NewReader([]byte(invalidBodyJSON)) TODO left in to remind me to test for this
-		//validBody = bytes.NewReader([]byte(validBodyJSON))
-		request = httptest.NewRequest(http.MethodPost, "/", nil)
-		response = httptest.NewRecorder()
-		requestParserSpy = &spy.RequestParserSpy{}
 		dataStoreSpy = &spy.GameDataStoreSpy{}
+		ratingDataStoreSpy = &spy.RatingDataStoreSpy{}
 		gameControllerSpy = &spy.GameControllerSpy{}
-		endpoint = api.NewMakeMoveEndpoint(requestParserSpy, dataStoreSpy, gameControllerSpy)
+		botPlayerSpy = &spy.BotPlayerSpy{}
+		publisherSpy = &spy.GameEventPublisherSpy{}
+		auditSpy = &spy.AuditSinkSpy{}
+		endpoint = api.NewMakeMoveEndpoint(dataStoreSpy, api.NewRater(ratingDataStoreSpy, &spy.LeaderboardDataStoreSpy{}), botPlayerSpy, publisherSpy, auditSpy)
+		makeMoveReq = &api.MakeMoveRequest{
+			GameID:        "TestGameID",
+			NeutrinoFromX: 1, NeutrinoToX: 2, NeutrinoFromY: 3, NeutrinoToY: 4,
+			PieceFromX: 0, PieceToX: 0, PieceFromY: 1, PieceToY: 2,
+		}
 	})
 
 	Context("performAction method", func() {
-		It("Should attempt to get the user from request", func() {
-			endpoint.PerformAction(response, request)
-			Expect(requestParserSpy.Request).To(BeIdenticalTo(request))
+
+		It("Should try and get the game", func() {
+			dataStoreSpy.GameErr = errors.New("error getting game")
+			endpoint.PerformAction(testRequestID, testUserID, makeMoveReq, gameControllerSpy)
+			Expect(dataStoreSpy.GameGameID).To(BeIdenticalTo("TestGameID"))
+		})
+
+		Context("and there was an error getting the game", func() {
+			It("Should return an internal server error", func() {
+				dataStoreSpy.GameErr = errors.New("error getting game")
+				code := endpoint.PerformAction(testRequestID, testUserID, makeMoveReq, gameControllerSpy)
+				Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
+			})
 		})
 
-		Context("Given the user is not logged in", func() {
-			It("Should return forbidden http response", func() {
-				requestParserSpy.Err = api.ErrInvalidJWT
-				endpoint.PerformAction(response, request)
-				Expect(response.Code).To(BeIdenticalTo(http.StatusForbidden))
+		Context("and the game does not exist", func() {
+			It("Should return not found", func() {
+				code := endpoint.PerformAction(testRequestID, testUserID, makeMoveReq, gameControllerSpy)
+				Expect(code).To(BeIdenticalTo(http.StatusNotFound))
 			})
 		})
 
-		Context("Given that the user is logged in", func() {
+		Context("and the data store returns a game", func() {
+			var game *api.Game
+			BeforeEach(func() {
+				game = &api.Game{GameID: "TestGameID", PlayerOneID: testUserID, PlayerTwoID: opponentID, State: api.PLAYING}
+				game.SerializedGame = g.GameToUInt64(g.NewStandardGame())
+				dataStoreSpy.GameReturn = game
+				gameControllerSpy.GameReturn = g.NewStandardGame()
+			})
 
-			Context("and the body is missing", func() {
-				It("Should return a bad request", func() {
-					// Using http instead of httptest to force a nil body
-					request, _ = http.NewRequest(http.MethodPost, "/", nil)
-					endpoint.PerformAction(response, request)
-					Expect(response.Code).To(BeIdenticalTo(http.StatusBadRequest))
+			Context("and it is not the players turn", func() {
+				It("Should return forbidden", func() {
+					// We are returning standard game so we know that its player ones turn
+					game.PlayerOneID = "someoneElse"
+					game.PlayerTwoID = testUserID
+					code := endpoint.PerformAction(testRequestID, testUserID, makeMoveReq, gameControllerSpy)
+					Expect(code).To(BeIdenticalTo(http.StatusForbidden))
+				})
+			})
+
+			Context("and the bot opponent did not get to play its last turn", func() {
+				BeforeEach(func() {
+					game.PlayerTwoID = api.BotUserID(api.MEDIUM)
+					botsTurn := g.NewStandardGame()
+					botsTurn.State = g.Player2NeutrinoMove
+					game.SerializedGame = g.GameToUInt64(botsTurn)
+					gameControllerSpy.GameReturn = g.NewStandardGame()
+					botPlayerSpy.NextTurnReturn = &api.MakeMoveRequest{NeutrinoFromX: 2, NeutrinoToX: 2, NeutrinoFromY: 2, NeutrinoToY: 1,
+						PieceFromX: 4, PieceToX: 4, PieceFromY: 4, PieceToY: 2}
+				})
+
+				It("Should let the bot play before the player", func() {
+					code := endpoint.PerformAction(testRequestID, testUserID, makeMoveReq, gameControllerSpy)
+					Expect(code).To(BeIdenticalTo(http.StatusConflict))
+					Expect(botPlayerSpy.NextTurnDifficulty).To(BeIdenticalTo(api.MEDIUM))
+					Expect(gameControllerSpy.MakeMoveMove).To(Equal(g.NewMove(4, 4, 4, 2)))
+					Expect(dataStoreSpy.UpdateGameGame.Turns).To(BeIdenticalTo(1))
+				})
+
+				It("Should return an internal server error if the bot still cannot play", func() {
+					botPlayerSpy.NextTurnErr = errors.New("No legal turn")
+					code := endpoint.PerformAction(testRequestID, testUserID, makeMoveReq, gameControllerSpy)
+					Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
+					Expect(dataStoreSpy.UpdateGameGame).To(BeNil())
+				})
+
+				It("Should not let anyone else make the bot play", func() {
+					code := endpoint.PerformAction(testRequestID, "someoneElse", makeMoveReq, gameControllerSpy)
+					Expect(code).To(BeIdenticalTo(http.StatusForbidden))
+					Expect(botPlayerSpy.NextTurnGame).To(BeNil())
 				})
 			})
 
-			Context("and the body does not contain a valid request", func() {
+			It("Should play the game from the data store", func() {
+				endpoint.PerformAction(testRequestID, testUserID, makeMoveReq, gameControllerSpy)
+				Expect(gameControllerSpy.PlayGameGame).ToNot(BeNil())
+			})
+
+			Context("and the move is not valid", func() {
 				It("Should return a bad request", func() {
-					request = httptest.NewRequest(http.MethodPost, "/", invalidBody)
-					endpoint.PerformAction(response, request)
-					Expect(response.Code).To(BeIdenticalTo(http.StatusBadRequest))
+					gameControllerSpy.MakeMoveErr = errors.New("Invalid move")
+					code := endpoint.PerformAction(testRequestID, testUserID, makeMoveReq, gameControllerSpy)
+					Expect(code).To(BeIdenticalTo(http.StatusBadRequest))
 				})
 			})
 
-			Context("and the body contains a valid request", func() {
-				BeforeEach(func() {
-					request = httptest.NewRequest(http.MethodPost, "/", validBody)
+			Context("and the move is valid", func() {
+				It("Should attempt to save the game", func() {
+					endpoint.PerformAction(testRequestID, testUserID, makeMoveReq, gameControllerSpy)
+					Expect(dataStoreSpy.UpdateGameGame).ToNot(BeNil())
+				})
+
+				It("Should count the turn", func() {
+					game.Turns = 4
+					endpoint.PerformAction(testRequestID, testUserID, makeMoveReq, gameControllerSpy)
+					Expect(dataStoreSpy.UpdateGameGame.Turns).To(BeIdenticalTo(5))
+				})
+
+				It("Should bump the version of the game", func() {
+					game.Version = 7
+					endpoint.PerformAction(testRequestID, testUserID, makeMoveReq, gameControllerSpy)
+					Expect(dataStoreSpy.UpdateGameGame.Version).To(BeIdenticalTo(8))
 				})
 
-				It("Should try and get the game", func() {
-					dataStoreSpy.GameErr = errors.New("error getting game")
-					endpoint.PerformAction(response, request)
-					Expect(dataStoreSpy.GameGameID).To(BeIdenticalTo("TestGameID"))
+				It("Should keep the position from before the turn in the history", func() {
+					before := game.SerializedGame
+					endpoint.PerformAction(testRequestID, testUserID, makeMoveReq, gameControllerSpy)
+					Expect(dataStoreSpy.UpdateGameGame.History).To(Equal([]uint64{before}))
 				})
 
-				Context("and there was an error getting the game", func() {
+				It("Should finish the turn with the piece move", func() {
+					endpoint.PerformAction(testRequestID, testUserID, makeMoveReq, gameControllerSpy)
+					Expect(gameControllerSpy.MakeMoveMove).To(Equal(g.NewMove(0, 1, 0, 2)))
+				})
+
+				Context("but there was an error saving the game", func() {
 					It("Should return an internal server error", func() {
-						dataStoreSpy.GameErr = errors.New("error getting game")
-						endpoint.PerformAction(response, request)
-						Expect(response.Code).To(BeIdenticalTo(http.StatusInternalServerError))
+						dataStoreSpy.UpdateGameErr = errors.New("error updating game")
+						code := endpoint.PerformAction(testRequestID, testUserID, makeMoveReq, gameControllerSpy)
+						Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
+						Expect(auditSpy.RecordEntries).To(BeEmpty())
 					})
 				})
 
-				Context("and the data store returns a game", func() {
-					var game *api.Game
+				Context("and the game was successfully saved", func() {
+					It("Should return status ok", func() {
+						code := endpoint.PerformAction(testRequestID, testUserID, makeMoveReq, gameControllerSpy)
+						Expect(code).To(BeIdenticalTo(http.StatusOK))
+					})
+
+					It("Should tell the players about the turn", func() {
+						endpoint.PerformAction(testRequestID, testUserID, makeMoveReq, gameControllerSpy)
+						Expect(len(publisherSpy.PublishEvents)).To(BeIdenticalTo(1))
+						Expect(publisherSpy.PublishEvents[0].Type).To(BeIdenticalTo(api.MOVE_MADE))
+						Expect(publisherSpy.PublishEvents[0].Game.Turns).To(BeIdenticalTo(1))
+					})
+
+					It("Should save the event along with the game", func() {
+						endpoint.PerformAction(testRequestID, testUserID, makeMoveReq, gameControllerSpy)
+						Expect(dataStoreSpy.UpdateGameEvents).To(Equal(publisherSpy.PublishEvents))
+					})
+
+					It("Should record the position before and after the turn in the audit log", func() {
+						before := game.SerializedGame
+						endpoint.PerformAction(testRequestID, testUserID, makeMoveReq, gameControllerSpy)
+						Expect(len(auditSpy.RecordEntries)).To(BeIdenticalTo(1))
+						entry := auditSpy.RecordEntries[0]
+						Expect(entry.Action).To(BeIdenticalTo(api.AUDIT_MAKE_MOVE))
+						Expect(entry.ActorID).To(BeIdenticalTo(testUserID))
+						Expect(entry.RequestID).To(BeIdenticalTo(testRequestID.ID))
+						Expect(entry.GameID).To(BeIdenticalTo(game.GameID))
+						Expect(entry.Before.SerializedGame).To(BeIdenticalTo(before))
+						Expect(entry.After.SerializedGame).To(BeIdenticalTo(dataStoreSpy.UpdateGameGame.SerializedGame))
+						Expect(entry.Occurred).ToNot(BeZero())
+					})
+
+					It("Should not rate a game that is still going", func() {
+						endpoint.PerformAction(testRequestID, testUserID, makeMoveReq, gameControllerSpy)
+						Expect(game.State).To(BeIdenticalTo(api.PLAYING))
+						Expect(ratingDataStoreSpy.UpdateRatingsChanges).To(BeNil())
+					})
+				})
+
+				Context("and the opponent is a bot", func() {
+					var botMoveReq *api.MakeMoveRequest
+
 					BeforeEach(func() {
-						game = &api.Game{}
-						game.SerializedGame = g.GameToUInt64(g.NewStandardGame())
-						dataStoreSpy.GameReturn = game
+						game.PlayerTwoID = api.BotUserID(api.MEDIUM)
+						botsTurn := g.NewStandardGame()
+						botsTurn.State = g.Player2NeutrinoMove
+						gameControllerSpy.GameReturn = botsTurn
+						botMoveReq = &api.MakeMoveRequest{NeutrinoFromX: 2, NeutrinoToX: 2, NeutrinoFromY: 2, NeutrinoToY: 1,
+							PieceFromX: 4, PieceToX: 4, PieceFromY: 4, PieceToY: 2}
+						botPlayerSpy.NextTurnReturn = botMoveReq
+					})
+
+					It("Should let the bot play its turn right away", func() {
+						code := endpoint.PerformAction(testRequestID, testUserID, makeMoveReq, gameControllerSpy)
+						Expect(code).To(BeIdenticalTo(http.StatusOK))
+						Expect(botPlayerSpy.NextTurnDifficulty).To(BeIdenticalTo(api.MEDIUM))
+						Expect(botMoveReq.GameID).To(BeIdenticalTo(game.GameID))
+						Expect(gameControllerSpy.MakeMoveMove).To(Equal(g.NewMove(4, 4, 4, 2)))
+						Expect(dataStoreSpy.UpdateGameGame.Turns).To(BeIdenticalTo(2))
+					})
+
+					It("Should publish the game as it was after each turn", func() {
+						endpoint.PerformAction(testRequestID, testUserID, makeMoveReq, gameControllerSpy)
+						Expect(len(publisherSpy.PublishEvents)).To(BeIdenticalTo(2))
+						Expect(publisherSpy.PublishEvents[0].Game.Turns).To(BeIdenticalTo(1))
+						Expect(publisherSpy.PublishEvents[1].Game.Turns).To(BeIdenticalTo(2))
+					})
+
+					It("Should record the bots turn under the same request", func() {
+						endpoint.PerformAction(testRequestID, testUserID, makeMoveReq, gameControllerSpy)
+						Expect(len(auditSpy.RecordEntries)).To(BeIdenticalTo(2))
+						botEntry := auditSpy.RecordEntries[1]
+						Expect(botEntry.ActorID).To(BeIdenticalTo(api.BotUserID(api.MEDIUM)))
+						Expect(botEntry.RequestID).To(BeIdenticalTo(testRequestID.ID))
+						Expect(botEntry.Before).To(Equal(auditSpy.RecordEntries[0].After))
+					})
+
+					It("Should not ask the bot to play when it is not its turn", func() {
 						gameControllerSpy.GameReturn = g.NewStandardGame()
+						endpoint.PerformAction(testRequestID, testUserID, makeMoveReq, gameControllerSpy)
+						Expect(botPlayerSpy.NextTurnGame).To(BeNil())
+					})
+
+					It("Should keep the players move even if the bot cannot find a move", func() {
+						botPlayerSpy.NextTurnErr = errors.New("No legal turn")
+						code := endpoint.PerformAction(testRequestID, testUserID, makeMoveReq, gameControllerSpy)
+						Expect(code).To(BeIdenticalTo(http.StatusOK))
+						Expect(dataStoreSpy.UpdateGameGame.Turns).To(BeIdenticalTo(1))
+					})
+				})
+
+				Context("and the opponent is not a bot", func() {
+					It("Should not ask the bot to play", func() {
+						botsTurn := g.NewStandardGame()
+						botsTurn.State = g.Player2NeutrinoMove
+						gameControllerSpy.GameReturn = botsTurn
+						endpoint.PerformAction(testRequestID, testUserID, makeMoveReq, gameControllerSpy)
+						Expect(botPlayerSpy.NextTurnGame).To(BeNil())
+					})
+				})
+
+				Context("and the move wins the game", func() {
+					BeforeEach(func() {
+						gameControllerSpy.MakeMoveReturn = g.Player1Win
+					})
+
+					It("Should finish the game with the player as the winner", func() {
+						endpoint.PerformAction(testRequestID, testUserID, makeMoveReq, gameControllerSpy)
+						saved := dataStoreSpy.UpdateGameGame
+						Expect(saved.State).To(BeIdenticalTo(api.DONE))
+						Expect(saved.WinnerID).To(BeIdenticalTo(testUserID))
+						Expect(saved.FinishedAt.IsZero()).To(BeFalse())
+					})
+
+					It("Should skip the piece move as the neutrino reached a back line", func() {
+						endpoint.PerformAction(testRequestID, testUserID, makeMoveReq, gameControllerSpy)
+						Expect(gameControllerSpy.MakeMoveMove).To(Equal(g.NewMove(1, 3, 2, 4)))
+						Expect(dataStoreSpy.UpdateGameGame.WinningCondition).To(BeIdenticalTo(api.BACK_LINE))
+					})
+
+					It("Should update the ratings of both players", func() {
+						endpoint.PerformAction(testRequestID, testUserID, makeMoveReq, gameControllerSpy)
+						changes := ratingDataStoreSpy.UpdateRatingsChanges
+						Expect(len(changes)).To(BeIdenticalTo(2))
+						Expect(changes[0].UserID).To(BeIdenticalTo(testUserID))
+						Expect(changes[0].After).To(BeNumerically(">", changes[0].Before))
+						Expect(changes[1].UserID).To(BeIdenticalTo(opponentID))
+						Expect(changes[1].After).To(BeNumerically("<", changes[1].Before))
+					})
+
+					It("Should tell the players the game is finished", func() {
+						endpoint.PerformAction(testRequestID, testUserID, makeMoveReq, gameControllerSpy)
+						Expect(publisherSpy.PublishEvents[0].Type).To(BeIdenticalTo(api.GAME_FINISHED))
+						Expect(publisherSpy.PublishEvents[0].Game.WinnerID).To(BeIdenticalTo(testUserID))
 					})
 
-					Context("and it is not the players turn", func() {
-						It("Should return forbidden", func() {
-							// We are returning standard game so we know that its player ones turn
-							game.PlayerOneID = "someoneElse"
-							game.PlayerTwoID = testUserID
-							endpoint.PerformAction(response, request)
-							Expect(response.Code).To(BeIdenticalTo(http.StatusForbidden))
-						})
-					})
-
-					Context("and the move is not valid", func() {
-						It("Should return a bad request", func() {
-							gameControllerSpy.MakeMoveErr = errors.New("Invalid move")
-							endpoint.PerformAction(response, request)
-							Expect(response.Code).To(BeIdenticalTo(http.StatusBadRequest))
-						})
-					})
-
-					Context("and the move is valid", func() {
-						It("Should attempt to save the game", func() {
-							endpoint.PerformAction(response, request)
-							Expect(dataStoreSpy.UpdateGameGame).ToNot(BeNil())
-						})
-
-						Context("but there was an error saving the game", func() {
-							It("Should return an internal server error", func() {
-								dataStoreSpy.UpdateGameErr = errors.New("error updating game")
-								endpoint.PerformAction(response, request)
-								Expect(response.Code).To(BeIdenticalTo(http.StatusInternalServerError))
-							})
-						})
-
-						Context("and the game was successfully saved", func() {
-							It("Should return status ok", func() {
-								endpoint.PerformAction(response, request)
-								Expect(response.Code).To(BeIdenticalTo(http.StatusOK))
-							})
-						})
+					It("Should still return status ok if the ratings could not be updated", func() {
+						ratingDataStoreSpy.UpdateRatingsErr = errors.New("error updating ratings")
+						code := endpoint.PerformAction(testRequestID, testUserID, makeMoveReq, gameControllerSpy)
+						Expect(code).To(BeIdenticalTo(http.StatusOK))
 					})
 				})
 			})
@@ -0,0 +1,87 @@
+package neutrinoapi
+
+import (
+	"time"
+)
+
+// Matchmaker picks which waiting game a player should join. It is an interface so data stores that
+// can index waiting games by rating are free to do the matching themselves.
+type Matchmaker interface {
+	// FindGame returns nil if there is no suitable game to join, in which case the player starts their own.
+	FindGame(userID string, excludedOpponentIDs []string) (*Game, error)
+}
+
+// FirstWaitingGameMatchmaker joins whichever game the data store returns first, regardless of ratings.
+type FirstWaitingGameMatchmaker struct {
+	ds GameDataStore
+}
+
+func NewFirstWaitingGameMatchmaker(ds GameDataStore) *FirstWaitingGameMatchmaker {
+	return &FirstWaitingGameMatchmaker{ds: ds}
+}
+
+func (m *FirstWaitingGameMatchmaker) FindGame(userID string, excludedOpponentIDs []string) (*Game, error) {
+	return m.ds.GameWaitingForPlayers(userID, excludedOpponentIDs)
+}
+
+// RatingWindowMatchmaker joins the game with the closest rated opponent, as long as the difference
+// in ratings is within a window that grows with the time the game has been waiting.
+type RatingWindowMatchmaker struct {
+	ds  GameDataStore
+	rds RatingDataStore
+}
+
+func NewRatingWindowMatchmaker(ds GameDataStore, rds RatingDataStore) *RatingWindowMatchmaker {
+	return &RatingWindowMatchmaker{ds: ds, rds: rds}
+}
+
+func (m *RatingWindowMatchmaker) FindGame(userID string, excludedOpponentIDs []string) (*Game, error) {
+	games, err := m.ds.GamesWaitingForPlayers(userID, excludedOpponentIDs)
+	if err != nil || len(games) == 0 {
+		return nil, err
+	}
+
+	player, err := ratingOrDefault(m.rds, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var bestGame *Game
+	bestDifference := 0
+	for _, game := range games {
+		opponent, err := ratingOrDefault(m.rds, game.PlayerOneID)
+		if err != nil {
+			return nil, err
+		}
+
+		difference := abs(player.Rating - opponent.Rating)
+		if difference > ratingWindow(now.Sub(game.CreatedAt)) {
+			continue
+		}
+		// Games are oldest first, so ties go to whoever has waited the longest
+		if bestGame == nil || difference < bestDifference {
+			bestGame = game
+			bestDifference = difference
+		}
+	}
+	return bestGame, nil
+}
+
+func ratingWindow(waited time.Duration) int {
+	if waited < 0 {
+		waited = 0
+	}
+	window := RATING_WINDOW_INITIAL + int(waited.Minutes()*RATING_WINDOW_GROWTH_PER_MINUTE)
+	if window > RATING_WINDOW_MAX {
+		return RATING_WINDOW_MAX
+	}
+	return window
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,96 @@
+package neutrinoapi_test
+
+import (
+	"errors"
+	api "github.com/Morras/neutrinoapi"
+	"github.com/Morras/neutrinoapi/spy"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"time"
+)
+
+var _ = Describe("RatingWindowMatchmaker", func() {
+
+	testUserID := "TestUserId"
+
+	var dataStoreSpy *spy.GameDataStoreSpy
+	var ratingDataStoreSpy *spy.RatingDataStoreSpy
+	var matchmaker *api.RatingWindowMatchmaker
+
+	waitingGame := func(gameID string, playerOneID string, waited time.Duration) *api.Game {
+		return &api.Game{GameID: gameID, PlayerOneID: playerOneID, State: api.INITIALIZING, CreatedAt: time.Now().Add(-waited)}
+	}
+
+	BeforeEach(func() {
+		dataStoreSpy = &spy.GameDataStoreSpy{}
+		ratingDataStoreSpy = &spy.RatingDataStoreSpy{}
+		matchmaker = api.NewRatingWindowMatchmaker(dataStoreSpy, ratingDataStoreSpy)
+		ratingDataStoreSpy.RatingReturn = map[string]*api.Rating{
+			testUserID: {UserID: testUserID, Rating: 1500},
+			"close":    {UserID: "close", Rating: 1550},
+			"closer":   {UserID: "closer", Rating: 1520},
+			"far":      {UserID: "far", Rating: 1900},
+		}
+	})
+
+	Context("FindGame", func() {
+
+		It("Should pass the filtering on to the data store", func() {
+			excluded := []string{"recent opponent"}
+			matchmaker.FindGame(testUserID, excluded)
+			Expect(dataStoreSpy.GamesWaitingForPlayersUserID).To(BeIdenticalTo(testUserID))
+			Expect(dataStoreSpy.GamesWaitingForPlayersExcludedOpponentIDs).To(Equal(excluded))
+		})
+
+		It("Should return nothing if no games are waiting", func() {
+			game, err := matchmaker.FindGame(testUserID, nil)
+			Expect(err).To(BeNil())
+			Expect(game).To(BeNil())
+		})
+
+		It("Should prefer the closest rated opponent", func() {
+			dataStoreSpy.GamesWaitingForPlayersReturn = []*api.Game{
+				waitingGame("close game", "close", 0),
+				waitingGame("closer game", "closer", 0),
+			}
+			game, _ := matchmaker.FindGame(testUserID, nil)
+			Expect(game.GameID).To(BeIdenticalTo("closer game"))
+		})
+
+		It("Should prefer the game that has waited the longest between equally rated opponents", func() {
+			dataStoreSpy.GamesWaitingForPlayersReturn = []*api.Game{
+				waitingGame("old game", "close", 2*time.Minute),
+				waitingGame("new game", "close", 0),
+			}
+			game, _ := matchmaker.FindGame(testUserID, nil)
+			Expect(game.GameID).To(BeIdenticalTo("old game"))
+		})
+
+		It("Should not match opponents outside the rating window", func() {
+			dataStoreSpy.GamesWaitingForPlayersReturn = []*api.Game{waitingGame("far game", "far", 0)}
+			game, err := matchmaker.FindGame(testUserID, nil)
+			Expect(err).To(BeNil())
+			Expect(game).To(BeNil())
+		})
+
+		It("Should widen the rating window the longer a game waits", func() {
+			dataStoreSpy.GamesWaitingForPlayersReturn = []*api.Game{waitingGame("far game", "far", 10*time.Minute)}
+			game, _ := matchmaker.FindGame(testUserID, nil)
+			Expect(game.GameID).To(BeIdenticalTo("far game"))
+		})
+
+		It("Should treat players without a rating as having the initial rating", func() {
+			dataStoreSpy.GamesWaitingForPlayersReturn = []*api.Game{waitingGame("new player game", "new player", 0)}
+			game, _ := matchmaker.FindGame(testUserID, nil)
+			Expect(game.GameID).To(BeIdenticalTo("new player game"))
+		})
+
+		It("Should return errors from the data stores", func() {
+			dataStoreSpy.GamesWaitingForPlayersReturn = []*api.Game{waitingGame("close game", "close", 0)}
+			ratingDataStoreSpy.RatingErr = errors.New("Error getting rating")
+			game, err := matchmaker.FindGame(testUserID, nil)
+			Expect(err).ToNot(BeNil())
+			Expect(game).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,25 @@
+package neutrinoapi
+
+import "net/http"
+
+type MuteOpponentEndpoint struct {
+	ds  GameDataStore
+	cds ChatDataStore
+}
+
+func NewMuteOpponentEndpoint(ds GameDataStore, cds ChatDataStore) *MuteOpponentEndpoint {
+	return &MuteOpponentEndpoint{ds: ds, cds: cds}
+}
+
+// PerformAction mutes or unmutes the opponent in one game. Muting only hides the opponents messages
+// from the player, the opponent can still post.
+func (moe *MuteOpponentEndpoint) PerformAction(userID string, gameID string, muted bool) int {
+	if statusCode := checkChatParticipant(moe.ds, userID, gameID); statusCode != http.StatusOK {
+		return statusCode
+	}
+
+	if err := moe.cds.SetMuted(gameID, userID, muted); err != nil {
+		return http.StatusInternalServerError
+	}
+	return http.StatusOK
+}
@@ -0,0 +1,49 @@
+package neutrinoapi_test
+
+import (
+	"errors"
+	api "github.com/Morras/neutrinoapi"
+	"github.com/Morras/neutrinoapi/spy"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+)
+
+var _ = Describe("muteOpponentEndpoint", func() {
+
+	testUserID := "TestUserId"
+
+	var dataStoreSpy *spy.GameDataStoreSpy
+	var chatDataStoreSpy *spy.ChatDataStoreSpy
+	var endpoint *api.MuteOpponentEndpoint
+
+	BeforeEach(func() {
+		dataStoreSpy = &spy.GameDataStoreSpy{}
+		chatDataStoreSpy = &spy.ChatDataStoreSpy{}
+		endpoint = api.NewMuteOpponentEndpoint(dataStoreSpy, chatDataStoreSpy)
+		dataStoreSpy.GameReturn = &api.Game{GameID: "game id", PlayerOneID: testUserID, PlayerTwoID: "opponent"}
+	})
+
+	Context("performAction method", func() {
+
+		It("Should mute the opponent for the player", func() {
+			code := endpoint.PerformAction(testUserID, "game id", true)
+			Expect(code).To(BeIdenticalTo(http.StatusOK))
+			Expect(chatDataStoreSpy.SetMutedGameID).To(BeIdenticalTo("game id"))
+			Expect(chatDataStoreSpy.SetMutedUserID).To(BeIdenticalTo(testUserID))
+			Expect(chatDataStoreSpy.SetMutedMuted).To(BeTrue())
+		})
+
+		It("Should return forbidden to users who are not playing the game", func() {
+			code := endpoint.PerformAction("spectator", "game id", true)
+			Expect(code).To(BeIdenticalTo(http.StatusForbidden))
+			Expect(chatDataStoreSpy.SetMutedUserID).To(BeEmpty())
+		})
+
+		It("Should return an internal server error if the setting cannot be stored", func() {
+			chatDataStoreSpy.SetMutedErr = errors.New("Error muting")
+			code := endpoint.PerformAction(testUserID, "game id", false)
+			Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
+		})
+	})
+})
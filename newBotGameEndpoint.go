@@ -0,0 +1,36 @@
+package neutrinoapi
+
+import "net/http"
+
+type NewBotGameEndpoint struct {
+	ds        GameDataStore
+	publisher GameEventPublisher
+	audit     AuditSink
+}
+
+func NewNewBotGameEndpoint(ds GameDataStore, publisher GameEventPublisher, audit AuditSink) *NewBotGameEndpoint {
+	return &NewBotGameEndpoint{ds: ds, publisher: publisher, audit: audit}
+}
+
+// PerformAction starts a game against a bot right away, with the bot as player two.
+func (nbe *NewBotGameEndpoint) PerformAction(requestIDs RequestIDs, userID string, difficulty BotDifficulty) (string, int) {
+	if _, found := botDifficultyNames[difficulty]; !found {
+		return "", http.StatusBadRequest
+	}
+
+	if eligible, statusCode := isEligibleForNewGame(nbe.ds, userID); !eligible {
+		return "", statusCode
+	}
+
+	event := newGameEvent(GAME_CREATED, userID, &Game{PlayerOneID: userID, PlayerTwoID: BotUserID(difficulty), State: PLAYING})
+	gameID, err := nbe.ds.CreateGame(userID, BotUserID(difficulty), event)
+	if err != nil {
+		return "", http.StatusInternalServerError
+	}
+	event.Game.GameID = gameID
+	publishGameEvent(nbe.publisher, event)
+	recordAudit(nbe.audit, requestIDs, &AuditEntry{ActorID: userID, Action: AUDIT_NEW_BOT_GAME, GameID: gameID,
+		Details: "bot " + BotUserID(difficulty)})
+
+	return gameID, http.StatusOK
+}
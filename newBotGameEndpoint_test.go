@@ -0,0 +1,93 @@
+package neutrinoapi_test
+
+import (
+	"errors"
+	api "github.com/Morras/neutrinoapi"
+	"github.com/Morras/neutrinoapi/spy"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+)
+
+var _ = Describe("newBotGameEndpoint", func() {
+
+	testUserID := "TestUserId"
+	testRequestID := api.RequestIDs{ID: "TestRequestId"}
+
+	var dataStoreSpy *spy.GameDataStoreSpy
+	var publisherSpy *spy.GameEventPublisherSpy
+	var auditSpy *spy.AuditSinkSpy
+	var endpoint *api.NewBotGameEndpoint
+
+	BeforeEach(func() {
+		dataStoreSpy = &spy.GameDataStoreSpy{}
+		publisherSpy = &spy.GameEventPublisherSpy{}
+		auditSpy = &spy.AuditSinkSpy{}
+		endpoint = api.NewNewBotGameEndpoint(dataStoreSpy, publisherSpy, auditSpy)
+	})
+
+	Context("performAction method", func() {
+
+		It("Should start a game with the bot as player two", func() {
+			dataStoreSpy.CreateGameReturn = "bot game id"
+			gameID, code := endpoint.PerformAction(testRequestID, testUserID, api.HARD)
+			Expect(code).To(BeIdenticalTo(http.StatusOK))
+			Expect(gameID).To(BeIdenticalTo("bot game id"))
+			Expect(dataStoreSpy.CreateGamePlayerOneID).To(BeIdenticalTo(testUserID))
+			Expect(dataStoreSpy.CreateGamePlayerTwoID).To(BeIdenticalTo(api.BotUserID(api.HARD)))
+		})
+
+		It("Should publish that a game was created", func() {
+			endpoint.PerformAction(testRequestID, testUserID, api.HARD)
+			Expect(len(publisherSpy.PublishEvents)).To(BeIdenticalTo(1))
+			Expect(publisherSpy.PublishEvents[0].Type).To(BeIdenticalTo(api.GAME_CREATED))
+			Expect(publisherSpy.PublishEvents[0].Game.PlayerTwoID).To(BeIdenticalTo(api.BotUserID(api.HARD)))
+		})
+
+		It("Should record the new game in the audit log", func() {
+			dataStoreSpy.CreateGameReturn = "bot game id"
+			endpoint.PerformAction(testRequestID, testUserID, api.HARD)
+			Expect(len(auditSpy.RecordEntries)).To(BeIdenticalTo(1))
+			Expect(auditSpy.RecordEntries[0].Action).To(BeIdenticalTo(api.AUDIT_NEW_BOT_GAME))
+			Expect(auditSpy.RecordEntries[0].GameID).To(BeIdenticalTo("bot game id"))
+		})
+
+		It("Should never use the matchmaking pool", func() {
+			endpoint.PerformAction(testRequestID, testUserID, api.EASY)
+			Expect(dataStoreSpy.GameWaitingForPlayersCalled).To(BeFalse())
+			Expect(dataStoreSpy.StartNewGameUserID).To(BeEmpty())
+		})
+
+		It("Should reject unknown difficulties", func() {
+			_, code := endpoint.PerformAction(testRequestID, testUserID, api.BotDifficulty(42))
+			Expect(code).To(BeIdenticalTo(http.StatusBadRequest))
+			Expect(dataStoreSpy.CreateGamePlayerOneID).To(BeEmpty())
+		})
+
+		It("Should respect the maximum number of active games", func() {
+			dataStoreSpy.NumberOfActiveGamesReturn = api.MAX_ACTIVE_GAMES
+			_, code := endpoint.PerformAction(testRequestID, testUserID, api.EASY)
+			Expect(code).To(BeIdenticalTo(http.StatusBadRequest))
+			Expect(dataStoreSpy.CreateGamePlayerOneID).To(BeEmpty())
+		})
+
+		It("Should return an internal server error if the game cannot be created", func() {
+			dataStoreSpy.CreateGameErr = errors.New("Error creating game")
+			_, code := endpoint.PerformAction(testRequestID, testUserID, api.EASY)
+			Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
+		})
+	})
+
+	Context("ParseBotDifficulty", func() {
+		It("Should parse the difficulty names regardless of case", func() {
+			difficulty, err := api.ParseBotDifficulty("Medium")
+			Expect(err).To(BeNil())
+			Expect(difficulty).To(BeIdenticalTo(api.MEDIUM))
+		})
+
+		It("Should return an error for unknown difficulties", func() {
+			_, err := api.ParseBotDifficulty("impossible")
+			Expect(err).ToNot(BeNil())
+		})
+	})
+})
@@ -0,0 +1,39 @@
+package neutrinoapi
+
+import (
+	"net/http"
+	"time"
+)
+
+type NewChallengeEndpoint struct {
+	ds  GameDataStore
+	cds ChallengeDataStore
+	ttl time.Duration
+}
+
+func NewNewChallengeEndpoint(ds GameDataStore, cds ChallengeDataStore, ttl time.Duration) *NewChallengeEndpoint {
+	return &NewChallengeEndpoint{ds: ds, cds: cds, ttl: ttl}
+}
+
+func (nce *NewChallengeEndpoint) PerformAction(userID string, challengedID string) (string, int) {
+	if challengedID == "" || challengedID == userID {
+		return "", http.StatusBadRequest
+	}
+
+	if eligible, statusCode := isEligibleForNewGame(nce.ds, userID); !eligible {
+		return "", statusCode
+	}
+
+	challenge := &Challenge{
+		ChallengerID: userID,
+		ChallengedID: challengedID,
+		Expires:      time.Now().Add(nce.ttl),
+	}
+
+	challengeID, err := nce.cds.CreateChallenge(challenge)
+	if err != nil {
+		return "", http.StatusInternalServerError
+	}
+
+	return challengeID, http.StatusOK
+}
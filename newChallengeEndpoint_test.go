@@ -0,0 +1,70 @@
+package neutrinoapi_test
+
+import (
+	"errors"
+	api "github.com/Morras/neutrinoapi"
+	"github.com/Morras/neutrinoapi/spy"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+	"time"
+)
+
+var _ = Describe("newChallengeEndpoint", func() {
+
+	testUserID := "TestUserId"
+	challengedID := "ChallengedUserId"
+	ttl := time.Hour
+
+	var dataStoreSpy *spy.GameDataStoreSpy
+	var challengeDataStoreSpy *spy.ChallengeDataStoreSpy
+	var endpoint *api.NewChallengeEndpoint
+
+	BeforeEach(func() {
+		dataStoreSpy = &spy.GameDataStoreSpy{}
+		challengeDataStoreSpy = &spy.ChallengeDataStoreSpy{}
+		endpoint = api.NewNewChallengeEndpoint(dataStoreSpy, challengeDataStoreSpy, ttl)
+	})
+
+	Context("performAction method", func() {
+
+		It("Should not let the user challenge nobody", func() {
+			_, code := endpoint.PerformAction(testUserID, "")
+			Expect(code).To(BeIdenticalTo(http.StatusBadRequest))
+			Expect(challengeDataStoreSpy.CreateChallengeChallenge).To(BeNil())
+		})
+
+		It("Should not let the user challenge themselves", func() {
+			_, code := endpoint.PerformAction(testUserID, testUserID)
+			Expect(code).To(BeIdenticalTo(http.StatusBadRequest))
+			Expect(challengeDataStoreSpy.CreateChallengeChallenge).To(BeNil())
+		})
+
+		It("Should not let the user challenge anyone with the maximum number of active games", func() {
+			dataStoreSpy.NumberOfActiveGamesReturn = api.MAX_ACTIVE_GAMES
+			_, code := endpoint.PerformAction(testUserID, challengedID)
+			Expect(code).To(BeIdenticalTo(http.StatusBadRequest))
+			Expect(challengeDataStoreSpy.CreateChallengeChallenge).To(BeNil())
+		})
+
+		It("Should store a challenge that expires after the configured time", func() {
+			challengeDataStoreSpy.CreateChallengeReturn = "challenge id"
+			before := time.Now()
+			challengeID, code := endpoint.PerformAction(testUserID, challengedID)
+
+			Expect(code).To(BeIdenticalTo(http.StatusOK))
+			Expect(challengeID).To(BeIdenticalTo("challenge id"))
+			challenge := challengeDataStoreSpy.CreateChallengeChallenge
+			Expect(challenge.ChallengerID).To(BeIdenticalTo(testUserID))
+			Expect(challenge.ChallengedID).To(BeIdenticalTo(challengedID))
+			Expect(challenge.Expires).To(BeTemporally(">=", before.Add(ttl)))
+			Expect(challenge.Expires).To(BeTemporally("<=", time.Now().Add(ttl)))
+		})
+
+		It("Should return an internal server error if the challenge cannot be stored", func() {
+			challengeDataStoreSpy.CreateChallengeErr = errors.New("Error creating challenge")
+			_, code := endpoint.PerformAction(testUserID, challengedID)
+			Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
+		})
+	})
+})
@@ -2,21 +2,28 @@ package neutrinoapi
 
 import "net/http"
 
+// NewGameEndpoint leaves games that nobody joins waiting, a BotFallback hands them to a bot after a while.
+// Players are not matched against their recentOpponentsToAvoid most recent opponents, 0 lets them meet anyone.
 type NewGameEndpoint struct {
-	ds GameDataStore
+	ds                     GameDataStore
+	matchmaker             Matchmaker
+	recentOpponentsToAvoid int
+	publisher              GameEventPublisher
+	audit                  AuditSink
 }
 
-func NewNewGameEndpoint(ds GameDataStore) *NewGameEndpoint {
-	return &NewGameEndpoint{ds: ds}
+func NewNewGameEndpoint(ds GameDataStore, matchmaker Matchmaker, recentOpponentsToAvoid int, publisher GameEventPublisher, audit AuditSink) *NewGameEndpoint {
+	return &NewGameEndpoint{ds: ds, matchmaker: matchmaker, recentOpponentsToAvoid: recentOpponentsToAvoid, publisher: publisher, audit: audit}
 }
 
-func (ne *NewGameEndpoint) PerformAction(userID string) (string, int){
+// The visibility is only used if a new game has to be started, joining a game keeps the visibility its creator chose.
+func (ne *NewGameEndpoint) PerformAction(requestIDs RequestIDs, userID string, visibility Visibility) (string, int){
 
-	if eligible, statusCode := ne.isEligibleForNewGame(userID); !eligible {
+	if eligible, statusCode := isEligibleForNewGame(ne.ds, userID); !eligible {
 		return "", statusCode
 	}
 
-	gameID, err := ne.joinExistingGame(userID)
+	gameID, err := ne.joinExistingGame(requestIDs, userID)
 	if err != nil {
 		return "", http.StatusInternalServerError
 	}
@@ -24,16 +31,20 @@ func (ne *NewGameEndpoint) PerformAction(userID string) (string, int){
 		return gameID, http.StatusOK
 	}
 
-	gameID, err = ne.ds.StartNewGame(userID)
+	event := newGameEvent(GAME_CREATED, userID, &Game{PlayerOneID: userID, State: INITIALIZING, Visibility: visibility})
+	gameID, err = ne.ds.StartNewGame(userID, visibility, event)
 	if err != nil {
 		return "", http.StatusInternalServerError
 	}
+	event.Game.GameID = gameID
+	publishGameEvent(ne.publisher, event)
+	recordAudit(ne.audit, requestIDs, &AuditEntry{ActorID: userID, Action: AUDIT_NEW_GAME, GameID: gameID})
 
 	return gameID, http.StatusOK
 }
 
-func (ne *NewGameEndpoint) isEligibleForNewGame(userID string) (bool, int) {
-	numberOfGames, err := ne.ds.NumberOfActiveGames(userID)
+func isEligibleForNewGame(ds GameDataStore, userID string) (bool, int) {
+	numberOfGames, err := ds.NumberOfActiveGames(userID)
 	if err != nil {
 		return false, http.StatusInternalServerError
 	}
@@ -44,22 +55,61 @@ func (ne *NewGameEndpoint) isEligibleForNewGame(userID string) (bool, int) {
 	return true, 0
 }
 
-func (ne *NewGameEndpoint) joinExistingGame(userID string) (string, error) {
+func (ne *NewGameEndpoint) joinExistingGame(requestIDs RequestIDs, userID string) (string, error) {
 	// So this is not at all thread safe. It is possible that two players join the same game,
 	// where the latter one then overrides the first one. TODO I should do something about that if
 	// I ever actually get anyone to play this.
-	activeGame, err := ne.ds.GameWaitingForPlayers()
+	excludedOpponentIDs, err := ne.recentOpponents(userID)
+	if err != nil {
+		return "", err
+	}
+
+	activeGame, err := ne.matchmaker.FindGame(userID, excludedOpponentIDs)
 
 	if err != nil {
 		return "", err
 	}
 
-	if activeGame != nil {
-		if err = ne.ds.JoinGame(userID, activeGame.GameID); err != nil {
+	// The matchmaker should already have filtered out the players own games, but joining your own
+	// game leaves it with the same player on both sides, so we do not rely on it.
+	if activeGame != nil && activeGame.PlayerOneID != userID {
+		before := auditedGame(activeGame)
+		activeGame.PlayerTwoID = userID
+		activeGame.State = PLAYING
+		activeGame.Version++
+		event := newGameEvent(PLAYER_JOINED, userID, activeGame)
+		if err = ne.ds.JoinGame(userID, activeGame.GameID, event); err != nil {
 			return "", err
 		}
+		publishGameEvent(ne.publisher, event)
+		recordAudit(ne.audit, requestIDs, &AuditEntry{ActorID: userID, Action: AUDIT_JOIN_GAME, GameID: activeGame.GameID,
+			Before: before, After: auditedGame(activeGame)})
 		return activeGame.GameID, nil
 	}
 
 	return "", nil
 }
+
+func (ne *NewGameEndpoint) recentOpponents(userID string) ([]string, error) {
+	if ne.recentOpponentsToAvoid <= 0 {
+		return nil, nil
+	}
+
+	// Games are returned by the data store in the order they were created
+	games, err := ne.ds.Games(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	opponentIDs := []string{}
+	for i := len(games) - 1; i >= 0 && len(opponentIDs) < ne.recentOpponentsToAvoid; i-- {
+		opponentID := games[i].PlayerOneID
+		if opponentID == userID {
+			opponentID = games[i].PlayerTwoID
+		}
+		if opponentID != "" {
+			opponentIDs = append(opponentIDs, opponentID)
+		}
+	}
+	return opponentIDs, nil
+}
@@ -7,159 +7,251 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"net/http"
-	"net/http/httptest"
 	"strconv"
 )
 
 var _ = Describe("newGameEndpoint", func() {
 
 	testUserID := "TestUserId"
+	testRequestID := api.RequestIDs{ID: "TestRequestId"}
 
-	var request *http.Request
-	var response *httptest.ResponseRecorder
+	Context("performAction method", func() {
 
-	BeforeEach(func() {
-		request = httptest.NewRequest(http.MethodGet, "/", nil)
-		response = httptest.NewRecorder()
-	})
+		var gameDataStoreSpy *spy.GameDataStoreSpy
+		var publisherSpy *spy.GameEventPublisherSpy
+		var auditSpy *spy.AuditSinkSpy
+		var endpoint *api.NewGameEndpoint
 
-	Context("performAction method", func() {
+		BeforeEach(func() {
+			gameDataStoreSpy = &spy.GameDataStoreSpy{}
+			publisherSpy = &spy.GameEventPublisherSpy{}
+			auditSpy = &spy.AuditSinkSpy{}
+			endpoint = api.NewNewGameEndpoint(gameDataStoreSpy, api.NewFirstWaitingGameMatchmaker(gameDataStoreSpy), 0, publisherSpy, auditSpy)
+		})
 
-		It("Should attempt to get the user from request", func() {
-			requestParserSpy := &spy.RequestParserSpy{}
-			endpoint := api.NewNewGameEndpoint(requestParserSpy, &spy.GameDataStoreSpy{})
-			endpoint.performAction(response, request)
+		It("Should ask the datastore for the users games", func() {
+			endpoint.PerformAction(testRequestID, testUserID, api.PLAYERS_ONLY)
 
-			Expect(requestParserSpy.Request).To(BeIdenticalTo(request))
+			Expect(gameDataStoreSpy.NumberOfActiveGamesUserID).To(BeIdenticalTo(testUserID))
 		})
 
-		Context("Given the user is not logged in", func() {
-			It("Should return forbidden http response", func() {
-				requestParserSpy := &spy.RequestParserSpy{Err: api.ErrInvalidJWT}
-				endpoint := api.NewNewGameEndpoint(requestParserSpy, &spy.GameDataStoreSpy{})
-				endpoint.performAction(response, request)
+		Context("And an error occurs while getting the users games", func() {
+			It("Should return an server error", func() {
+				gameDataStoreSpy.NumberOfActiveGamesErr = errors.New("Test error")
+				_, code := endpoint.PerformAction(testRequestID, testUserID, api.PLAYERS_ONLY)
 
-				Expect(response.Code).To(BeIdenticalTo(http.StatusForbidden))
+				Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
 			})
 		})
 
-		Context("Given the user is logged in", func() {
-			requestParserSpy := &spy.RequestParserSpy{UserID: testUserID}
-			var gameDataStoreSpy *spy.GameDataStoreSpy
-			var endpoint *api.NewGameEndpoint
-
+		Context("And the user already has "+strconv.Itoa(api.MAX_ACTIVE_GAMES)+" active games", func() {
 			BeforeEach(func() {
-				gameDataStoreSpy = &spy.GameDataStoreSpy{}
-				endpoint = api.NewNewGameEndpoint(requestParserSpy, gameDataStoreSpy)
+				gameDataStoreSpy.NumberOfActiveGamesReturn = api.MAX_ACTIVE_GAMES
 			})
 
-			It("Should ask the datastore for the users games", func() {
-				endpoint.performAction(response, request)
+			It("Should return an client error", func() {
+				_, code := endpoint.PerformAction(testRequestID, testUserID, api.PLAYERS_ONLY)
+				Expect(code).To(BeIdenticalTo(http.StatusBadRequest))
+			})
 
-				Expect(gameDataStoreSpy.NumberOfActiveGamesUserID).To(BeIdenticalTo(testUserID))
+			It("Should not try to get games waiting for players", func() {
+				endpoint.PerformAction(testRequestID, testUserID, api.PLAYERS_ONLY)
+				Expect(gameDataStoreSpy.GameWaitingForPlayersCalled).To(BeFalse())
 			})
 
-			Context("And an error occurs while getting the users games", func() {
-				It("Should return an server error", func() {
-					gameDataStoreSpy.NumberOfActiveGamesErr = errors.New("Test error")
-					endpoint.performAction(response, request)
+			It("Should not try to join a game", func() {
+				endpoint.PerformAction(testRequestID, testUserID, api.PLAYERS_ONLY)
+				Expect(gameDataStoreSpy.JoinGameUserID).To(BeIdenticalTo(""))
+				Expect(gameDataStoreSpy.JoinGameGameID).To(BeIdenticalTo(""))
+			})
 
-					Expect(response.Code).To(BeIdenticalTo(http.StatusInternalServerError))
-				})
+			It("Should not try to create a new game", func() {
+				endpoint.PerformAction(testRequestID, testUserID, api.PLAYERS_ONLY)
+				Expect(gameDataStoreSpy.StartNewGameUserID).To(BeIdenticalTo(""))
+			})
+		})
+
+		Context("And the user has less than "+strconv.Itoa(api.MAX_ACTIVE_GAMES)+" active games", func() {
+			BeforeEach(func() {
+				gameDataStoreSpy.NumberOfActiveGamesReturn = 0
 			})
 
-			Context("And the user already has "+strconv.Itoa(api.MAX_ACTIVE_GAMES)+" active games", func() {
+			It("Should ask for a vacant game to join", func() {
+				endpoint.PerformAction(testRequestID, testUserID, api.PLAYERS_ONLY)
+				Expect(gameDataStoreSpy.GameWaitingForPlayersCalled).To(BeTrue())
+			})
+
+			It("Should ask for a vacant game that was not started by the user", func() {
+				endpoint.PerformAction(testRequestID, testUserID, api.PLAYERS_ONLY)
+				Expect(gameDataStoreSpy.GameWaitingForPlayersUserID).To(BeIdenticalTo(testUserID))
+			})
+
+			It("Should not look up recent opponents when the endpoint lets players meet anyone", func() {
+				endpoint.PerformAction(testRequestID, testUserID, api.PLAYERS_ONLY)
+				Expect(gameDataStoreSpy.GamesUserID).To(BeEmpty())
+				Expect(gameDataStoreSpy.GameWaitingForPlayersExcludedOpponentIDs).To(BeEmpty())
+			})
+
+			Context("and the endpoint avoids recent opponents", func() {
 				BeforeEach(func() {
-					gameDataStoreSpy.NumberOfActiveGamesReturn = api.MAX_ACTIVE_GAMES
+					endpoint = api.NewNewGameEndpoint(gameDataStoreSpy, api.NewFirstWaitingGameMatchmaker(gameDataStoreSpy), 2, publisherSpy, auditSpy)
+					gameDataStoreSpy.GamesReturn = []*api.Game{
+						{PlayerOneID: testUserID, PlayerTwoID: "oldest"},
+						{PlayerOneID: "older", PlayerTwoID: testUserID},
+						{PlayerOneID: testUserID},
+						{PlayerOneID: testUserID, PlayerTwoID: "newest"},
+					}
 				})
 
-				It("Should return an client error", func() {
-					endpoint.performAction(response, request)
-					Expect(response.Code).To(BeIdenticalTo(http.StatusBadRequest))
+				It("Should not match the player against their most recent opponents", func() {
+					endpoint.PerformAction(testRequestID, testUserID, api.PLAYERS_ONLY)
+					Expect(gameDataStoreSpy.GamesUserID).To(BeIdenticalTo(testUserID))
+					Expect(gameDataStoreSpy.GameWaitingForPlayersExcludedOpponentIDs).To(Equal([]string{"newest", "older"}))
 				})
 
-				It("Should not try to get games waiting for players", func() {
-					endpoint.performAction(response, request)
-					Expect(gameDataStoreSpy.GameWaitingForPlayersCalled).To(BeFalse())
+				It("Should return an internal server error if the recent games cannot be looked up", func() {
+					gameDataStoreSpy.GamesErr = errors.New("Error getting games")
+					_, code := endpoint.PerformAction(testRequestID, testUserID, api.PLAYERS_ONLY)
+					Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
 				})
+			})
 
-				It("Should not try to join a game", func() {
-					endpoint.performAction(response, request)
-					Expect(gameDataStoreSpy.JoinGameUserID).To(BeIdenticalTo(""))
-					Expect(gameDataStoreSpy.JoinGameGameID).To(BeIdenticalTo(""))
-				})
+			It("Should join a vacant game if one exists", func() {
+				id := "vacant game id"
+				gameDataStoreSpy.GameWaitingForPlayersReturn = &api.Game{GameID: id}
+				gameID, _ := endpoint.PerformAction(testRequestID, testUserID, api.PLAYERS_ONLY)
+				Expect(gameDataStoreSpy.JoinGameGameID).To(BeIdenticalTo(id))
+				Expect(gameDataStoreSpy.JoinGameUserID).To(BeIdenticalTo(testUserID))
+				Expect(gameID).To(BeIdenticalTo(id))
+			})
 
-				It("Should not try to create a new game", func() {
-					endpoint.performAction(response, request)
-					Expect(gameDataStoreSpy.StartNewGameUserID).To(BeIdenticalTo(""))
-				})
+			It("Should tell the player waiting in the vacant game that it has started", func() {
+				gameDataStoreSpy.GameWaitingForPlayersReturn = &api.Game{GameID: "vacant game id", PlayerOneID: "waiting player", State: api.INITIALIZING}
+				endpoint.PerformAction(testRequestID, testUserID, api.PLAYERS_ONLY)
+				Expect(len(publisherSpy.PublishEvents)).To(BeIdenticalTo(1))
+				event := publisherSpy.PublishEvents[0]
+				Expect(event.Type).To(BeIdenticalTo(api.PLAYER_JOINED))
+				Expect(event.UserID).To(BeIdenticalTo(testUserID))
+				Expect(event.Game.PlayerOneID).To(BeIdenticalTo("waiting player"))
+				Expect(event.Game.PlayerTwoID).To(BeIdenticalTo(testUserID))
+				Expect(event.Game.State).To(BeIdenticalTo(api.PLAYING))
 			})
 
-			Context("And the user has less than "+strconv.Itoa(api.MAX_ACTIVE_GAMES)+" active games", func() {
-				BeforeEach(func() {
-					gameDataStoreSpy.NumberOfActiveGamesReturn = 0
-				})
+			It("Should record joining the vacant game in the audit log", func() {
+				gameDataStoreSpy.GameWaitingForPlayersReturn = &api.Game{GameID: "vacant game id", PlayerOneID: "waiting player", SerializedGame: 1234}
+				endpoint.PerformAction(testRequestID, testUserID, api.PLAYERS_ONLY)
+				Expect(len(auditSpy.RecordEntries)).To(BeIdenticalTo(1))
+				entry := auditSpy.RecordEntries[0]
+				Expect(entry.Action).To(BeIdenticalTo(api.AUDIT_JOIN_GAME))
+				Expect(entry.GameID).To(BeIdenticalTo("vacant game id"))
+				Expect(entry.Before.PlayerTwoID).To(BeEmpty())
+				Expect(entry.Before.State).To(BeIdenticalTo(api.INITIALIZING))
+				Expect(entry.After.SerializedGame).To(BeIdenticalTo(uint64(1234)))
+				Expect(entry.After.PlayerTwoID).To(BeIdenticalTo(testUserID))
+				Expect(entry.After.State).To(BeIdenticalTo(api.PLAYING))
+			})
 
-				It("Should ask for a vacant game to join", func() {
-					endpoint.performAction(response, request)
-					Expect(gameDataStoreSpy.GameWaitingForPlayersCalled).To(BeTrue())
-				})
+			It("Should publish that a new game was created", func() {
+				gameDataStoreSpy.StartNewGameReturn = "new game id"
+				endpoint.PerformAction(testRequestID, testUserID, api.PUBLIC)
+				Expect(len(publisherSpy.PublishEvents)).To(BeIdenticalTo(1))
+				event := publisherSpy.PublishEvents[0]
+				Expect(event.Type).To(BeIdenticalTo(api.GAME_CREATED))
+				Expect(event.Game.GameID).To(BeIdenticalTo("new game id"))
+				Expect(event.Game.PlayerOneID).To(BeIdenticalTo(testUserID))
+				Expect(event.Game.Visibility).To(BeIdenticalTo(api.PUBLIC))
+				Expect(gameDataStoreSpy.StartNewGameEvents).To(Equal([]*api.GameEvent{event}))
+			})
 
-				It("Should join a vacant game if one exists", func() {
-					id := "vacant game id"
-					gameDataStoreSpy.GameWaitingForPlayersReturn = &api.Game{GameID: id}
-					endpoint.performAction(response, request)
-					Expect(gameDataStoreSpy.JoinGameGameID).To(BeIdenticalTo(id))
-					Expect(gameDataStoreSpy.JoinGameUserID).To(BeIdenticalTo(testUserID))
-				})
+			It("Should record the new game in the audit log", func() {
+				gameDataStoreSpy.StartNewGameReturn = "new game id"
+				endpoint.PerformAction(testRequestID, testUserID, api.PLAYERS_ONLY)
+				Expect(len(auditSpy.RecordEntries)).To(BeIdenticalTo(1))
+				entry := auditSpy.RecordEntries[0]
+				Expect(entry.Action).To(BeIdenticalTo(api.AUDIT_NEW_GAME))
+				Expect(entry.ActorID).To(BeIdenticalTo(testUserID))
+				Expect(entry.RequestID).To(BeIdenticalTo(testRequestID.ID))
+				Expect(entry.GameID).To(BeIdenticalTo("new game id"))
+				Expect(entry.Before).To(BeNil())
+			})
+
+			It("Should not publish anything if the new game could not be started", func() {
+				gameDataStoreSpy.StartNewGameErr = errors.New("error starting game")
+				endpoint.PerformAction(testRequestID, testUserID, api.PLAYERS_ONLY)
+				Expect(publisherSpy.PublishEvents).To(BeEmpty())
+			})
+
+			It("Should not attempt to create a new game if a vacant one exist", func() {
+				id := "vacant game id second test"
+				gameDataStoreSpy.GameWaitingForPlayersReturn = &api.Game{GameID: id}
+				endpoint.PerformAction(testRequestID, testUserID, api.PLAYERS_ONLY)
+				Expect(gameDataStoreSpy.StartNewGameUserID).To(BeIdenticalTo(""))
+			})
+
+			It("Should not attempt join a vacant game if none exists", func() {
+				gameDataStoreSpy.GameWaitingForPlayersReturn = nil
+				endpoint.PerformAction(testRequestID, testUserID, api.PLAYERS_ONLY)
+				Expect(gameDataStoreSpy.JoinGameGameID).To(BeIdenticalTo(""))
+				Expect(gameDataStoreSpy.JoinGameUserID).To(BeIdenticalTo(""))
+			})
 
-				It("Should not attempt to create a new game if a vacant one exist", func() {
-					id := "vacant game id second test"
-					gameDataStoreSpy.GameWaitingForPlayersReturn = &api.Game{GameID: id}
-					endpoint.performAction(response, request)
-					Expect(gameDataStoreSpy.StartNewGameUserID).To(BeIdenticalTo(""))
+			It("Should create a new game if no vacant game exists", func() {
+				gameDataStoreSpy.GameWaitingForPlayersReturn = nil
+				endpoint.PerformAction(testRequestID, testUserID, api.PLAYERS_ONLY)
+				Expect(gameDataStoreSpy.StartNewGameUserID).To(BeIdenticalTo(testUserID))
+			})
+
+			It("Should create the new game with the chosen visibility", func() {
+				endpoint.PerformAction(testRequestID, testUserID, api.PUBLIC)
+				Expect(gameDataStoreSpy.StartNewGameVisibility).To(BeIdenticalTo(api.PUBLIC))
+			})
+
+			Context("and the only vacant game was started by the user", func() {
+				BeforeEach(func() {
+					gameDataStoreSpy.GameWaitingForPlayersReturn = &api.Game{GameID: "own game id", PlayerOneID: testUserID}
+					gameDataStoreSpy.StartNewGameReturn = "new game id"
 				})
 
-				It("Should not attempt join a vacant game if none exists", func() {
-					gameDataStoreSpy.GameWaitingForPlayersReturn = nil
-					endpoint.performAction(response, request)
+				It("Should not join the game", func() {
+					endpoint.PerformAction(testRequestID, testUserID, api.PLAYERS_ONLY)
 					Expect(gameDataStoreSpy.JoinGameGameID).To(BeIdenticalTo(""))
 					Expect(gameDataStoreSpy.JoinGameUserID).To(BeIdenticalTo(""))
 				})
 
-				It("Should create a new game if no vacant game exists", func() {
-					gameDataStoreSpy.GameWaitingForPlayersReturn = nil
-					endpoint.performAction(response, request)
+				It("Should create a new game instead", func() {
+					gameID, code := endpoint.PerformAction(testRequestID, testUserID, api.PLAYERS_ONLY)
 					Expect(gameDataStoreSpy.StartNewGameUserID).To(BeIdenticalTo(testUserID))
+					Expect(code).To(BeIdenticalTo(http.StatusOK))
+					Expect(gameID).To(BeIdenticalTo("new game id"))
 				})
+			})
 
-				It("Should return OK if no errors occurred", func() {
-					gameDataStoreSpy.GameWaitingForPlayersReturn = nil
-					gameDataStoreSpy.StartNewGameReturn = "new game id"
-					endpoint.performAction(response, request)
-					Expect(response.Code).To(BeIdenticalTo(http.StatusOK))
-					Expect(response.Body.String()).To(BeIdenticalTo("new game id"))
+			It("Should return OK if no errors occurred", func() {
+				gameDataStoreSpy.GameWaitingForPlayersReturn = nil
+				gameDataStoreSpy.StartNewGameReturn = "new game id"
+				gameID, code := endpoint.PerformAction(testRequestID, testUserID, api.PLAYERS_ONLY)
+				Expect(code).To(BeIdenticalTo(http.StatusOK))
+				Expect(gameID).To(BeIdenticalTo("new game id"))
+			})
+
+			Context("If an error occurs while calling the data store", func() {
+				It("Should return an internal server error if the datastore cannot lookup vacant games", func() {
+					gameDataStoreSpy.GameWaitingForPlayersErr = errors.New("Error getting vacant games")
+					_, code := endpoint.PerformAction(testRequestID, testUserID, api.PLAYERS_ONLY)
+					Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
+				})
+
+				It("Should return an internal server error if the datastore cannot join an existing game", func() {
+					gameDataStoreSpy.GameWaitingForPlayersReturn = &api.Game{GameID: "game id"}
+					gameDataStoreSpy.JoinGameErr = errors.New("Error joining a game")
+					_, code := endpoint.PerformAction(testRequestID, testUserID, api.PLAYERS_ONLY)
+					Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
 				})
 
-				Context("If an error occurs while calling the data store", func() {
-					It("Should return an internal server error if the datastore cannot lookup vacant games", func() {
-						gameDataStoreSpy.GameWaitingForPlayersErr = errors.New("Error getting vacant games")
-						endpoint.performAction(response, request)
-						Expect(response.Code).To(BeIdenticalTo(http.StatusInternalServerError))
-					})
-
-					It("Should return an internal server error if the datastore cannot join an existing game", func() {
-						gameDataStoreSpy.GameWaitingForPlayersReturn = &api.Game{GameID: "game id"}
-						gameDataStoreSpy.JoinGameErr = errors.New("Error joining a game")
-						endpoint.performAction(response, request)
-						Expect(response.Code).To(BeIdenticalTo(http.StatusInternalServerError))
-					})
-
-					It("Should return an internal server error if the datastore cannot create a new game", func() {
-						gameDataStoreSpy.StartNewGameErr = errors.New("Error creating new game")
-						endpoint.performAction(response, request)
-						Expect(response.Code).To(BeIdenticalTo(http.StatusInternalServerError))
-					})
+				It("Should return an internal server error if the datastore cannot create a new game", func() {
+					gameDataStoreSpy.StartNewGameErr = errors.New("Error creating new game")
+					_, code := endpoint.PerformAction(testRequestID, testUserID, api.PLAYERS_ONLY)
+					Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
 				})
 			})
 		})
@@ -0,0 +1,66 @@
+package neutrinoapi
+
+import (
+	"crypto/rand"
+	"math/big"
+	"net/http"
+)
+
+// How many times we try to come up with an unused invite code before giving up
+const maxInviteCodeAttempts = 3
+
+type PrivateGameInvite struct {
+	GameID, InviteCode string
+}
+
+type NewPrivateGameEndpoint struct {
+	ds        GameDataStore
+	publisher GameEventPublisher
+	audit     AuditSink
+}
+
+func NewNewPrivateGameEndpoint(ds GameDataStore, publisher GameEventPublisher, audit AuditSink) *NewPrivateGameEndpoint {
+	return &NewPrivateGameEndpoint{ds: ds, publisher: publisher, audit: audit}
+}
+
+func (npe *NewPrivateGameEndpoint) PerformAction(requestIDs RequestIDs, userID string, visibility Visibility) (*PrivateGameInvite, int) {
+	if eligible, statusCode := isEligibleForNewGame(npe.ds, userID); !eligible {
+		return nil, statusCode
+	}
+
+	for attempt := 0; attempt < maxInviteCodeAttempts; attempt++ {
+		inviteCode, err := generateInviteCode()
+		if err != nil {
+			return nil, http.StatusInternalServerError
+		}
+
+		event := newGameEvent(GAME_CREATED, userID, &Game{PlayerOneID: userID, State: INITIALIZING, InviteCode: inviteCode, Visibility: visibility})
+		gameID, err := npe.ds.StartPrivateGame(userID, inviteCode, visibility, event)
+		if err == ErrInviteCodeInUse {
+			continue
+		}
+		if err != nil {
+			return nil, http.StatusInternalServerError
+		}
+
+		event.Game.GameID = gameID
+		publishGameEvent(npe.publisher, event)
+		recordAudit(npe.audit, requestIDs, &AuditEntry{ActorID: userID, Action: AUDIT_NEW_PRIVATE_GAME, GameID: gameID})
+		return &PrivateGameInvite{GameID: gameID, InviteCode: inviteCode}, http.StatusOK
+	}
+
+	return nil, http.StatusInternalServerError
+}
+
+func generateInviteCode() (string, error) {
+	alphabetSize := big.NewInt(int64(len(INVITE_CODE_ALPHABET)))
+	code := make([]byte, INVITE_CODE_LENGTH)
+	for i := range code {
+		index, err := rand.Int(rand.Reader, alphabetSize)
+		if err != nil {
+			return "", err
+		}
+		code[i] = INVITE_CODE_ALPHABET[index.Int64()]
+	}
+	return string(code), nil
+}
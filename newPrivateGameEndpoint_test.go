@@ -0,0 +1,97 @@
+package neutrinoapi_test
+
+import (
+	"errors"
+	api "github.com/Morras/neutrinoapi"
+	"github.com/Morras/neutrinoapi/spy"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+	"strings"
+)
+
+var _ = Describe("newPrivateGameEndpoint", func() {
+
+	testUserID := "TestUserId"
+	testRequestID := api.RequestIDs{ID: "TestRequestId"}
+
+	var dataStoreSpy *spy.GameDataStoreSpy
+	var publisherSpy *spy.GameEventPublisherSpy
+	var auditSpy *spy.AuditSinkSpy
+	var endpoint *api.NewPrivateGameEndpoint
+
+	BeforeEach(func() {
+		dataStoreSpy = &spy.GameDataStoreSpy{}
+		publisherSpy = &spy.GameEventPublisherSpy{}
+		auditSpy = &spy.AuditSinkSpy{}
+		endpoint = api.NewNewPrivateGameEndpoint(dataStoreSpy, publisherSpy, auditSpy)
+	})
+
+	Context("performAction method", func() {
+
+		Context("Given the user already has the maximum number of active games", func() {
+			It("Should return a client error without creating a game", func() {
+				dataStoreSpy.NumberOfActiveGamesReturn = api.MAX_ACTIVE_GAMES
+				invite, code := endpoint.PerformAction(testRequestID, testUserID, api.PLAYERS_ONLY)
+				Expect(code).To(BeIdenticalTo(http.StatusBadRequest))
+				Expect(invite).To(BeNil())
+				Expect(dataStoreSpy.StartPrivateGameUserID).To(BeEmpty())
+			})
+		})
+
+		Context("Given the user can start a new game", func() {
+			It("Should start a private game with a readable invite code", func() {
+				dataStoreSpy.StartPrivateGameReturn = "private game id"
+				invite, code := endpoint.PerformAction(testRequestID, testUserID, api.PLAYERS_ONLY)
+				Expect(code).To(BeIdenticalTo(http.StatusOK))
+				Expect(dataStoreSpy.StartPrivateGameUserID).To(BeIdenticalTo(testUserID))
+				Expect(invite.GameID).To(BeIdenticalTo("private game id"))
+				Expect(invite.InviteCode).To(BeIdenticalTo(dataStoreSpy.StartPrivateGameInviteCode))
+				Expect(len(invite.InviteCode)).To(BeIdenticalTo(api.INVITE_CODE_LENGTH))
+				for _, c := range invite.InviteCode {
+					Expect(strings.ContainsRune(api.INVITE_CODE_ALPHABET, c)).To(BeTrue())
+				}
+			})
+
+			It("Should let spectators watch if the player wants", func() {
+				endpoint.PerformAction(testRequestID, testUserID, api.PUBLIC)
+				Expect(dataStoreSpy.StartPrivateGameVisibility).To(BeIdenticalTo(api.PUBLIC))
+			})
+
+			It("Should publish that a game was created", func() {
+				dataStoreSpy.StartPrivateGameReturn = "private game id"
+				invite, _ := endpoint.PerformAction(testRequestID, testUserID, api.PLAYERS_ONLY)
+				Expect(len(publisherSpy.PublishEvents)).To(BeIdenticalTo(1))
+				Expect(publisherSpy.PublishEvents[0].Type).To(BeIdenticalTo(api.GAME_CREATED))
+				Expect(publisherSpy.PublishEvents[0].Game.InviteCode).To(BeIdenticalTo(invite.InviteCode))
+			})
+
+			It("Should record the new game in the audit log", func() {
+				dataStoreSpy.StartPrivateGameReturn = "private game id"
+				endpoint.PerformAction(testRequestID, testUserID, api.PLAYERS_ONLY)
+				Expect(len(auditSpy.RecordEntries)).To(BeIdenticalTo(1))
+				Expect(auditSpy.RecordEntries[0].Action).To(BeIdenticalTo(api.AUDIT_NEW_PRIVATE_GAME))
+				Expect(auditSpy.RecordEntries[0].GameID).To(BeIdenticalTo("private game id"))
+			})
+
+			It("Should never look for or join a public game", func() {
+				endpoint.PerformAction(testRequestID, testUserID, api.PLAYERS_ONLY)
+				Expect(dataStoreSpy.GameWaitingForPlayersCalled).To(BeFalse())
+				Expect(dataStoreSpy.StartNewGameUserID).To(BeEmpty())
+			})
+
+			It("Should return an internal server error if the invite codes keep colliding", func() {
+				dataStoreSpy.StartPrivateGameErr = api.ErrInviteCodeInUse
+				invite, code := endpoint.PerformAction(testRequestID, testUserID, api.PLAYERS_ONLY)
+				Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
+				Expect(invite).To(BeNil())
+			})
+
+			It("Should return an internal server error if the game cannot be created", func() {
+				dataStoreSpy.StartPrivateGameErr = errors.New("Error creating private game")
+				_, code := endpoint.PerformAction(testRequestID, testUserID, api.PLAYERS_ONLY)
+				Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
+			})
+		})
+	})
+})
@@ -0,0 +1,251 @@
+package neutrinoapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type DevicePlatform int8
+
+const (
+	FCM DevicePlatform = iota // Android and web clients, through Firebase Cloud Messaging
+	APNS
+)
+
+var devicePlatformNames = map[DevicePlatform]string{
+	FCM:  "fcm",
+	APNS: "apns",
+}
+
+func ParseDevicePlatform(name string) (DevicePlatform, error) {
+	for platform, platformName := range devicePlatformNames {
+		if strings.EqualFold(name, platformName) {
+			return platform, nil
+		}
+	}
+	return FCM, errors.New("Unknown device platform " + name)
+}
+
+type DeviceToken struct {
+	UserID   string
+	Token    string
+	Platform DevicePlatform
+}
+
+type NotificationKind int8
+
+const (
+	YOUR_TURN NotificationKind = iota
+	OPPONENT_JOINED
+	GAME_ENDED
+)
+
+var notificationKindNames = map[NotificationKind]string{
+	YOUR_TURN:       "yourTurn",
+	OPPONENT_JOINED: "opponentJoined",
+	GAME_ENDED:      "gameEnded",
+}
+
+type Notification struct {
+	Kind   NotificationKind
+	GameID string
+	Title  string
+	Body   string
+}
+
+// NotificationPreferences lets players turn off the kinds of notifications they do not want.
+type NotificationPreferences struct {
+	YourTurn       bool
+	OpponentJoined bool
+	GameEnded      bool
+}
+
+func DefaultNotificationPreferences() *NotificationPreferences {
+	return &NotificationPreferences{YourTurn: true, OpponentJoined: true, GameEnded: true}
+}
+
+func (np *NotificationPreferences) wants(kind NotificationKind) bool {
+	switch kind {
+	case YOUR_TURN:
+		return np.YourTurn
+	case OPPONENT_JOINED:
+		return np.OpponentJoined
+	case GAME_ENDED:
+		return np.GameEnded
+	}
+	return false
+}
+
+type NotificationDataStore interface {
+	// AddDeviceToken should do nothing if the user already registered the token.
+	AddDeviceToken(device *DeviceToken) error
+	RemoveDeviceToken(userID string, token string) error
+	DeviceTokens(userID string) ([]*DeviceToken, error)
+	// NotificationPreferences should return nil if the user never changed their preferences.
+	NotificationPreferences(userID string) (*NotificationPreferences, error)
+	SetNotificationPreferences(userID string, preferences *NotificationPreferences) error
+}
+
+// Notifier sends a notification to one device, picking the payload for its platform.
+type Notifier interface {
+	Notify(device *DeviceToken, notification *Notification) error
+}
+
+type FCMMessage struct {
+	Message FCMMessageBody `json:"message"`
+}
+
+type FCMMessageBody struct {
+	Token        string            `json:"token"`
+	Notification FCMNotification   `json:"notification"`
+	Data         map[string]string `json:"data"`
+}
+
+type FCMNotification struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+// BuildFCMPayload builds the body of a Firebase Cloud Messaging HTTP v1 send request.
+func BuildFCMPayload(token string, notification *Notification) *FCMMessage {
+	return &FCMMessage{Message: FCMMessageBody{
+		Token:        token,
+		Notification: FCMNotification{Title: notification.Title, Body: notification.Body},
+		Data:         map[string]string{"kind": notificationKindNames[notification.Kind], "gameID": notification.GameID},
+	}}
+}
+
+type APNSPayload struct {
+	Aps    APNSAps `json:"aps"`
+	Kind   string  `json:"kind"`
+	GameID string  `json:"gameID"`
+}
+
+type APNSAps struct {
+	Alert APNSAlert `json:"alert"`
+	Sound string    `json:"sound,omitempty"`
+}
+
+type APNSAlert struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+// BuildAPNSPayload builds the body of an Apple Push Notification service request, the device token
+// goes in the request path rather than the body.
+func BuildAPNSPayload(notification *Notification) *APNSPayload {
+	return &APNSPayload{
+		Aps:    APNSAps{Alert: APNSAlert{Title: notification.Title, Body: notification.Body}, Sound: "default"},
+		Kind:   notificationKindNames[notification.Kind],
+		GameID: notification.GameID,
+	}
+}
+
+// LoggingNotifier prints the payload it would have sent, for running without push credentials.
+type LoggingNotifier struct{}
+
+func (ln *LoggingNotifier) Notify(device *DeviceToken, notification *Notification) error {
+	var payload interface{}
+	switch device.Platform {
+	case FCM:
+		payload = BuildFCMPayload(device.Token, notification)
+	case APNS:
+		payload = BuildAPNSPayload(notification)
+	default:
+		return fmt.Errorf("Unknown device platform %v", device.Platform)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Notifying %v on %v: %s\n", device.UserID, devicePlatformNames[device.Platform], data)
+	return nil
+}
+
+// GameEventNotifier tells players about the events in their games they were not part of: the
+// opponent moving, the opponent joining, and the game ending. Accepted challenges and rematches
+// start out with both players, so the player who sent them is told the game started.
+type GameEventNotifier struct {
+	nds      NotificationDataStore
+	notifier Notifier
+}
+
+func NewGameEventNotifier(nds NotificationDataStore, notifier Notifier) *GameEventNotifier {
+	return &GameEventNotifier{nds: nds, notifier: notifier}
+}
+
+// HandleGameEvent only fails if the data store does, a device that cannot be reached is only logged.
+func (gn *GameEventNotifier) HandleGameEvent(event *GameEvent) error {
+	game := event.Game
+	// Bots play right after the player, in the same request, so the player already knows
+	if _, botTurn := botDifficulty(event.UserID); botTurn && (event.Type == MOVE_MADE || event.Type == GAME_FINISHED) {
+		return nil
+	}
+
+	for _, userID := range []string{game.PlayerOneID, game.PlayerTwoID} {
+		if _, isBot := botDifficulty(userID); isBot || userID == "" || userID == event.UserID {
+			continue
+		}
+
+		var notification *Notification
+		switch event.Type {
+		case MOVE_MADE:
+			notification = &Notification{Kind: YOUR_TURN, Title: "Your turn", Body: "Your opponent has moved"}
+		case GAME_CREATED:
+			// Games waiting for an opponent are of no interest to anyone but the player who made them
+			if game.State != PLAYING {
+				return nil
+			}
+			notification = &Notification{Kind: OPPONENT_JOINED, Title: "Game started", Body: "Your challenge was accepted"}
+		case PLAYER_JOINED:
+			notification = &Notification{Kind: OPPONENT_JOINED, Title: "Game started", Body: "An opponent joined your game"}
+		case GAME_FINISHED:
+			notification = &Notification{Kind: GAME_ENDED, Title: "Game over", Body: gameEndedText(game, userID)}
+		default:
+			return nil
+		}
+		notification.GameID = game.GameID
+
+		if err := gn.notify(userID, notification); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func gameEndedText(game *Game, userID string) string {
+	switch game.WinnerID {
+	case "":
+		return "Your game ended in a draw"
+	case userID:
+		return "You won your game"
+	}
+	return "You lost your game"
+}
+
+func (gn *GameEventNotifier) notify(userID string, notification *Notification) error {
+	preferences, err := gn.nds.NotificationPreferences(userID)
+	if err != nil {
+		return err
+	}
+	if preferences == nil {
+		preferences = DefaultNotificationPreferences()
+	}
+	if !preferences.wants(notification.Kind) {
+		return nil
+	}
+
+	devices, err := gn.nds.DeviceTokens(userID)
+	if err != nil {
+		return err
+	}
+	for _, device := range devices {
+		if err = gn.notifier.Notify(device, notification); err != nil {
+			fmt.Printf("Error notifying %v on device %v: %v\n", userID, device.Token, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package neutrinoapi_test
+
+import (
+	"encoding/json"
+	"errors"
+	api "github.com/Morras/neutrinoapi"
+	"github.com/Morras/neutrinoapi/spy"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("notifications", func() {
+
+	testUserID := "TestUserId"
+	opponentID := "OpponentUserId"
+
+	Context("GameEventNotifier", func() {
+		var dataStoreSpy *spy.NotificationDataStoreSpy
+		var notifierSpy *spy.NotifierSpy
+		var notifier *api.GameEventNotifier
+		var game *api.Game
+
+		BeforeEach(func() {
+			dataStoreSpy = &spy.NotificationDataStoreSpy{}
+			dataStoreSpy.DeviceTokensReturn = []*api.DeviceToken{{UserID: opponentID, Token: "phone"}, {UserID: opponentID, Token: "tablet", Platform: api.APNS}}
+			notifierSpy = &spy.NotifierSpy{}
+			notifier = api.NewGameEventNotifier(dataStoreSpy, notifierSpy)
+			game = &api.Game{GameID: "game id", PlayerOneID: testUserID, PlayerTwoID: opponentID, State: api.PLAYING}
+		})
+
+		It("Should tell the opponent it is their turn on every device", func() {
+			err := notifier.HandleGameEvent(&api.GameEvent{Type: api.MOVE_MADE, UserID: testUserID, Game: game})
+			Expect(err).To(BeNil())
+			Expect(dataStoreSpy.DeviceTokensUserIDs).To(Equal([]string{opponentID}))
+			Expect(len(notifierSpy.NotifyDevices)).To(BeIdenticalTo(2))
+			Expect(notifierSpy.NotifyNotifications[0].Kind).To(BeIdenticalTo(api.YOUR_TURN))
+			Expect(notifierSpy.NotifyNotifications[0].GameID).To(BeIdenticalTo("game id"))
+		})
+
+		It("Should tell the waiting player that an opponent joined", func() {
+			notifier.HandleGameEvent(&api.GameEvent{Type: api.PLAYER_JOINED, UserID: opponentID, Game: game})
+			Expect(dataStoreSpy.DeviceTokensUserIDs).To(Equal([]string{testUserID}))
+			Expect(notifierSpy.NotifyNotifications[0].Kind).To(BeIdenticalTo(api.OPPONENT_JOINED))
+		})
+
+		It("Should tell the player who did not finish the game how it ended", func() {
+			game.State = api.DONE
+			game.WinnerID = opponentID
+			notifier.HandleGameEvent(&api.GameEvent{Type: api.GAME_FINISHED, UserID: testUserID, Game: game})
+			Expect(dataStoreSpy.DeviceTokensUserIDs).To(Equal([]string{opponentID}))
+			Expect(notifierSpy.NotifyNotifications[0].Kind).To(BeIdenticalTo(api.GAME_ENDED))
+			Expect(notifierSpy.NotifyNotifications[0].Body).To(ContainSubstring("won"))
+		})
+
+		It("Should never notify bots", func() {
+			game.PlayerTwoID = api.BotUserID(api.EASY)
+			notifier.HandleGameEvent(&api.GameEvent{Type: api.MOVE_MADE, UserID: testUserID, Game: game})
+			Expect(dataStoreSpy.DeviceTokensUserIDs).To(BeEmpty())
+		})
+
+		It("Should not notify anyone about games waiting for an opponent", func() {
+			game.State = api.INITIALIZING
+			game.PlayerTwoID = ""
+			notifier.HandleGameEvent(&api.GameEvent{Type: api.GAME_CREATED, UserID: testUserID, Game: game})
+			Expect(notifierSpy.NotifyDevices).To(BeEmpty())
+		})
+
+		It("Should tell the challenger that a game created by accepting their challenge has started", func() {
+			notifier.HandleGameEvent(&api.GameEvent{Type: api.GAME_CREATED, UserID: opponentID, Game: game})
+			Expect(dataStoreSpy.DeviceTokensUserIDs).To(Equal([]string{testUserID}))
+			Expect(notifierSpy.NotifyNotifications[0].Kind).To(BeIdenticalTo(api.OPPONENT_JOINED))
+		})
+
+		It("Should not tell the player it is their turn when the bot answers their move", func() {
+			game.PlayerTwoID = api.BotUserID(api.EASY)
+			notifier.HandleGameEvent(&api.GameEvent{Type: api.MOVE_MADE, UserID: game.PlayerTwoID, Game: game})
+			Expect(dataStoreSpy.DeviceTokensUserIDs).To(BeEmpty())
+		})
+
+		It("Should respect the players preferences", func() {
+			dataStoreSpy.NotificationPreferencesReturn = &api.NotificationPreferences{YourTurn: false, OpponentJoined: true, GameEnded: true}
+			notifier.HandleGameEvent(&api.GameEvent{Type: api.MOVE_MADE, UserID: testUserID, Game: game})
+			Expect(notifierSpy.NotifyDevices).To(BeEmpty())
+		})
+
+		It("Should keep notifying the other devices if one of them fails", func() {
+			notifierSpy.NotifyErr = errors.New("error notifying")
+			err := notifier.HandleGameEvent(&api.GameEvent{Type: api.MOVE_MADE, UserID: testUserID, Game: game})
+			Expect(err).To(BeNil())
+			Expect(len(notifierSpy.NotifyDevices)).To(BeIdenticalTo(2))
+		})
+
+		It("Should return an error if the devices could not be looked up", func() {
+			dataStoreSpy.DeviceTokensErr = errors.New("error getting devices")
+			err := notifier.HandleGameEvent(&api.GameEvent{Type: api.MOVE_MADE, UserID: testUserID, Game: game})
+			Expect(err).ToNot(BeNil())
+		})
+	})
+
+	Context("Payload builders", func() {
+		notification := &api.Notification{Kind: api.YOUR_TURN, GameID: "game id", Title: "Your turn", Body: "Your opponent has moved"}
+
+		It("Should build an FCM message for the device", func() {
+			data, _ := json.Marshal(api.BuildFCMPayload("device token", notification))
+			Expect(data).To(MatchJSON(`{"message": {"token": "device token",
+				"notification": {"title": "Your turn", "body": "Your opponent has moved"},
+				"data": {"kind": "yourTurn", "gameID": "game id"}}}`))
+		})
+
+		It("Should build an APNs payload", func() {
+			data, _ := json.Marshal(api.BuildAPNSPayload(notification))
+			Expect(data).To(MatchJSON(`{"aps": {"alert": {"title": "Your turn", "body": "Your opponent has moved"}, "sound": "default"},
+				"kind": "yourTurn", "gameID": "game id"}`))
+		})
+	})
+
+	Context("ParseDevicePlatform", func() {
+		It("Should parse the platform names regardless of case", func() {
+			platform, err := api.ParseDevicePlatform("APNs")
+			Expect(err).To(BeNil())
+			Expect(platform).To(BeIdenticalTo(api.APNS))
+		})
+
+		It("Should return an error for unknown platforms", func() {
+			_, err := api.ParseDevicePlatform("pager")
+			Expect(err).ToNot(BeNil())
+		})
+	})
+})
@@ -0,0 +1,93 @@
+package neutrinoapi
+
+import (
+	"fmt"
+	"time"
+)
+
+type OutboxEntry struct {
+	EntryID string
+	Event   *GameEvent
+}
+
+// OutboxDataStore keeps events until they have been published. Most events are added by the game
+// data store, in the same transaction as the change they are about.
+type OutboxDataStore interface {
+	AddToOutbox(event *GameEvent) error
+	// OutboxEntries returns up to count entries, oldest first.
+	OutboxEntries(count int) ([]*OutboxEntry, error)
+	// UpdateOutboxEntry saves the HandledBy of an event that only some handlers could take.
+	UpdateOutboxEntry(entryID string, event *GameEvent) error
+	RemoveFromOutbox(entryID string) error
+}
+
+// OutboxPublisher makes sure events are not lost when publishing fails after the game has been
+// saved. Events saved along with their game are removed from the outbox once they are published,
+// and RetryOutbox publishes whatever is left later. Handlers can get an event twice if the server
+// stops after publishing it but before removing it, or before saving which handlers it reached.
+type OutboxPublisher struct {
+	publisher GameEventPublisher
+	ods       OutboxDataStore
+}
+
+func NewOutboxPublisher(publisher GameEventPublisher, ods OutboxDataStore) *OutboxPublisher {
+	return &OutboxPublisher{publisher: publisher, ods: ods}
+}
+
+// Publish only fails if the event could neither be published nor kept in the outbox. Events that
+// were not saved along with their game are put in the outbox if publishing them fails.
+func (op *OutboxPublisher) Publish(event *GameEvent) error {
+	handled := len(event.HandledBy)
+	err := op.publisher.Publish(event)
+	if err == nil {
+		if event.OutboxEntryID == "" {
+			return nil
+		}
+		return op.ods.RemoveFromOutbox(event.OutboxEntryID)
+	}
+	if event.OutboxEntryID != "" {
+		fmt.Printf("Error publishing outbox entry %v, leaving it for the next retry: %v\n", event.OutboxEntryID, err)
+		return op.updateHandledBy(event.OutboxEntryID, event, handled)
+	}
+	fmt.Printf("Error publishing event for game %v, adding it to the outbox: %v\n", event.Game.GameID, err)
+	return op.ods.AddToOutbox(event)
+}
+
+// RetryOutbox publishes the oldest events in the outbox again, and returns how many made it.
+// Events that fail again are left for the next run, and only go to the handlers that failed. Events newer than OUTBOX_RETRY_AFTER are left
+// to the request that saved them, which is most likely still publishing them.
+func (op *OutboxPublisher) RetryOutbox() (int, error) {
+	entries, err := op.ods.OutboxEntries(OUTBOX_RETRY_BATCH_SIZE)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	for _, entry := range entries {
+		// Entries come oldest first, so the rest are too new as well
+		if time.Since(entry.Event.Occurred) < OUTBOX_RETRY_AFTER {
+			break
+		}
+		handled := len(entry.Event.HandledBy)
+		if err = op.publisher.Publish(entry.Event); err != nil {
+			fmt.Printf("Error publishing outbox entry %v: %v\n", entry.EntryID, err)
+			if err = op.updateHandledBy(entry.EntryID, entry.Event, handled); err != nil {
+				return published, err
+			}
+			continue
+		}
+		if err = op.ods.RemoveFromOutbox(entry.EntryID); err != nil {
+			return published, err
+		}
+		published++
+	}
+	return published, nil
+}
+
+// Saving the handlers that got the event is only worth a write if more of them got it this time.
+func (op *OutboxPublisher) updateHandledBy(entryID string, event *GameEvent, handledBefore int) error {
+	if len(event.HandledBy) == handledBefore {
+		return nil
+	}
+	return op.ods.UpdateOutboxEntry(entryID, event)
+}
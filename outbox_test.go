@@ -0,0 +1,170 @@
+package neutrinoapi_test
+
+import (
+	"errors"
+	api "github.com/Morras/neutrinoapi"
+	"github.com/Morras/neutrinoapi/spy"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"time"
+)
+
+var _ = Describe("outboxPublisher", func() {
+
+	var publisherSpy *spy.GameEventPublisherSpy
+	var outboxSpy *spy.OutboxDataStoreSpy
+	var publisher *api.OutboxPublisher
+	var event *api.GameEvent
+
+	BeforeEach(func() {
+		publisherSpy = &spy.GameEventPublisherSpy{}
+		outboxSpy = &spy.OutboxDataStoreSpy{}
+		publisher = api.NewOutboxPublisher(publisherSpy, outboxSpy)
+		event = &api.GameEvent{Type: api.MOVE_MADE, Game: &api.Game{GameID: "game id"}}
+	})
+
+	Context("Publish method", func() {
+		It("Should publish the event", func() {
+			err := publisher.Publish(event)
+			Expect(err).To(BeNil())
+			Expect(publisherSpy.PublishEvents).To(Equal([]*api.GameEvent{event}))
+			Expect(outboxSpy.AddToOutboxEvent).To(BeNil())
+		})
+
+		It("Should put the event in the outbox if it could not be published", func() {
+			publisherSpy.PublishErr = errors.New("error publishing")
+			err := publisher.Publish(event)
+			Expect(err).To(BeNil())
+			Expect(outboxSpy.AddToOutboxEvent).To(BeIdenticalTo(event))
+		})
+
+		It("Should return an error if the event could not be put in the outbox either", func() {
+			publisherSpy.PublishErr = errors.New("error publishing")
+			outboxSpy.AddToOutboxErr = errors.New("error adding to outbox")
+			err := publisher.Publish(event)
+			Expect(err).ToNot(BeNil())
+		})
+
+		Context("and the event was saved along with its game", func() {
+			BeforeEach(func() {
+				event.OutboxEntryID = "entry id"
+			})
+
+			It("Should remove the event from the outbox once it is published", func() {
+				err := publisher.Publish(event)
+				Expect(err).To(BeNil())
+				Expect(outboxSpy.RemoveFromOutboxEntryIDs).To(Equal([]string{"entry id"}))
+			})
+
+			It("Should leave the event in the outbox if it could not be published", func() {
+				publisherSpy.PublishErr = errors.New("error publishing")
+				err := publisher.Publish(event)
+				Expect(err).To(BeNil())
+				Expect(outboxSpy.RemoveFromOutboxEntryIDs).To(BeEmpty())
+				Expect(outboxSpy.AddToOutboxEvent).To(BeNil())
+				Expect(outboxSpy.UpdateOutboxEntryEntryIDs).To(BeEmpty())
+			})
+
+			It("Should save which handlers got the event if only some of them did", func() {
+				inProcess := api.NewInProcessGameEventPublisher()
+				inProcess.Subscribe("failing", &gameEventHandlerStub{err: errors.New("error handling event")})
+				inProcess.Subscribe("other", &gameEventHandlerStub{})
+				publisher = api.NewOutboxPublisher(inProcess, outboxSpy)
+
+				err := publisher.Publish(event)
+				Expect(err).To(BeNil())
+				Expect(outboxSpy.RemoveFromOutboxEntryIDs).To(BeEmpty())
+				Expect(outboxSpy.UpdateOutboxEntryEntryIDs).To(Equal([]string{"entry id"}))
+				Expect(outboxSpy.UpdateOutboxEntryEvents[0].HandledBy).To(Equal([]string{"other"}))
+			})
+
+			It("Should return an error if the event could not be removed from the outbox", func() {
+				outboxSpy.RemoveFromOutboxErr = errors.New("error removing from outbox")
+				err := publisher.Publish(event)
+				Expect(err).ToNot(BeNil())
+			})
+		})
+	})
+
+	Context("RetryOutbox method", func() {
+		BeforeEach(func() {
+			event.Occurred = time.Now().Add(-2 * api.OUTBOX_RETRY_AFTER)
+			outboxSpy.OutboxEntriesReturn = []*api.OutboxEntry{
+				{EntryID: "first", Event: event},
+				{EntryID: "second", Event: event},
+			}
+		})
+
+		It("Should publish the oldest entries in batches", func() {
+			published, err := publisher.RetryOutbox()
+			Expect(err).To(BeNil())
+			Expect(published).To(BeIdenticalTo(2))
+			Expect(outboxSpy.OutboxEntriesCount).To(BeIdenticalTo(api.OUTBOX_RETRY_BATCH_SIZE))
+			Expect(len(publisherSpy.PublishEvents)).To(BeIdenticalTo(2))
+		})
+
+		It("Should remove the entries that were published", func() {
+			publisher.RetryOutbox()
+			Expect(outboxSpy.RemoveFromOutboxEntryIDs).To(Equal([]string{"first", "second"}))
+		})
+
+		It("Should leave entries that still cannot be published in the outbox", func() {
+			publisherSpy.PublishErr = errors.New("error publishing")
+			published, err := publisher.RetryOutbox()
+			Expect(err).To(BeNil())
+			Expect(published).To(BeIdenticalTo(0))
+			Expect(outboxSpy.RemoveFromOutboxEntryIDs).To(BeEmpty())
+		})
+
+		It("Should only give entries to the handlers that did not get them yet", func() {
+			failing := &gameEventHandlerStub{err: errors.New("error handling event")}
+			other := &gameEventHandlerStub{}
+			handled := &gameEventHandlerStub{}
+			inProcess := api.NewInProcessGameEventPublisher()
+			inProcess.Subscribe("failing", failing)
+			inProcess.Subscribe("other", other)
+			inProcess.Subscribe("handled", handled)
+			publisher = api.NewOutboxPublisher(inProcess, outboxSpy)
+			event.HandledBy = []string{"handled"}
+			outboxSpy.OutboxEntriesReturn = outboxSpy.OutboxEntriesReturn[:1]
+
+			published, err := publisher.RetryOutbox()
+			Expect(err).To(BeNil())
+			Expect(published).To(BeIdenticalTo(0))
+			Expect(handled.events).To(BeEmpty())
+			Expect(outboxSpy.UpdateOutboxEntryEntryIDs).To(Equal([]string{"first"}))
+			Expect(outboxSpy.UpdateOutboxEntryEvents[0].HandledBy).To(Equal([]string{"handled", "other"}))
+
+			failing.err = nil
+			published, _ = publisher.RetryOutbox()
+			Expect(published).To(BeIdenticalTo(1))
+			Expect(len(other.events)).To(BeIdenticalTo(1))
+			Expect(outboxSpy.RemoveFromOutboxEntryIDs).To(Equal([]string{"first"}))
+		})
+
+		It("Should return an error if the handlers that got an entry could not be saved", func() {
+			inProcess := api.NewInProcessGameEventPublisher()
+			inProcess.Subscribe("failing", &gameEventHandlerStub{err: errors.New("error handling event")})
+			inProcess.Subscribe("other", &gameEventHandlerStub{})
+			publisher = api.NewOutboxPublisher(inProcess, outboxSpy)
+			outboxSpy.UpdateOutboxEntryErr = errors.New("error updating outbox entry")
+
+			_, err := publisher.RetryOutbox()
+			Expect(err).ToNot(BeNil())
+		})
+
+		It("Should leave recent entries to the request that saved them", func() {
+			recent := &api.GameEvent{Type: api.MOVE_MADE, Game: &api.Game{GameID: "game id"}, Occurred: time.Now()}
+			outboxSpy.OutboxEntriesReturn = append(outboxSpy.OutboxEntriesReturn, &api.OutboxEntry{EntryID: "third", Event: recent})
+			published, _ := publisher.RetryOutbox()
+			Expect(published).To(BeIdenticalTo(2))
+			Expect(outboxSpy.RemoveFromOutboxEntryIDs).To(Equal([]string{"first", "second"}))
+		})
+
+		It("Should return an error if the outbox could not be read", func() {
+			outboxSpy.OutboxEntriesErr = errors.New("error reading outbox")
+			_, err := publisher.RetryOutbox()
+			Expect(err).ToNot(BeNil())
+		})
+	})
+})
@@ -0,0 +1,71 @@
+package neutrinoapi
+
+import "sync"
+
+// PlayerEventStreams hands game events to the players of the game, and to spectators of it, if they
+// are connected to this server. Subscribers that do not keep up miss events rather than hold up everyone else.
+type PlayerEventStreams struct {
+	mutex       sync.Mutex
+	subscribers map[string]map[chan *GameEvent]bool
+	spectators  map[string]map[chan *GameEvent]bool // By game rather than by player
+}
+
+func NewPlayerEventStreams() *PlayerEventStreams {
+	return &PlayerEventStreams{subscribers: map[string]map[chan *GameEvent]bool{}, spectators: map[string]map[chan *GameEvent]bool{}}
+}
+
+// Subscribe returns the events for every game the player is in, until unsubscribe is called.
+func (streams *PlayerEventStreams) Subscribe(userID string) (events <-chan *GameEvent, unsubscribe func()) {
+	return streams.subscribe(streams.subscribers, userID)
+}
+
+// SubscribeToGame returns the events for a single game, with the game as spectators see it. It is up
+// to the caller to make sure the subscriber is allowed to watch the game.
+func (streams *PlayerEventStreams) SubscribeToGame(gameID string) (events <-chan *GameEvent, unsubscribe func()) {
+	return streams.subscribe(streams.spectators, gameID)
+}
+
+func (streams *PlayerEventStreams) subscribe(subscribers map[string]map[chan *GameEvent]bool, key string) (<-chan *GameEvent, func()) {
+	channel := make(chan *GameEvent, GAME_EVENT_BUFFER_SIZE)
+
+	streams.mutex.Lock()
+	defer streams.mutex.Unlock()
+	if subscribers[key] == nil {
+		subscribers[key] = map[chan *GameEvent]bool{}
+	}
+	subscribers[key][channel] = true
+
+	return channel, func() {
+		streams.mutex.Lock()
+		defer streams.mutex.Unlock()
+		delete(subscribers[key], channel)
+		if len(subscribers[key]) == 0 {
+			delete(subscribers, key)
+		}
+	}
+}
+
+func (streams *PlayerEventStreams) HandleGameEvent(event *GameEvent) error {
+	streams.mutex.Lock()
+	defer streams.mutex.Unlock()
+
+	for _, userID := range []string{event.Game.PlayerOneID, event.Game.PlayerTwoID} {
+		handOut(streams.subscribers[userID], event)
+	}
+
+	if spectators := streams.spectators[event.Game.GameID]; len(spectators) > 0 {
+		spectatorEvent := *event
+		spectatorEvent.Game = event.Game.SpectatorView()
+		handOut(spectators, &spectatorEvent)
+	}
+	return nil
+}
+
+func handOut(channels map[chan *GameEvent]bool, event *GameEvent) {
+	for channel := range channels {
+		select {
+		case channel <- event:
+		default:
+		}
+	}
+}
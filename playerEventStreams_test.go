@@ -0,0 +1,88 @@
+package neutrinoapi_test
+
+import (
+	api "github.com/Morras/neutrinoapi"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PlayerEventStreams", func() {
+
+	var streams *api.PlayerEventStreams
+	var event *api.GameEvent
+
+	BeforeEach(func() {
+		streams = api.NewPlayerEventStreams()
+		event = &api.GameEvent{Type: api.MOVE_MADE, Game: &api.Game{GameID: "game id", PlayerOneID: "one", PlayerTwoID: "two"}}
+	})
+
+	It("Should hand events to both players of the game", func() {
+		one, _ := streams.Subscribe("one")
+		two, _ := streams.Subscribe("two")
+		streams.HandleGameEvent(event)
+		Expect(<-one).To(BeIdenticalTo(event))
+		Expect(<-two).To(BeIdenticalTo(event))
+	})
+
+	It("Should hand events to every subscription the player has", func() {
+		first, _ := streams.Subscribe("one")
+		second, _ := streams.Subscribe("one")
+		streams.HandleGameEvent(event)
+		Expect(first).To(Receive())
+		Expect(second).To(Receive())
+	})
+
+	It("Should not hand events to players outside the game", func() {
+		other, _ := streams.Subscribe("other")
+		streams.HandleGameEvent(event)
+		Expect(other).ToNot(Receive())
+	})
+
+	It("Should stop handing out events after unsubscribing", func() {
+		one, unsubscribe := streams.Subscribe("one")
+		unsubscribe()
+		streams.HandleGameEvent(event)
+		Expect(one).ToNot(Receive())
+	})
+
+	Context("SubscribeToGame", func() {
+		It("Should hand out the events of the game to spectators", func() {
+			spectator, _ := streams.SubscribeToGame("game id")
+			streams.HandleGameEvent(event)
+			var received *api.GameEvent
+			Expect(spectator).To(Receive(&received))
+			Expect(received.Game.GameID).To(BeIdenticalTo("game id"))
+		})
+
+		It("Should not show spectators the invite code", func() {
+			event.Game.InviteCode = "ABC234"
+			spectator, _ := streams.SubscribeToGame("game id")
+			streams.HandleGameEvent(event)
+			var received *api.GameEvent
+			Expect(spectator).To(Receive(&received))
+			Expect(received.Game.InviteCode).To(BeEmpty())
+			Expect(event.Game.InviteCode).To(BeIdenticalTo("ABC234"))
+		})
+
+		It("Should not hand out the events of other games", func() {
+			spectator, _ := streams.SubscribeToGame("other game id")
+			streams.HandleGameEvent(event)
+			Expect(spectator).ToNot(Receive())
+		})
+
+		It("Should stop handing out events after unsubscribing", func() {
+			spectator, unsubscribe := streams.SubscribeToGame("game id")
+			unsubscribe()
+			streams.HandleGameEvent(event)
+			Expect(spectator).ToNot(Receive())
+		})
+	})
+
+	It("Should drop events for subscribers who do not keep up rather than block", func() {
+		one, _ := streams.Subscribe("one")
+		for i := 0; i < api.GAME_EVENT_BUFFER_SIZE+1; i++ {
+			streams.HandleGameEvent(event)
+		}
+		Expect(len(one)).To(BeIdenticalTo(api.GAME_EVENT_BUFFER_SIZE))
+	})
+})
@@ -0,0 +1,56 @@
+package neutrinoapi
+
+import (
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+type PostChatMessageEndpoint struct {
+	ds     GameDataStore
+	cds    ChatDataStore
+	filter MessageFilter
+}
+
+func NewPostChatMessageEndpoint(ds GameDataStore, cds ChatDataStore, filter MessageFilter) *PostChatMessageEndpoint {
+	return &PostChatMessageEndpoint{ds: ds, cds: cds, filter: filter}
+}
+
+func (pce *PostChatMessageEndpoint) PerformAction(userID string, chatReq *ChatMessageRequest) (string, int) {
+	text := strings.TrimSpace(chatReq.Text)
+	if text == "" || utf8.RuneCountInString(text) > MAX_CHAT_MESSAGE_LENGTH {
+		return "", http.StatusBadRequest
+	}
+
+	if statusCode := checkChatParticipant(pce.ds, userID, chatReq.GameID); statusCode != http.StatusOK {
+		return "", statusCode
+	}
+
+	text, err := pce.filter.Filter(text)
+	if err != nil {
+		return "", http.StatusBadRequest
+	}
+
+	message := &ChatMessage{GameID: chatReq.GameID, SenderID: userID, Text: text, Sent: time.Now()}
+	messageID, err := pce.cds.AddChatMessage(message)
+	if err != nil {
+		return "", http.StatusInternalServerError
+	}
+	return messageID, http.StatusOK
+}
+
+// Chat is only between the two players, spectators of public games cannot read or post.
+func checkChatParticipant(ds GameDataStore, userID string, gameID string) int {
+	dsGame, err := ds.Game(gameID)
+	if err != nil {
+		return http.StatusInternalServerError
+	}
+	if dsGame == nil {
+		return http.StatusNotFound
+	}
+	if dsGame.PlayerOneID != userID && dsGame.PlayerTwoID != userID {
+		return http.StatusForbidden
+	}
+	return http.StatusOK
+}
@@ -0,0 +1,91 @@
+package neutrinoapi_test
+
+import (
+	"errors"
+	api "github.com/Morras/neutrinoapi"
+	"github.com/Morras/neutrinoapi/spy"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+	"strings"
+)
+
+var _ = Describe("postChatMessageEndpoint", func() {
+
+	testUserID := "TestUserId"
+
+	var dataStoreSpy *spy.GameDataStoreSpy
+	var chatDataStoreSpy *spy.ChatDataStoreSpy
+	var filterSpy *spy.MessageFilterSpy
+	var endpoint *api.PostChatMessageEndpoint
+	var chatReq *api.ChatMessageRequest
+
+	BeforeEach(func() {
+		dataStoreSpy = &spy.GameDataStoreSpy{}
+		chatDataStoreSpy = &spy.ChatDataStoreSpy{}
+		filterSpy = &spy.MessageFilterSpy{FilterReturn: "filtered text"}
+		endpoint = api.NewPostChatMessageEndpoint(dataStoreSpy, chatDataStoreSpy, filterSpy)
+		dataStoreSpy.GameReturn = &api.Game{GameID: "game id", PlayerOneID: "opponent", PlayerTwoID: testUserID}
+		chatReq = &api.ChatMessageRequest{GameID: "game id", Text: " Good luck "}
+	})
+
+	Context("performAction method", func() {
+
+		It("Should store the filtered message", func() {
+			chatDataStoreSpy.AddChatMessageReturn = "message id"
+			messageID, code := endpoint.PerformAction(testUserID, chatReq)
+			Expect(code).To(BeIdenticalTo(http.StatusOK))
+			Expect(messageID).To(BeIdenticalTo("message id"))
+			Expect(filterSpy.FilterText).To(BeIdenticalTo("Good luck"))
+
+			message := chatDataStoreSpy.AddChatMessageMessage
+			Expect(message.GameID).To(BeIdenticalTo("game id"))
+			Expect(message.SenderID).To(BeIdenticalTo(testUserID))
+			Expect(message.Text).To(BeIdenticalTo("filtered text"))
+			Expect(message.Sent.IsZero()).To(BeFalse())
+		})
+
+		It("Should return bad request for an empty message", func() {
+			chatReq.Text = "   "
+			_, code := endpoint.PerformAction(testUserID, chatReq)
+			Expect(code).To(BeIdenticalTo(http.StatusBadRequest))
+			Expect(chatDataStoreSpy.AddChatMessageMessage).To(BeNil())
+		})
+
+		It("Should return bad request for a message that is too long", func() {
+			chatReq.Text = strings.Repeat("a", api.MAX_CHAT_MESSAGE_LENGTH+1)
+			_, code := endpoint.PerformAction(testUserID, chatReq)
+			Expect(code).To(BeIdenticalTo(http.StatusBadRequest))
+		})
+
+		It("Should count characters rather than bytes", func() {
+			chatReq.Text = strings.Repeat("æ", api.MAX_CHAT_MESSAGE_LENGTH)
+			_, code := endpoint.PerformAction(testUserID, chatReq)
+			Expect(code).To(BeIdenticalTo(http.StatusOK))
+		})
+
+		It("Should return bad request if the filter rejects the message", func() {
+			filterSpy.FilterErr = errors.New("Rejected")
+			_, code := endpoint.PerformAction(testUserID, chatReq)
+			Expect(code).To(BeIdenticalTo(http.StatusBadRequest))
+			Expect(chatDataStoreSpy.AddChatMessageMessage).To(BeNil())
+		})
+
+		It("Should return forbidden to users who are not playing the game", func() {
+			_, code := endpoint.PerformAction("spectator", chatReq)
+			Expect(code).To(BeIdenticalTo(http.StatusForbidden))
+		})
+
+		It("Should return not found if the game does not exist", func() {
+			dataStoreSpy.GameReturn = nil
+			_, code := endpoint.PerformAction(testUserID, chatReq)
+			Expect(code).To(BeIdenticalTo(http.StatusNotFound))
+		})
+
+		It("Should return an internal server error if the message cannot be stored", func() {
+			chatDataStoreSpy.AddChatMessageErr = errors.New("Error adding message")
+			_, code := endpoint.PerformAction(testUserID, chatReq)
+			Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
+		})
+	})
+})
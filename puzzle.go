@@ -0,0 +1,101 @@
+package neutrinoapi
+
+import (
+	"fmt"
+	"github.com/Morras/go-neutrino/game"
+	"time"
+)
+
+// Puzzle is a position from a real game where the player to move can force a win.
+type Puzzle struct {
+	PuzzleID         string
+	SerializedGame   uint64
+	Plies            int // The player to move wins in this many turns with the right play
+	WinningCondition WinningCondition
+	SourceGameID     string
+	Rating           int // Puzzles are rated like players, going up when players fail them
+}
+
+type PuzzleAttempt struct {
+	UserID, PuzzleID                             string
+	Solved                                       bool
+	RatingBefore, RatingAfter, PuzzleRatingAfter int
+	Time                                         time.Time
+}
+
+// PuzzleMiner looks through finished games for positions that make good puzzles.
+type PuzzleMiner struct {
+	ds       GameDataStore
+	pds      PuzzleDataStore
+	analyzer Analyzer
+}
+
+func NewPuzzleMiner(ds GameDataStore, pds PuzzleDataStore, analyzer Analyzer) *PuzzleMiner {
+	return &PuzzleMiner{ds: ds, pds: pds, analyzer: analyzer}
+}
+
+// MineGames searches every game finished after since, and returns how many puzzles were found.
+// The search is far too slow to run while players wait on a move, so this is meant to be run on a schedule.
+func (pm *PuzzleMiner) MineGames(since time.Time) (int, error) {
+	games, err := pm.ds.FinishedGames(since)
+	if err != nil {
+		return 0, err
+	}
+
+	found := 0
+	for _, dsGame := range games {
+		puzzles, err := pm.MineGame(dsGame)
+		if err != nil {
+			// One bad game should not stop the rest from being mined
+			fmt.Printf("Error mining puzzles from game %v: %v\n", dsGame.GameID, err)
+			continue
+		}
+		found += puzzles
+	}
+	return found, nil
+}
+
+func (pm *PuzzleMiner) MineGame(dsGame *Game) (int, error) {
+	// Puzzles are shown to everyone, so only point back at games anyone is allowed to watch
+	sourceGameID := ""
+	if dsGame.Visibility == PUBLIC {
+		sourceGameID = dsGame.GameID
+	}
+
+	found := 0
+	// The first turn of a game has no neutrino move, and is never a forced win anyway
+	for turn := 1; turn < len(dsGame.History); turn++ {
+		position := game.UInt64ToGame(dsGame.History[turn])
+		analysis, err := pm.analyzer.Analyze(position, PUZZLE_MAX_PLIES)
+		if err != nil {
+			return found, err
+		}
+		if !analysis.ForcedWin {
+			continue
+		}
+
+		puzzle := &Puzzle{
+			SerializedGame:   dsGame.History[turn],
+			Plies:            analysis.Plies,
+			WinningCondition: analysis.WinningCondition,
+			SourceGameID:     sourceGameID,
+			Rating:           INITIAL_RATING + (analysis.Plies-1)*PUZZLE_RATING_PER_PLY,
+		}
+		if err = pm.pds.SavePuzzle(puzzle); err != nil {
+			return found, err
+		}
+		found++
+	}
+	return found, nil
+}
+
+func puzzleRatingOrDefault(pds PuzzleDataStore, userID string) (*Rating, error) {
+	rating, err := pds.PuzzleRating(userID)
+	if err != nil {
+		return nil, err
+	}
+	if rating == nil {
+		rating = &Rating{UserID: userID, Rating: INITIAL_RATING}
+	}
+	return rating, nil
+}
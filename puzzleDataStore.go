@@ -0,0 +1,16 @@
+package neutrinoapi
+
+type PuzzleDataStore interface {
+	// SavePuzzle gives the puzzle an ID, positions that are already puzzles should be ignored.
+	SavePuzzle(puzzle *Puzzle) error
+	Puzzle(puzzleID string) (*Puzzle, error)
+	// NextPuzzle should return the puzzle closest to rating that the player has not attempted, or nil if there is none.
+	NextPuzzle(userID string, rating int) (*Puzzle, error)
+
+	// PuzzleRating should return nil if the player has not attempted any puzzles, RatedGames counts the attempts.
+	PuzzleRating(userID string) (*Rating, error)
+	// Attempt should return nil if the player has not attempted the puzzle.
+	Attempt(userID string, puzzleID string) (*PuzzleAttempt, error)
+	// RecordAttempt stores the attempt along with the new ratings of the player and the puzzle.
+	RecordAttempt(attempt *PuzzleAttempt) error
+}
@@ -0,0 +1,96 @@
+package neutrinoapi_test
+
+import (
+	"errors"
+	g "github.com/Morras/go-neutrino/game"
+	api "github.com/Morras/neutrinoapi"
+	"github.com/Morras/neutrinoapi/spy"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"time"
+)
+
+var _ = Describe("PuzzleMiner", func() {
+
+	var dataStoreSpy *spy.GameDataStoreSpy
+	var puzzleDataStoreSpy *spy.PuzzleDataStoreSpy
+	var analyzerSpy *spy.AnalyzerSpy
+	var miner *api.PuzzleMiner
+	var finished *api.Game
+
+	BeforeEach(func() {
+		dataStoreSpy = &spy.GameDataStoreSpy{}
+		puzzleDataStoreSpy = &spy.PuzzleDataStoreSpy{}
+		analyzerSpy = &spy.AnalyzerSpy{AnalyzeReturn: &api.Analysis{ForcedWin: true, WinningCondition: api.BACK_LINE, Plies: 2}}
+		miner = api.NewPuzzleMiner(dataStoreSpy, puzzleDataStoreSpy, analyzerSpy)
+
+		position := g.NewStandardGame()
+		position.State = g.Player2NeutrinoMove
+		finished = &api.Game{GameID: "finished game", State: api.DONE, Visibility: api.PUBLIC,
+			History: []uint64{g.GameToUInt64(g.NewStandardGame()), g.GameToUInt64(position)}}
+	})
+
+	Context("MineGame", func() {
+		It("Should save positions with a forced win as puzzles", func() {
+			found, err := miner.MineGame(finished)
+			Expect(err).To(BeNil())
+			Expect(found).To(BeIdenticalTo(1))
+
+			puzzle := puzzleDataStoreSpy.SavePuzzlePuzzles[0]
+			Expect(puzzle.SerializedGame).To(BeIdenticalTo(finished.History[1]))
+			Expect(puzzle.Plies).To(BeIdenticalTo(2))
+			Expect(puzzle.WinningCondition).To(BeIdenticalTo(api.BACK_LINE))
+			Expect(puzzle.SourceGameID).To(BeIdenticalTo("finished game"))
+			Expect(puzzle.Rating).To(BeIdenticalTo(api.INITIAL_RATING + api.PUZZLE_RATING_PER_PLY))
+		})
+
+		It("Should not point puzzles at games that are not public", func() {
+			finished.Visibility = api.PLAYERS_ONLY
+			miner.MineGame(finished)
+			Expect(puzzleDataStoreSpy.SavePuzzlePuzzles[0].SourceGameID).To(BeEmpty())
+		})
+
+		It("Should search for wins within the puzzle limit", func() {
+			miner.MineGame(finished)
+			Expect(analyzerSpy.AnalyzePlies).To(BeIdenticalTo(api.PUZZLE_MAX_PLIES))
+		})
+
+		It("Should skip positions without a forced win", func() {
+			analyzerSpy.AnalyzeReturn = &api.Analysis{}
+			found, _ := miner.MineGame(finished)
+			Expect(found).To(BeIdenticalTo(0))
+			Expect(puzzleDataStoreSpy.SavePuzzlePuzzles).To(BeEmpty())
+		})
+
+		It("Should return the error if a puzzle cannot be saved", func() {
+			puzzleDataStoreSpy.SavePuzzleErr = errors.New("Error saving puzzle")
+			_, err := miner.MineGame(finished)
+			Expect(err).ToNot(BeNil())
+		})
+	})
+
+	Context("MineGames", func() {
+		It("Should mine the games finished since the given time", func() {
+			since := time.Now().Add(-time.Hour)
+			dataStoreSpy.FinishedGamesReturn = []*api.Game{finished, finished}
+			found, err := miner.MineGames(since)
+			Expect(err).To(BeNil())
+			Expect(dataStoreSpy.FinishedGamesSince).To(Equal(since))
+			Expect(found).To(BeIdenticalTo(2))
+		})
+
+		It("Should keep mining the other games if one fails", func() {
+			dataStoreSpy.FinishedGamesReturn = []*api.Game{finished}
+			analyzerSpy.AnalyzeErr = errors.New("No legal turn")
+			found, err := miner.MineGames(time.Now())
+			Expect(err).To(BeNil())
+			Expect(found).To(BeIdenticalTo(0))
+		})
+
+		It("Should return the error if the finished games cannot be looked up", func() {
+			dataStoreSpy.FinishedGamesErr = errors.New("Error getting games")
+			_, err := miner.MineGames(time.Now())
+			Expect(err).ToNot(BeNil())
+		})
+	})
+})
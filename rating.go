@@ -0,0 +1,107 @@
+package neutrinoapi
+
+import (
+	"math"
+	"time"
+)
+
+type Rating struct {
+	UserID      string
+	Rating      int
+	RatedGames  int
+	LastUpdated time.Time
+}
+
+type RatingChange struct {
+	UserID, GameID, OpponentID string
+	Before, After              int
+	Time                       time.Time
+}
+
+type Rater struct {
+	rds RatingDataStore
+	lds LeaderboardDataStore
+}
+
+func NewRater(rds RatingDataStore, lds LeaderboardDataStore) *Rater {
+	return &Rater{rds: rds, lds: lds}
+}
+
+// RateGame updates the ratings of both players of a finished game, and their places on the leaderboard.
+// Games that are not DONE and games against bots are ignored. Resigned games are won by DEFAULT and
+// rated like any other win. Games have no clock yet, so nobody can lose on time and there are no
+// timeouts to rate; games a player walks away from stay PLAYING until an admin finishes them.
+func (r *Rater) RateGame(game *Game) error {
+	if game.State != DONE || game.PlayerOneID == "" || game.PlayerTwoID == "" {
+		return nil
+	}
+	// Bots do not have a rating, and beating them should not change a players rating either
+	if isBotGame(game) {
+		return nil
+	}
+
+	playerOne, err := ratingOrDefault(r.rds, game.PlayerOneID)
+	if err != nil {
+		return err
+	}
+	playerTwo, err := ratingOrDefault(r.rds, game.PlayerTwoID)
+	if err != nil {
+		return err
+	}
+
+	// A draw is worth half a win to both players
+	playerOneScore := 0.5
+	switch game.WinnerID {
+	case game.PlayerOneID:
+		playerOneScore = 1
+	case game.PlayerTwoID:
+		playerOneScore = 0
+	}
+
+	now := time.Now()
+	changes := []*RatingChange{
+		{
+			UserID:     game.PlayerOneID,
+			GameID:     game.GameID,
+			OpponentID: game.PlayerTwoID,
+			Before:     playerOne.Rating,
+			After:      newEloRating(playerOne.Rating, playerTwo.Rating, playerOneScore),
+			Time:       now,
+		},
+		{
+			UserID:     game.PlayerTwoID,
+			GameID:     game.GameID,
+			OpponentID: game.PlayerOneID,
+			Before:     playerTwo.Rating,
+			After:      newEloRating(playerTwo.Rating, playerOne.Rating, 1-playerOneScore),
+			Time:       now,
+		},
+	}
+
+	if err = r.rds.UpdateRatings(changes); err != nil {
+		return err
+	}
+
+	entries := []*LeaderboardEntry{
+		{UserID: game.PlayerOneID, Rating: changes[0].After, RatedGames: playerOne.RatedGames + 1},
+		{UserID: game.PlayerTwoID, Rating: changes[1].After, RatedGames: playerTwo.RatedGames + 1},
+	}
+	return r.lds.UpdateLeaderboard(entries)
+}
+
+// Players who have never finished a game have no rating stored yet.
+func ratingOrDefault(rds RatingDataStore, userID string) (*Rating, error) {
+	rating, err := rds.Rating(userID)
+	if err != nil {
+		return nil, err
+	}
+	if rating == nil {
+		rating = &Rating{UserID: userID, Rating: INITIAL_RATING}
+	}
+	return rating, nil
+}
+
+func newEloRating(rating int, opponentRating int, score float64) int {
+	expectedScore := 1 / (1 + math.Pow(10, float64(opponentRating-rating)/400))
+	return rating + int(math.Round(ELO_K_FACTOR*(score-expectedScore)))
+}
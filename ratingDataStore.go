@@ -0,0 +1,10 @@
+package neutrinoapi
+
+type RatingDataStore interface {
+	// Rating should return nil if the player has no rating yet.
+	Rating(userID string) (*Rating, error)
+	// UpdateRatings stores the new ratings, counts the game towards the players RatedGames and
+	// appends the changes to the players rating history.
+	UpdateRatings(changes []*RatingChange) error
+	RatingHistory(userID string) ([]*RatingChange, error)
+}
@@ -0,0 +1,112 @@
+package neutrinoapi_test
+
+import (
+	"errors"
+	api "github.com/Morras/neutrinoapi"
+	"github.com/Morras/neutrinoapi/spy"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Rater", func() {
+
+	var ratingDataStoreSpy *spy.RatingDataStoreSpy
+	var leaderboardDataStoreSpy *spy.LeaderboardDataStoreSpy
+	var rater *api.Rater
+	var game *api.Game
+
+	BeforeEach(func() {
+		ratingDataStoreSpy = &spy.RatingDataStoreSpy{}
+		leaderboardDataStoreSpy = &spy.LeaderboardDataStoreSpy{}
+		rater = api.NewRater(ratingDataStoreSpy, leaderboardDataStoreSpy)
+		game = &api.Game{GameID: "game id", PlayerOneID: "one", PlayerTwoID: "two", State: api.DONE}
+	})
+
+	Context("RateGame", func() {
+
+		It("Should not rate games that are not done", func() {
+			game.State = api.PLAYING
+			Expect(rater.RateGame(game)).To(Succeed())
+			Expect(ratingDataStoreSpy.RatingUserIDs).To(BeEmpty())
+			Expect(ratingDataStoreSpy.UpdateRatingsChanges).To(BeNil())
+		})
+
+		It("Should not rate games against bots", func() {
+			game.PlayerTwoID = api.BotUserID(api.HARD)
+			game.WinnerID = "one"
+			Expect(rater.RateGame(game)).To(Succeed())
+			Expect(ratingDataStoreSpy.UpdateRatingsChanges).To(BeNil())
+			Expect(leaderboardDataStoreSpy.UpdateLeaderboardEntries).To(BeNil())
+		})
+
+		It("Should not rate games where a bot took the place of a player", func() {
+			game.BotPlayed = true
+			Expect(rater.RateGame(game)).To(Succeed())
+			Expect(ratingDataStoreSpy.UpdateRatingsChanges).To(BeNil())
+		})
+
+		It("Should give players without a rating the initial rating", func() {
+			game.WinnerID = "one"
+			rater.RateGame(game)
+			changes := ratingDataStoreSpy.UpdateRatingsChanges
+			Expect(changes[0].Before).To(BeIdenticalTo(api.INITIAL_RATING))
+			Expect(changes[1].Before).To(BeIdenticalTo(api.INITIAL_RATING))
+		})
+
+		It("Should move equally rated players by half the K factor", func() {
+			game.WinnerID = "two"
+			rater.RateGame(game)
+			changes := ratingDataStoreSpy.UpdateRatingsChanges
+			Expect(changes[0].After).To(BeIdenticalTo(api.INITIAL_RATING - api.ELO_K_FACTOR/2))
+			Expect(changes[1].After).To(BeIdenticalTo(api.INITIAL_RATING + api.ELO_K_FACTOR/2))
+			Expect(changes[0].GameID).To(BeIdenticalTo("game id"))
+			Expect(changes[0].OpponentID).To(BeIdenticalTo("two"))
+		})
+
+		It("Should reward the weaker player for a draw", func() {
+			ratingDataStoreSpy.RatingReturn = map[string]*api.Rating{
+				"one": {UserID: "one", Rating: 1400},
+				"two": {UserID: "two", Rating: 1600},
+			}
+			rater.RateGame(game)
+			changes := ratingDataStoreSpy.UpdateRatingsChanges
+			Expect(changes[0].After).To(BeNumerically(">", 1400))
+			Expect(changes[1].After).To(BeNumerically("<", 1600))
+		})
+
+		It("Should barely reward a much stronger player for winning", func() {
+			ratingDataStoreSpy.RatingReturn = map[string]*api.Rating{
+				"one": {UserID: "one", Rating: 2400},
+				"two": {UserID: "two", Rating: 1200},
+			}
+			game.WinnerID = "one"
+			rater.RateGame(game)
+			Expect(ratingDataStoreSpy.UpdateRatingsChanges[0].After).To(BeNumerically("<=", 2401))
+		})
+
+		It("Should move both players on the leaderboard", func() {
+			ratingDataStoreSpy.RatingReturn = map[string]*api.Rating{"one": {UserID: "one", Rating: 1500, RatedGames: 3}}
+			game.WinnerID = "one"
+			rater.RateGame(game)
+			entries := leaderboardDataStoreSpy.UpdateLeaderboardEntries
+			Expect(len(entries)).To(BeIdenticalTo(2))
+			Expect(entries[0].UserID).To(BeIdenticalTo("one"))
+			Expect(entries[0].Rating).To(BeIdenticalTo(ratingDataStoreSpy.UpdateRatingsChanges[0].After))
+			Expect(entries[0].RatedGames).To(BeIdenticalTo(4))
+			Expect(entries[1].UserID).To(BeIdenticalTo("two"))
+			Expect(entries[1].RatedGames).To(BeIdenticalTo(1))
+		})
+
+		It("Should not touch the leaderboard if the ratings could not be saved", func() {
+			ratingDataStoreSpy.UpdateRatingsErr = errors.New("Error updating ratings")
+			Expect(rater.RateGame(game)).ToNot(Succeed())
+			Expect(leaderboardDataStoreSpy.UpdateLeaderboardEntries).To(BeNil())
+		})
+
+		It("Should return errors from the data store", func() {
+			ratingDataStoreSpy.RatingErr = errors.New("Error getting rating")
+			Expect(rater.RateGame(game)).ToNot(Succeed())
+			Expect(ratingDataStoreSpy.UpdateRatingsChanges).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,24 @@
+package neutrinoapi
+
+import "net/http"
+
+type RegisterDeviceEndpoint struct {
+	nds NotificationDataStore
+}
+
+func NewRegisterDeviceEndpoint(nds NotificationDataStore) *RegisterDeviceEndpoint {
+	return &RegisterDeviceEndpoint{nds: nds}
+}
+
+// PerformAction lets a device receive the players notifications. Players get their notifications on
+// every device they have registered.
+func (rde *RegisterDeviceEndpoint) PerformAction(userID string, token string, platform DevicePlatform) int {
+	if token == "" || len(token) > MAX_DEVICE_TOKEN_LENGTH {
+		return http.StatusBadRequest
+	}
+
+	if err := rde.nds.AddDeviceToken(&DeviceToken{UserID: userID, Token: token, Platform: platform}); err != nil {
+		return http.StatusInternalServerError
+	}
+	return http.StatusOK
+}
@@ -0,0 +1,46 @@
+package neutrinoapi_test
+
+import (
+	"errors"
+	api "github.com/Morras/neutrinoapi"
+	"github.com/Morras/neutrinoapi/spy"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+	"strings"
+)
+
+var _ = Describe("registerDeviceEndpoint", func() {
+
+	testUserID := "TestUserId"
+
+	var dataStoreSpy *spy.NotificationDataStoreSpy
+	var endpoint *api.RegisterDeviceEndpoint
+
+	BeforeEach(func() {
+		dataStoreSpy = &spy.NotificationDataStoreSpy{}
+		endpoint = api.NewRegisterDeviceEndpoint(dataStoreSpy)
+	})
+
+	Context("performAction method", func() {
+		It("Should register the device for the user", func() {
+			code := endpoint.PerformAction(testUserID, "device token", api.APNS)
+			Expect(code).To(BeIdenticalTo(http.StatusOK))
+			Expect(dataStoreSpy.AddDeviceTokenDevice).To(Equal(&api.DeviceToken{UserID: testUserID, Token: "device token", Platform: api.APNS}))
+		})
+
+		It("Should reject empty and overly long tokens", func() {
+			for _, token := range []string{"", strings.Repeat("a", api.MAX_DEVICE_TOKEN_LENGTH+1)} {
+				code := endpoint.PerformAction(testUserID, token, api.FCM)
+				Expect(code).To(BeIdenticalTo(http.StatusBadRequest))
+			}
+			Expect(dataStoreSpy.AddDeviceTokenDevice).To(BeNil())
+		})
+
+		It("Should return an internal server error if the device could not be saved", func() {
+			dataStoreSpy.AddDeviceTokenErr = errors.New("error adding device")
+			code := endpoint.PerformAction(testUserID, "device token", api.FCM)
+			Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
+		})
+	})
+})
@@ -0,0 +1,39 @@
+package neutrinoapi
+
+import (
+	"fmt"
+	"github.com/Morras/neutrinoapi/auth"
+	"net/http"
+)
+
+// RequestParser reads the player from plain HTTP requests, for when the API runs as a standalone server.
+type RequestParser interface {
+	GetUserID(r *http.Request) (string, error)
+}
+
+type TokenRequestParser struct {
+	authenticator auth.Authenticator
+}
+
+func NewRequestParser(authenticator auth.Authenticator) RequestParser {
+	return &TokenRequestParser{authenticator: authenticator}
+}
+
+func (parser *TokenRequestParser) GetUserID(r *http.Request) (string, error) {
+	jwt := r.Header.Get(JWT_HEADER_KEY)
+
+	if jwt == "" {
+		return "", ErrMissingJWT
+	}
+
+	claims, err := parser.authenticator.Authenticate(jwt)
+	if err != nil {
+		fmt.Printf("Error authenticating request: %v\n", err)
+		return "", ErrInvalidJWT
+	}
+	// Authenticators check this already, but an empty ID would let the request act as nobody in particular
+	if claims.Subject == "" {
+		return "", ErrInvalidJWT
+	}
+	return claims.Subject, nil
+}
@@ -0,0 +1,61 @@
+package neutrinoapi
+
+import (
+	"net/http"
+	"time"
+)
+
+// RequestRematchEndpoint sends the opponent of a finished game a challenge, which is accepted
+// or declined like any other challenge. Bots cannot answer challenges, so there are no rematches
+// of bot games, the player starts a new bot game instead.
+type RequestRematchEndpoint struct {
+	ds  GameDataStore
+	cds ChallengeDataStore
+	ttl time.Duration
+}
+
+func NewRequestRematchEndpoint(ds GameDataStore, cds ChallengeDataStore, ttl time.Duration) *RequestRematchEndpoint {
+	return &RequestRematchEndpoint{ds: ds, cds: cds, ttl: ttl}
+}
+
+func (rre *RequestRematchEndpoint) PerformAction(userID string, gameID string) (string, int) {
+	game, err := rre.ds.Game(gameID)
+	if err != nil {
+		return "", http.StatusInternalServerError
+	}
+	if game == nil {
+		return "", http.StatusNotFound
+	}
+
+	var opponentID string
+	switch userID {
+	case game.PlayerOneID:
+		opponentID = game.PlayerTwoID
+	case game.PlayerTwoID:
+		opponentID = game.PlayerOneID
+	default:
+		return "", http.StatusForbidden
+	}
+
+	if game.State != DONE || isBotGame(game) {
+		return "", http.StatusBadRequest
+	}
+
+	if eligible, statusCode := isEligibleForNewGame(rre.ds, userID); !eligible {
+		return "", statusCode
+	}
+
+	challenge := &Challenge{
+		ChallengerID:    userID,
+		ChallengedID:    opponentID,
+		Expires:         time.Now().Add(rre.ttl),
+		RematchOfGameID: game.GameID,
+	}
+
+	challengeID, err := rre.cds.CreateChallenge(challenge)
+	if err != nil {
+		return "", http.StatusInternalServerError
+	}
+
+	return challengeID, http.StatusOK
+}
@@ -0,0 +1,93 @@
+package neutrinoapi_test
+
+import (
+	"errors"
+	api "github.com/Morras/neutrinoapi"
+	"github.com/Morras/neutrinoapi/spy"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+	"time"
+)
+
+var _ = Describe("requestRematchEndpoint", func() {
+
+	testUserID := "TestUserId"
+	opponentID := "OpponentUserId"
+	const gameID = "finished game id"
+
+	var dataStoreSpy *spy.GameDataStoreSpy
+	var challengeDataStoreSpy *spy.ChallengeDataStoreSpy
+	var endpoint *api.RequestRematchEndpoint
+
+	BeforeEach(func() {
+		dataStoreSpy = &spy.GameDataStoreSpy{}
+		challengeDataStoreSpy = &spy.ChallengeDataStoreSpy{}
+		endpoint = api.NewRequestRematchEndpoint(dataStoreSpy, challengeDataStoreSpy, time.Hour)
+	})
+
+	Context("performAction method", func() {
+
+		It("Should return not found if the game does not exist", func() {
+			_, code := endpoint.PerformAction(testUserID, gameID)
+			Expect(dataStoreSpy.GameGameID).To(BeIdenticalTo(gameID))
+			Expect(code).To(BeIdenticalTo(http.StatusNotFound))
+		})
+
+		It("Should return an internal server error if the game cannot be looked up", func() {
+			dataStoreSpy.GameErr = errors.New("Error getting game")
+			_, code := endpoint.PerformAction(testUserID, gameID)
+			Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
+		})
+
+		It("Should not let anyone but the players ask for a rematch", func() {
+			dataStoreSpy.GameReturn = &api.Game{GameID: gameID, PlayerOneID: "someone", PlayerTwoID: opponentID, State: api.DONE}
+			_, code := endpoint.PerformAction(testUserID, gameID)
+			Expect(code).To(BeIdenticalTo(http.StatusForbidden))
+		})
+
+		It("Should not allow a rematch of a game that is still going", func() {
+			dataStoreSpy.GameReturn = &api.Game{GameID: gameID, PlayerOneID: testUserID, PlayerTwoID: opponentID, State: api.PLAYING}
+			_, code := endpoint.PerformAction(testUserID, gameID)
+			Expect(code).To(BeIdenticalTo(http.StatusBadRequest))
+			Expect(challengeDataStoreSpy.CreateChallengeChallenge).To(BeNil())
+		})
+
+		It("Should not allow a rematch of a bot game", func() {
+			dataStoreSpy.GameReturn = &api.Game{GameID: gameID, PlayerOneID: testUserID, PlayerTwoID: api.BotUserID(api.HARD), State: api.DONE}
+			_, code := endpoint.PerformAction(testUserID, gameID)
+			Expect(code).To(BeIdenticalTo(http.StatusBadRequest))
+			Expect(challengeDataStoreSpy.CreateChallengeChallenge).To(BeNil())
+		})
+
+		Context("Given the game is done", func() {
+			BeforeEach(func() {
+				dataStoreSpy.GameReturn = &api.Game{GameID: gameID, PlayerOneID: opponentID, PlayerTwoID: testUserID, State: api.DONE}
+				challengeDataStoreSpy.CreateChallengeReturn = "challenge id"
+			})
+
+			It("Should challenge the opponent to a rematch", func() {
+				challengeID, code := endpoint.PerformAction(testUserID, gameID)
+				Expect(code).To(BeIdenticalTo(http.StatusOK))
+				Expect(challengeID).To(BeIdenticalTo("challenge id"))
+				challenge := challengeDataStoreSpy.CreateChallengeChallenge
+				Expect(challenge.ChallengerID).To(BeIdenticalTo(testUserID))
+				Expect(challenge.ChallengedID).To(BeIdenticalTo(opponentID))
+				Expect(challenge.RematchOfGameID).To(BeIdenticalTo(gameID))
+			})
+
+			It("Should respect the maximum number of active games", func() {
+				dataStoreSpy.NumberOfActiveGamesReturn = api.MAX_ACTIVE_GAMES
+				_, code := endpoint.PerformAction(testUserID, gameID)
+				Expect(code).To(BeIdenticalTo(http.StatusBadRequest))
+				Expect(challengeDataStoreSpy.CreateChallengeChallenge).To(BeNil())
+			})
+
+			It("Should return an internal server error if the challenge cannot be stored", func() {
+				challengeDataStoreSpy.CreateChallengeErr = errors.New("Error creating challenge")
+				_, code := endpoint.PerformAction(testUserID, gameID)
+				Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
+			})
+		})
+	})
+})
@@ -0,0 +1,59 @@
+package neutrinoapi
+
+import (
+	"fmt"
+	"net/http"
+)
+
+type ResignEndpoint struct {
+	ds        GameDataStore
+	rater     *Rater
+	publisher GameEventPublisher
+	audit     AuditSink
+}
+
+func NewResignEndpoint(ds GameDataStore, rater *Rater, publisher GameEventPublisher, audit AuditSink) *ResignEndpoint {
+	return &ResignEndpoint{ds: ds, rater: rater, publisher: publisher, audit: audit}
+}
+
+func (re *ResignEndpoint) PerformAction(requestIDs RequestIDs, userID string, gameID string) int {
+	dsGame, err := re.ds.Game(gameID)
+	if err != nil {
+		return http.StatusInternalServerError
+	}
+	if dsGame == nil {
+		return http.StatusNotFound
+	}
+
+	var opponentID string
+	switch userID {
+	case dsGame.PlayerOneID:
+		opponentID = dsGame.PlayerTwoID
+	case dsGame.PlayerTwoID:
+		opponentID = dsGame.PlayerOneID
+	default:
+		return http.StatusForbidden
+	}
+
+	if dsGame.State != PLAYING {
+		return http.StatusBadRequest
+	}
+
+	before := auditedGame(dsGame)
+	finishGame(dsGame, opponentID, DEFAULT)
+	dsGame.Version++
+
+	event := newGameEvent(GAME_FINISHED, userID, dsGame)
+	if err = re.ds.UpdateGame(dsGame, event); err != nil {
+		return http.StatusInternalServerError
+	}
+
+	if err = re.rater.RateGame(dsGame); err != nil {
+		fmt.Printf("Error rating game %v: %v\n", dsGame.GameID, err)
+	}
+	publishGameEvent(re.publisher, event)
+	recordAudit(re.audit, requestIDs, &AuditEntry{ActorID: userID, Action: AUDIT_RESIGN, GameID: gameID,
+		Before: before, After: auditedGame(dsGame)})
+
+	return http.StatusOK
+}
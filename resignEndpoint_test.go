@@ -0,0 +1,104 @@
+package neutrinoapi_test
+
+import (
+	"errors"
+	api "github.com/Morras/neutrinoapi"
+	"github.com/Morras/neutrinoapi/spy"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+)
+
+var _ = Describe("resignEndpoint", func() {
+
+	testUserID := "TestUserId"
+	testRequestID := api.RequestIDs{ID: "TestRequestId"}
+	opponentID := "OpponentUserId"
+	const gameID = "game id"
+
+	var dataStoreSpy *spy.GameDataStoreSpy
+	var ratingDataStoreSpy *spy.RatingDataStoreSpy
+	var publisherSpy *spy.GameEventPublisherSpy
+	var auditSpy *spy.AuditSinkSpy
+	var endpoint *api.ResignEndpoint
+
+	BeforeEach(func() {
+		dataStoreSpy = &spy.GameDataStoreSpy{}
+		ratingDataStoreSpy = &spy.RatingDataStoreSpy{}
+		publisherSpy = &spy.GameEventPublisherSpy{}
+		auditSpy = &spy.AuditSinkSpy{}
+		endpoint = api.NewResignEndpoint(dataStoreSpy, api.NewRater(ratingDataStoreSpy, &spy.LeaderboardDataStoreSpy{}), publisherSpy, auditSpy)
+	})
+
+	Context("performAction method", func() {
+
+		It("Should return not found if the game does not exist", func() {
+			code := endpoint.PerformAction(testRequestID, testUserID, gameID)
+			Expect(code).To(BeIdenticalTo(http.StatusNotFound))
+		})
+
+		It("Should not let anyone but the players resign", func() {
+			dataStoreSpy.GameReturn = &api.Game{GameID: gameID, PlayerOneID: "someone", PlayerTwoID: opponentID, State: api.PLAYING}
+			code := endpoint.PerformAction(testRequestID, testUserID, gameID)
+			Expect(code).To(BeIdenticalTo(http.StatusForbidden))
+			Expect(dataStoreSpy.UpdateGameGame).To(BeNil())
+		})
+
+		It("Should not allow resigning a game that is not being played", func() {
+			dataStoreSpy.GameReturn = &api.Game{GameID: gameID, PlayerOneID: testUserID, PlayerTwoID: opponentID, State: api.DONE}
+			code := endpoint.PerformAction(testRequestID, testUserID, gameID)
+			Expect(code).To(BeIdenticalTo(http.StatusBadRequest))
+			Expect(dataStoreSpy.UpdateGameGame).To(BeNil())
+		})
+
+		Context("Given the game is being played", func() {
+			BeforeEach(func() {
+				dataStoreSpy.GameReturn = &api.Game{GameID: gameID, PlayerOneID: testUserID, PlayerTwoID: opponentID, State: api.PLAYING}
+			})
+
+			It("Should finish the game with the opponent winning by default", func() {
+				code := endpoint.PerformAction(testRequestID, testUserID, gameID)
+				Expect(code).To(BeIdenticalTo(http.StatusOK))
+				saved := dataStoreSpy.UpdateGameGame
+				Expect(saved.State).To(BeIdenticalTo(api.DONE))
+				Expect(saved.WinnerID).To(BeIdenticalTo(opponentID))
+				Expect(saved.WinningCondition).To(BeIdenticalTo(api.DEFAULT))
+				Expect(saved.Version).To(BeIdenticalTo(1))
+			})
+
+			It("Should rate the game", func() {
+				endpoint.PerformAction(testRequestID, testUserID, gameID)
+				Expect(len(ratingDataStoreSpy.UpdateRatingsChanges)).To(BeIdenticalTo(2))
+			})
+
+			It("Should tell the players the game is finished", func() {
+				endpoint.PerformAction(testRequestID, testUserID, gameID)
+				Expect(len(publisherSpy.PublishEvents)).To(BeIdenticalTo(1))
+				Expect(publisherSpy.PublishEvents[0].Type).To(BeIdenticalTo(api.GAME_FINISHED))
+				Expect(publisherSpy.PublishEvents[0].UserID).To(BeIdenticalTo(testUserID))
+			})
+
+			It("Should record the resignation in the audit log", func() {
+				endpoint.PerformAction(testRequestID, testUserID, gameID)
+				Expect(len(auditSpy.RecordEntries)).To(BeIdenticalTo(1))
+				entry := auditSpy.RecordEntries[0]
+				Expect(entry.Action).To(BeIdenticalTo(api.AUDIT_RESIGN))
+				Expect(entry.ActorID).To(BeIdenticalTo(testUserID))
+				Expect(entry.RequestID).To(BeIdenticalTo(testRequestID.ID))
+				Expect(entry.GameID).To(BeIdenticalTo(gameID))
+				Expect(entry.Before.State).To(BeIdenticalTo(api.PLAYING))
+				Expect(entry.Before.WinnerID).To(BeEmpty())
+				Expect(entry.After.State).To(BeIdenticalTo(api.DONE))
+				Expect(entry.After.WinnerID).ToNot(BeEmpty())
+			})
+
+			It("Should return an internal server error if the game cannot be saved", func() {
+				dataStoreSpy.UpdateGameErr = errors.New("Error updating game")
+				code := endpoint.PerformAction(testRequestID, testUserID, gameID)
+				Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
+				Expect(ratingDataStoreSpy.UpdateRatingsChanges).To(BeNil())
+				Expect(publisherSpy.PublishEvents).To(BeEmpty())
+			})
+		})
+	})
+})
@@ -0,0 +1,94 @@
+package neutrinoapi
+
+import (
+	"errors"
+	"net/http"
+)
+
+type RespondToChallengeEndpoint struct {
+	ds        GameDataStore
+	cds       ChallengeDataStore
+	publisher GameEventPublisher
+	audit     AuditSink
+}
+
+func NewRespondToChallengeEndpoint(ds GameDataStore, cds ChallengeDataStore, publisher GameEventPublisher, audit AuditSink) *RespondToChallengeEndpoint {
+	return &RespondToChallengeEndpoint{ds: ds, cds: cds, publisher: publisher, audit: audit}
+}
+
+// PerformAction returns the ID of the new game if the challenge was accepted.
+func (rce *RespondToChallengeEndpoint) PerformAction(requestIDs RequestIDs, userID string, challengeID string, accept bool) (string, int) {
+	challenge, err := rce.cds.Challenge(challengeID)
+	if err != nil {
+		return "", http.StatusInternalServerError
+	}
+	if challenge == nil {
+		return "", http.StatusNotFound
+	}
+	if challenge.ChallengedID != userID {
+		return "", http.StatusForbidden
+	}
+
+	if challenge.expired() {
+		if err = rce.cds.DeleteChallenge(challengeID); err != nil {
+			return "", http.StatusInternalServerError
+		}
+		return "", http.StatusGone
+	}
+
+	if !accept {
+		if err = rce.cds.DeleteChallenge(challengeID); err != nil {
+			return "", http.StatusInternalServerError
+		}
+		return "", http.StatusOK
+	}
+
+	if eligible, statusCode := isEligibleForNewGame(rce.ds, userID); !eligible {
+		return "", statusCode
+	}
+	// The challenger might have started other games since sending the challenge
+	if eligible, statusCode := isEligibleForNewGame(rce.ds, challenge.ChallengerID); !eligible {
+		if statusCode == http.StatusBadRequest {
+			statusCode = http.StatusConflict
+		}
+		return "", statusCode
+	}
+
+	playerOneID, playerTwoID, err := rce.playersForNewGame(challenge)
+	if err != nil {
+		return "", http.StatusInternalServerError
+	}
+
+	// Deleting the challenge first means a retried accept cannot create the same game twice
+	if err = rce.cds.DeleteChallenge(challengeID); err != nil {
+		return "", http.StatusInternalServerError
+	}
+
+	event := newGameEvent(GAME_CREATED, userID, &Game{PlayerOneID: playerOneID, PlayerTwoID: playerTwoID, State: PLAYING})
+	gameID, err := rce.ds.CreateGame(playerOneID, playerTwoID, event)
+	if err != nil {
+		return "", http.StatusInternalServerError
+	}
+	event.Game.GameID = gameID
+	publishGameEvent(rce.publisher, event)
+	recordAudit(rce.audit, requestIDs, &AuditEntry{ActorID: userID, Action: AUDIT_ACCEPT_CHALLENGE, GameID: gameID,
+		Details: "challenge " + challengeID})
+
+	return gameID, http.StatusOK
+}
+
+// The challenger gets to be player one, except for rematches where the players swap sides.
+func (rce *RespondToChallengeEndpoint) playersForNewGame(challenge *Challenge) (string, string, error) {
+	if challenge.RematchOfGameID == "" {
+		return challenge.ChallengerID, challenge.ChallengedID, nil
+	}
+
+	previousGame, err := rce.ds.Game(challenge.RematchOfGameID)
+	if err != nil {
+		return "", "", err
+	}
+	if previousGame == nil {
+		return "", "", errors.New("Rematch of a game that no longer exists")
+	}
+	return previousGame.PlayerTwoID, previousGame.PlayerOneID, nil
+}
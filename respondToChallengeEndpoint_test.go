@@ -0,0 +1,153 @@
+package neutrinoapi_test
+
+import (
+	"errors"
+	api "github.com/Morras/neutrinoapi"
+	"github.com/Morras/neutrinoapi/spy"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+	"time"
+)
+
+var _ = Describe("respondToChallengeEndpoint", func() {
+
+	testUserID := "TestUserId"
+	testRequestID := api.RequestIDs{ID: "TestRequestId"}
+	challengerID := "ChallengerUserId"
+	const challengeID = "challenge id"
+
+	var dataStoreSpy *spy.GameDataStoreSpy
+	var challengeDataStoreSpy *spy.ChallengeDataStoreSpy
+	var publisherSpy *spy.GameEventPublisherSpy
+	var auditSpy *spy.AuditSinkSpy
+	var endpoint *api.RespondToChallengeEndpoint
+
+	BeforeEach(func() {
+		dataStoreSpy = &spy.GameDataStoreSpy{}
+		challengeDataStoreSpy = &spy.ChallengeDataStoreSpy{}
+		publisherSpy = &spy.GameEventPublisherSpy{}
+		auditSpy = &spy.AuditSinkSpy{}
+		endpoint = api.NewRespondToChallengeEndpoint(dataStoreSpy, challengeDataStoreSpy, publisherSpy, auditSpy)
+	})
+
+	Context("performAction method", func() {
+
+		It("Should return not found if the challenge does not exist", func() {
+			_, code := endpoint.PerformAction(testRequestID, testUserID, challengeID, true)
+			Expect(challengeDataStoreSpy.ChallengeChallengeID).To(BeIdenticalTo(challengeID))
+			Expect(code).To(BeIdenticalTo(http.StatusNotFound))
+		})
+
+		It("Should return an internal server error if the challenge cannot be looked up", func() {
+			challengeDataStoreSpy.ChallengeErr = errors.New("Error getting challenge")
+			_, code := endpoint.PerformAction(testRequestID, testUserID, challengeID, true)
+			Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
+		})
+
+		It("Should not let anyone but the challenged user respond", func() {
+			challengeDataStoreSpy.ChallengeReturn = &api.Challenge{ChallengeID: challengeID, ChallengerID: challengerID, ChallengedID: "someone else", Expires: time.Now().Add(time.Hour)}
+			_, code := endpoint.PerformAction(testRequestID, testUserID, challengeID, true)
+			Expect(code).To(BeIdenticalTo(http.StatusForbidden))
+			Expect(challengeDataStoreSpy.DeleteChallengeChallengeID).To(BeEmpty())
+			Expect(dataStoreSpy.CreateGamePlayerOneID).To(BeEmpty())
+		})
+
+		Context("Given the challenge has expired", func() {
+			BeforeEach(func() {
+				challengeDataStoreSpy.ChallengeReturn = &api.Challenge{ChallengeID: challengeID, ChallengerID: challengerID, ChallengedID: testUserID, Expires: time.Now().Add(-time.Hour)}
+			})
+
+			It("Should delete the challenge and return gone without creating a game", func() {
+				_, code := endpoint.PerformAction(testRequestID, testUserID, challengeID, true)
+				Expect(code).To(BeIdenticalTo(http.StatusGone))
+				Expect(challengeDataStoreSpy.DeleteChallengeChallengeID).To(BeIdenticalTo(challengeID))
+				Expect(dataStoreSpy.CreateGamePlayerOneID).To(BeEmpty())
+			})
+		})
+
+		Context("Given the challenge is pending", func() {
+			BeforeEach(func() {
+				challengeDataStoreSpy.ChallengeReturn = &api.Challenge{ChallengeID: challengeID, ChallengerID: challengerID, ChallengedID: testUserID, Expires: time.Now().Add(time.Hour)}
+				dataStoreSpy.CreateGameReturn = "new game id"
+			})
+
+			Context("and the user declines it", func() {
+				It("Should delete the challenge without creating a game", func() {
+					gameID, code := endpoint.PerformAction(testRequestID, testUserID, challengeID, false)
+					Expect(code).To(BeIdenticalTo(http.StatusOK))
+					Expect(gameID).To(BeEmpty())
+					Expect(challengeDataStoreSpy.DeleteChallengeChallengeID).To(BeIdenticalTo(challengeID))
+					Expect(dataStoreSpy.CreateGamePlayerOneID).To(BeEmpty())
+				})
+			})
+
+			Context("and the user accepts it", func() {
+				It("Should create a game between the challenger and the user", func() {
+					gameID, code := endpoint.PerformAction(testRequestID, testUserID, challengeID, true)
+					Expect(code).To(BeIdenticalTo(http.StatusOK))
+					Expect(gameID).To(BeIdenticalTo("new game id"))
+					Expect(dataStoreSpy.CreateGamePlayerOneID).To(BeIdenticalTo(challengerID))
+					Expect(dataStoreSpy.CreateGamePlayerTwoID).To(BeIdenticalTo(testUserID))
+					Expect(challengeDataStoreSpy.DeleteChallengeChallengeID).To(BeIdenticalTo(challengeID))
+				})
+
+				It("Should publish that a game was created", func() {
+					endpoint.PerformAction(testRequestID, testUserID, challengeID, true)
+					Expect(len(publisherSpy.PublishEvents)).To(BeIdenticalTo(1))
+					event := publisherSpy.PublishEvents[0]
+					Expect(event.Type).To(BeIdenticalTo(api.GAME_CREATED))
+					Expect(event.Game.GameID).To(BeIdenticalTo("new game id"))
+					Expect(event.Game.State).To(BeIdenticalTo(api.PLAYING))
+				})
+
+				It("Should record the new game in the audit log", func() {
+					endpoint.PerformAction(testRequestID, testUserID, challengeID, true)
+					Expect(len(auditSpy.RecordEntries)).To(BeIdenticalTo(1))
+					entry := auditSpy.RecordEntries[0]
+					Expect(entry.Action).To(BeIdenticalTo(api.AUDIT_ACCEPT_CHALLENGE))
+					Expect(entry.GameID).To(BeIdenticalTo("new game id"))
+					Expect(entry.RequestID).To(BeIdenticalTo(testRequestID.ID))
+				})
+
+				It("Should not create a game if the players already have the maximum number of active games", func() {
+					dataStoreSpy.NumberOfActiveGamesReturn = api.MAX_ACTIVE_GAMES
+					_, code := endpoint.PerformAction(testRequestID, testUserID, challengeID, true)
+					Expect(code).To(BeIdenticalTo(http.StatusBadRequest))
+					Expect(dataStoreSpy.CreateGamePlayerOneID).To(BeEmpty())
+					Expect(challengeDataStoreSpy.DeleteChallengeChallengeID).To(BeEmpty())
+				})
+
+				It("Should return an internal server error if the challenge cannot be deleted", func() {
+					challengeDataStoreSpy.DeleteChallengeErr = errors.New("Error deleting challenge")
+					_, code := endpoint.PerformAction(testRequestID, testUserID, challengeID, true)
+					Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
+					Expect(dataStoreSpy.CreateGamePlayerOneID).To(BeEmpty())
+				})
+
+				It("Should swap the players from the previous game if it is a rematch", func() {
+					challengeDataStoreSpy.ChallengeReturn.RematchOfGameID = "previous game id"
+					dataStoreSpy.GameReturn = &api.Game{GameID: "previous game id", PlayerOneID: testUserID, PlayerTwoID: challengerID, State: api.DONE}
+					_, code := endpoint.PerformAction(testRequestID, testUserID, challengeID, true)
+					Expect(code).To(BeIdenticalTo(http.StatusOK))
+					Expect(dataStoreSpy.GameGameID).To(BeIdenticalTo("previous game id"))
+					Expect(dataStoreSpy.CreateGamePlayerOneID).To(BeIdenticalTo(challengerID))
+					Expect(dataStoreSpy.CreateGamePlayerTwoID).To(BeIdenticalTo(testUserID))
+				})
+
+				It("Should return an internal server error if the game being rematched cannot be found", func() {
+					challengeDataStoreSpy.ChallengeReturn.RematchOfGameID = "previous game id"
+					_, code := endpoint.PerformAction(testRequestID, testUserID, challengeID, true)
+					Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
+					Expect(dataStoreSpy.CreateGamePlayerOneID).To(BeEmpty())
+				})
+
+				It("Should return an internal server error if the game cannot be created", func() {
+					dataStoreSpy.CreateGameErr = errors.New("Error creating game")
+					_, code := endpoint.PerformAction(testRequestID, testUserID, challengeID, true)
+					Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
+				})
+			})
+		})
+	})
+})
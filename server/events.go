@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	api "github.com/Morras/neutrinoapi"
+	"net/http"
+	"time"
+)
+
+// Clients knew the events by these names before the endpoints published domain events, so joining
+// and moving keep their old names.
+var eventNames = map[api.GameEventType]string{
+	api.GAME_CREATED:      "game-created",
+	api.PLAYER_JOINED:     "game-started",
+	api.MOVE_MADE:         "game-updated",
+	api.GAME_FINISHED:     "game-finished",
+	api.GAME_CANCELLED:    "game-cancelled",
+	api.PLAYER_REASSIGNED: "player-reassigned",
+}
+
+// handleEvents streams Server-Sent Events about every game the player is in, until they disconnect.
+// Each event carries the game as JSON, the same way the game endpoint returns it.
+func (s *server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	userID, err := s.parser.GetUserID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	events, unsubscribe := s.streams.Subscribe(userID)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(s.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event := <-events:
+			data, err := json.Marshal(event.Game)
+			if err != nil {
+				fmt.Printf("Error marshalling event for game %v: %v\n", event.Game.GameID, err)
+				continue
+			}
+			fmt.Fprintf(w, "event: %v\ndata: %s\n\n", eventNames[event.Type], data)
+		case <-heartbeat.C:
+			// Comments are ignored by clients, this only keeps the connection alive
+			fmt.Fprint(w, ": heartbeat\n\n")
+		}
+		flusher.Flush()
+	}
+}
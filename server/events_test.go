@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bufio"
+	api "github.com/Morras/neutrinoapi"
+	"github.com/Morras/neutrinoapi/spy"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+var _ = Describe("Event stream", func() {
+
+	testUserID := "TestUserId"
+
+	var parserSpy *spy.RequestParserSpy
+	var streams *api.PlayerEventStreams
+	var testServer *httptest.Server
+
+	BeforeEach(func() {
+		parserSpy = &spy.RequestParserSpy{UserID: testUserID}
+		streams = api.NewPlayerEventStreams()
+		s := &server{parser: parserSpy, streams: streams, heartbeat: time.Hour}
+		testServer = httptest.NewServer(s.routes())
+	})
+
+	AfterEach(func() {
+		testServer.Close()
+	})
+
+	// Reads lines until the blank line that ends an event
+	readEvent := func(reader *bufio.Reader) []string {
+		lines := []string{}
+		for {
+			line, err := reader.ReadString('\n')
+			Expect(err).To(BeNil())
+			if line == "\n" {
+				return lines
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	It("Should push events about the players games as they happen", func() {
+		resp, err := http.Get(testServer.URL + "/events")
+		Expect(err).To(BeNil())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(BeIdenticalTo(http.StatusOK))
+		Expect(resp.Header.Get("Content-Type")).To(BeIdenticalTo("text/event-stream"))
+
+		streams.HandleGameEvent(&api.GameEvent{Type: api.PLAYER_JOINED, Game: &api.Game{GameID: "game id", PlayerOneID: testUserID, PlayerTwoID: "opponent"}})
+		streams.HandleGameEvent(&api.GameEvent{Type: api.GAME_FINISHED, Game: &api.Game{GameID: "game id", PlayerOneID: testUserID, PlayerTwoID: "opponent"}})
+
+		reader := bufio.NewReader(resp.Body)
+		started := readEvent(reader)
+		Expect(started[0]).To(BeIdenticalTo("event: game-started\n"))
+		Expect(started[1]).To(ContainSubstring(`"GameID":"game id"`))
+		Expect(readEvent(reader)[0]).To(BeIdenticalTo("event: game-finished\n"))
+	})
+
+	It("Should send heartbeats while nothing happens", func() {
+		testServer.Close()
+		s := &server{parser: parserSpy, streams: streams, heartbeat: 10 * time.Millisecond}
+		testServer = httptest.NewServer(s.routes())
+
+		resp, err := http.Get(testServer.URL + "/events")
+		Expect(err).To(BeNil())
+		defer resp.Body.Close()
+		Expect(readEvent(bufio.NewReader(resp.Body))).To(Equal([]string{": heartbeat\n"}))
+	})
+
+	It("Should refuse players who are not logged in", func() {
+		parserSpy.Err = api.ErrMissingJWT
+		resp, err := http.Get(testServer.URL + "/events")
+		Expect(err).To(BeNil())
+		resp.Body.Close()
+		Expect(resp.StatusCode).To(BeIdenticalTo(http.StatusForbidden))
+	})
+})
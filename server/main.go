@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"github.com/Morras/go-neutrino/game"
+	api "github.com/Morras/neutrinoapi"
+	"github.com/Morras/neutrinoapi/auth"
+	"github.com/Morras/neutrinoapi/bot"
+	"github.com/Morras/neutrinoapi/spy"
+	"net/http"
+	"os"
+	"time"
+)
+
+// The standalone server keeps connections open to push game events to the players, which the
+// lambda handlers in application cannot do.
+func main() {
+	authenticator, err := auth.NewAuthenticator(auth.ConfigFromEnv(api.FIREBASE_PROJECT_ID))
+	if err != nil {
+		panic(err)
+	}
+
+	gameDataStore := &spy.GameDataStoreSpy{}                               //TODO substitute datastore
+	ratingDataStore := &spy.RatingDataStoreSpy{}                           //TODO substitute datastore
+	rater := api.NewRater(ratingDataStore, &spy.LeaderboardDataStoreSpy{}) //TODO substitute datastore
+	botPlayer := bot.NewMinimaxBot(func() game.GameController { return &game.Controller{} }, api.ANALYSIS_NODE_BUDGET)
+	streams := api.NewPlayerEventStreams()
+	inProcessPublisher := api.NewInProcessGameEventPublisher()
+	inProcessPublisher.Subscribe(api.EVENT_HANDLER_STREAMS, streams)
+	webhookDataStore := &spy.WebhookDataStoreSpy{} //TODO substitute datastore
+	webhookDispatcher := api.NewWebhookDispatcher(webhookDataStore, &http.Client{Timeout: api.WEBHOOK_TIMEOUT}, api.WEBHOOK_MAX_ATTEMPTS, api.WEBHOOK_INITIAL_BACKOFF)
+	inProcessPublisher.Subscribe(api.EVENT_HANDLER_WEBHOOKS, webhookDispatcher)
+	go deliverWebhooks(webhookDispatcher, api.WEBHOOK_DELIVERY_INTERVAL)
+	notificationDataStore := &spy.NotificationDataStoreSpy{} //TODO substitute datastore
+	inProcessPublisher.Subscribe(api.EVENT_HANDLER_NOTIFICATIONS, api.NewGameEventNotifier(notificationDataStore, &api.LoggingNotifier{}))
+	// Events that cannot be handled wait in the outbox until retryOutbox gets to them
+	publisher := api.NewOutboxPublisher(inProcessPublisher, &spy.OutboxDataStoreSpy{}) //TODO substitute datastore
+	go retryOutbox(publisher, api.OUTBOX_RETRY_INTERVAL)
+
+	var auditSink api.AuditSink = api.NewDataStoreAuditSink(&spy.AuditDataStoreSpy{}) //TODO substitute datastore
+	if path := os.Getenv(api.AUDIT_LOG_FILE_ENV); path != "" {
+		if auditSink, err = api.OpenJSONLinesAuditSink(path); err != nil {
+			panic(err)
+		}
+	}
+
+	go substituteBots(api.NewBotFallback(gameDataStore, api.DEFAULT_BOT_FALLBACK_AFTER, publisher, auditSink), api.BOT_FALLBACK_INTERVAL)
+
+	s := &server{
+		parser:           api.NewRequestParser(authenticator),
+		streams:          streams,
+		heartbeat:        api.EVENT_STREAM_HEARTBEAT,
+		getGameEndpoint:  api.NewGetGameEndpoint(gameDataStore, ratingDataStore),
+		newGameEndpoint:  api.NewNewGameEndpoint(gameDataStore, api.NewRatingWindowMatchmaker(gameDataStore, ratingDataStore), api.DEFAULT_RECENT_OPPONENTS_TO_AVOID, publisher, auditSink),
+		makeMoveEndpoint: api.NewMakeMoveEndpoint(gameDataStore, rater, botPlayer, publisher, auditSink),
+	}
+
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = api.DEFAULT_PORT
+	}
+	if err := http.ListenAndServe(":"+port, s.routes()); err != nil {
+		panic(err)
+	}
+}
+
+// Game events only queue their webhooks, the posting happens here, away from the requests.
+func deliverWebhooks(dispatcher *api.WebhookDispatcher, interval time.Duration) {
+	for range time.Tick(interval) {
+		if _, err := dispatcher.DeliverPending(); err != nil {
+			fmt.Printf("Error delivering webhooks: %v\n", err)
+		}
+	}
+}
+
+// Events that did not reach all of their handlers are published again here, to the handlers that missed them.
+func retryOutbox(publisher *api.OutboxPublisher, interval time.Duration) {
+	for range time.Tick(interval) {
+		if _, err := publisher.RetryOutbox(); err != nil {
+			fmt.Printf("Error retrying the outbox: %v\n", err)
+		}
+	}
+}
+
+// Games that nobody joins get a bot, without the waiting player having to do anything.
+func substituteBots(fallback *api.BotFallback, interval time.Duration) {
+	for range time.Tick(interval) {
+		if _, err := fallback.SubstituteBots(api.NewRequestIDs("")); err != nil {
+			fmt.Printf("Error handing waiting games to bots: %v\n", err)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/Morras/go-neutrino/game"
+	api "github.com/Morras/neutrinoapi"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type server struct {
+	parser    api.RequestParser
+	streams   *api.PlayerEventStreams
+	heartbeat time.Duration
+
+	getGameEndpoint  *api.GetGameEndpoint
+	newGameEndpoint  *api.NewGameEndpoint
+	makeMoveEndpoint *api.MakeMoveEndpoint
+}
+
+func (s *server) routes() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/game", s.handleGetGame)
+	mux.HandleFunc("/newGame", s.handleNewGame)
+	mux.HandleFunc("/makeMove", s.handleMakeMove)
+	mux.HandleFunc("/events", s.handleEvents)
+	mux.HandleFunc("/ws", s.handleWebSocket)
+	return mux
+}
+
+func (s *server) handleGetGame(w http.ResponseWriter, r *http.Request) {
+	userID, err := s.parser.GetUserID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	gameID := r.URL.Query().Get(api.QUERY_GET_GAME_GAME_ID)
+	// Do not care about errors as parse errors return false anyway
+	includeInactive, _ := strconv.ParseBool(r.URL.Query().Get(api.QUERY_GET_GAME_INCLUDE_INACTIVE))
+
+	games, statusCode := s.getGameEndpoint.PerformAction(userID, gameID, includeInactive)
+	writeJSON(w, games, statusCode)
+}
+
+func (s *server) handleNewGame(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	userID, err := s.parser.GetUserID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	visibility := api.PLAYERS_ONLY
+	if param := r.URL.Query().Get(api.QUERY_NEW_GAME_VISIBILITY); param != "" {
+		if visibility, err = api.ParseVisibility(param); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	gameID, statusCode := s.newGameEndpoint.PerformAction(requestIDs(r), userID, visibility)
+	writeJSON(w, gameID, statusCode)
+}
+
+func (s *server) handleMakeMove(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	userID, err := s.parser.GetUserID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	makeMoveReq := &api.MakeMoveRequest{}
+	if err = json.NewDecoder(r.Body).Decode(makeMoveReq); err != nil || makeMoveReq.GameID == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	statusCode := s.makeMoveEndpoint.PerformAction(requestIDs(r), userID, makeMoveReq, &game.Controller{})
+	w.WriteHeader(statusCode)
+}
+
+// Proxies in front of the server usually set a request ID, keeping theirs next to ours lets their
+// logs be matched up with the audit log
+func requestIDs(r *http.Request) api.RequestIDs {
+	return api.NewRequestIDs(r.Header.Get(api.REQUEST_ID_HEADER_KEY))
+}
+
+func writeJSON(w http.ResponseWriter, body interface{}, statusCode int) {
+	if statusCode != http.StatusOK {
+		w.WriteHeader(statusCode)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		fmt.Printf("Error writing response %v\n", err)
+	}
+}
@@ -0,0 +1,300 @@
+package main
+
+import (
+	"fmt"
+	"github.com/Morras/go-neutrino/game"
+	api "github.com/Morras/neutrinoapi"
+	"github.com/gorilla/websocket"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Message types sent by the client
+const (
+	wsSubscribe   = "subscribe"
+	wsUnsubscribe = "unsubscribe"
+	wsMove        = "move"
+)
+
+// Message types sent by the server
+const (
+	wsGame       = "game"
+	wsMoveResult = "moveResult"
+	wsError      = "error"
+)
+
+// Sent instead of an event name when a subscription catches the client up on a game
+const gameSnapshotEvent = "game-snapshot"
+
+const wsWriteWait = 10 * time.Second
+const wsOutgoingBufferSize = 16
+
+// Only ever agrees to the token protocol, the token that comes after it is not a protocol
+var upgrader = websocket.Upgrader{Subprotocols: []string{api.WEB_SOCKET_TOKEN_PROTOCOL}}
+
+type wsClientMessage struct {
+	Type    string
+	GameID  string
+	Version int // The last version of the game the client has seen, for resuming after a reconnect
+	Move    *api.MakeMoveRequest
+}
+
+type wsServerMessage struct {
+	Type       string
+	GameID     string
+	Event      string    `json:",omitempty"`
+	Game       *api.Game `json:",omitempty"`
+	StatusCode int       `json:",omitempty"`
+}
+
+type wsConnection struct {
+	s         *server
+	conn      *websocket.Conn
+	userID    string
+	out       chan *wsServerMessage
+	spectated chan *api.GameEvent // Events from every public game the client is watching without playing in it
+	closed    chan struct{}
+
+	mutex      sync.Mutex
+	subscribed map[string]bool
+	spectating map[string]func() // Stops the events for the game
+}
+
+// handleWebSocket lets players in live games subscribe to them, play their moves and hear about the
+// opponents moves over a single connection. Anyone can also watch public games. The player is
+// authenticated once when the connection is opened.
+func (s *server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	userID, err := s.parser.GetUserID(withWebSocketToken(r))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// The upgrader has already told the client what went wrong
+		return
+	}
+
+	events, unsubscribe := s.streams.Subscribe(userID)
+	defer unsubscribe()
+
+	c := &wsConnection{
+		s:          s,
+		conn:       conn,
+		userID:     userID,
+		out:        make(chan *wsServerMessage, wsOutgoingBufferSize),
+		spectated:  make(chan *api.GameEvent, wsOutgoingBufferSize),
+		closed:     make(chan struct{}),
+		subscribed: map[string]bool{},
+		spectating: map[string]func(){},
+	}
+
+	readDone := make(chan struct{})
+	go func() {
+		c.readLoop()
+		close(readDone)
+	}()
+	c.writeLoop(events, readDone)
+
+	conn.Close()
+	close(c.closed)
+	// Nothing can start spectating once the read loop is done
+	<-readDone
+	c.mutex.Lock()
+	for _, stop := range c.spectating {
+		stop()
+	}
+	c.mutex.Unlock()
+}
+
+// Browsers cannot set headers on the websocket handshake, so the token can also be sent as a query
+// parameter, or as the subprotocol after WEB_SOCKET_TOKEN_PROTOCOL. The subprotocol is better, as
+// query parameters tend to end up in access logs.
+func withWebSocketToken(r *http.Request) *http.Request {
+	if r.Header.Get(api.JWT_HEADER_KEY) != "" {
+		return r
+	}
+	token := r.URL.Query().Get(api.QUERY_WEB_SOCKET_TOKEN)
+	if protocols := websocket.Subprotocols(r); len(protocols) == 2 && protocols[0] == api.WEB_SOCKET_TOKEN_PROTOCOL {
+		token = protocols[1]
+	}
+	if token == "" {
+		return r
+	}
+	r = r.Clone(r.Context())
+	r.Header.Set(api.JWT_HEADER_KEY, token)
+	return r
+}
+
+// gorilla/websocket allows one reader and one writer at a time, so all writes happen here.
+func (c *wsConnection) writeLoop(events <-chan *api.GameEvent, readDone <-chan struct{}) {
+	ping := time.NewTicker(c.s.heartbeat)
+	defer ping.Stop()
+
+	for {
+		var err error
+		select {
+		case <-readDone:
+			return
+		case event := <-events:
+			err = c.writeEvent(event)
+		case event := <-c.spectated:
+			err = c.writeEvent(event)
+		case message := <-c.out:
+			err = c.write(message)
+		case <-ping.C:
+			err = c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait))
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (c *wsConnection) writeEvent(event *api.GameEvent) error {
+	if !c.isSubscribed(event.Game.GameID) {
+		return nil
+	}
+	return c.write(&wsServerMessage{Type: wsGame, GameID: event.Game.GameID, Event: eventNames[event.Type], Game: event.Game})
+}
+
+func (c *wsConnection) write(message *wsServerMessage) error {
+	c.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+	return c.conn.WriteJSON(message)
+}
+
+// Clients that stop answering pings are dropped after missing two of them
+func (c *wsConnection) readLoop() {
+	pongWait := 2 * c.s.heartbeat
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		message := &wsClientMessage{}
+		if err := c.conn.ReadJSON(message); err != nil {
+			if _, isCloseError := err.(*websocket.CloseError); !isCloseError {
+				fmt.Printf("Error reading from websocket for %v: %v\n", c.userID, err)
+			}
+			return
+		}
+		if reply := c.handle(message); reply != nil {
+			select {
+			case c.out <- reply:
+			case <-c.closed:
+				return
+			}
+		}
+	}
+}
+
+func (c *wsConnection) handle(message *wsClientMessage) *wsServerMessage {
+	switch message.Type {
+	case wsSubscribe:
+		return c.subscribe(message.GameID, message.Version)
+	case wsUnsubscribe:
+		c.unsubscribe(message.GameID)
+		return nil
+	case wsMove:
+		if message.Move == nil {
+			return &wsServerMessage{Type: wsError, StatusCode: http.StatusBadRequest}
+		}
+		// The new state of the game reaches the client as an event, like it does for the opponent. The
+		// connection can carry any number of moves, so every one of them gets a request ID of its own
+		statusCode := c.s.makeMoveEndpoint.PerformAction(api.NewRequestIDs(""), c.userID, message.Move, &game.Controller{})
+		return &wsServerMessage{Type: wsMoveResult, GameID: message.Move.GameID, StatusCode: statusCode}
+	default:
+		return &wsServerMessage{Type: wsError, StatusCode: http.StatusBadRequest}
+	}
+}
+
+// subscribe sends the game right away if it has changed since the version the client last saw. The
+// subscription starts before the game is fetched, so no change can slip in between the two. The
+// client can get a change both ways, and should go by the version of the game.
+func (c *wsConnection) subscribe(gameID string, version int) *wsServerMessage {
+	// The get game endpoint lists the players active games when there is no game ID
+	if gameID == "" {
+		return &wsServerMessage{Type: wsError, StatusCode: http.StatusBadRequest}
+	}
+
+	// Players only ever get events for their own games, so this shows nothing they could not see anyway
+	c.mutex.Lock()
+	c.subscribed[gameID] = true
+	c.mutex.Unlock()
+
+	dsGame, statusCode := c.game(gameID)
+	if statusCode == http.StatusOK && dsGame.PlayerOneID != c.userID && dsGame.PlayerTwoID != c.userID {
+		// Spectators are only known to be allowed to watch the game now, so the game is fetched again
+		// to catch any change from before they started watching it
+		c.spectate(gameID)
+		dsGame, statusCode = c.game(gameID)
+	}
+	if statusCode != http.StatusOK {
+		c.unsubscribe(gameID)
+		return &wsServerMessage{Type: wsError, GameID: gameID, StatusCode: statusCode}
+	}
+
+	if dsGame.Version <= version {
+		return nil
+	}
+	return &wsServerMessage{Type: wsGame, GameID: gameID, Event: gameSnapshotEvent, Game: dsGame}
+}
+
+func (c *wsConnection) game(gameID string) (*api.Game, int) {
+	games, statusCode := c.s.getGameEndpoint.PerformAction(c.userID, gameID, false)
+	if statusCode != http.StatusOK {
+		return nil, statusCode
+	}
+	if len(games) != 1 {
+		return nil, http.StatusInternalServerError
+	}
+	return games[0], http.StatusOK
+}
+
+func (c *wsConnection) spectate(gameID string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.spectating[gameID] != nil {
+		return
+	}
+
+	events, unsubscribe := c.s.streams.SubscribeToGame(gameID)
+	stop := make(chan struct{})
+	c.spectating[gameID] = func() {
+		unsubscribe()
+		close(stop)
+	}
+	go func() {
+		for {
+			select {
+			case event := <-events:
+				select {
+				case c.spectated <- event:
+				case <-stop:
+					return
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+func (c *wsConnection) unsubscribe(gameID string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.subscribed, gameID)
+	if stop := c.spectating[gameID]; stop != nil {
+		stop()
+		delete(c.spectating, gameID)
+	}
+}
+
+func (c *wsConnection) isSubscribed(gameID string) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.subscribed[gameID]
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"errors"
+	api "github.com/Morras/neutrinoapi"
+	"github.com/Morras/neutrinoapi/spy"
+	"github.com/gorilla/websocket"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+)
+
+var _ = Describe("WebSocket", func() {
+
+	testUserID := "TestUserId"
+
+	var parserSpy *spy.RequestParserSpy
+	var dataStoreSpy *spy.GameDataStoreSpy
+	var streams *api.PlayerEventStreams
+	var testServer *httptest.Server
+	var conn *websocket.Conn
+
+	BeforeEach(func() {
+		parserSpy = &spy.RequestParserSpy{UserID: testUserID}
+		dataStoreSpy = &spy.GameDataStoreSpy{}
+		dataStoreSpy.GameReturn = &api.Game{GameID: "game id", PlayerOneID: testUserID, PlayerTwoID: "opponent", Version: 3}
+		ratingDataStoreSpy := &spy.RatingDataStoreSpy{}
+		streams = api.NewPlayerEventStreams()
+		publisher := api.NewInProcessGameEventPublisher()
+		publisher.Subscribe(api.EVENT_HANDLER_STREAMS, streams)
+		s := &server{
+			parser:           parserSpy,
+			streams:          streams,
+			heartbeat:        time.Hour,
+			getGameEndpoint:  api.NewGetGameEndpoint(dataStoreSpy, ratingDataStoreSpy),
+			makeMoveEndpoint: api.NewMakeMoveEndpoint(dataStoreSpy, api.NewRater(ratingDataStoreSpy, &spy.LeaderboardDataStoreSpy{}), &spy.BotPlayerSpy{}, publisher, &spy.AuditSinkSpy{}),
+		}
+		testServer = httptest.NewServer(s.routes())
+	})
+
+	AfterEach(func() {
+		if conn != nil {
+			conn.Close()
+			conn = nil
+		}
+		testServer.Close()
+	})
+
+	dial := func() *websocket.Conn {
+		c, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(testServer.URL, "http")+"/ws", nil)
+		Expect(err).To(BeNil())
+		return c
+	}
+
+	receive := func() *wsServerMessage {
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+		message := &wsServerMessage{}
+		Expect(conn.ReadJSON(message)).To(Succeed())
+		return message
+	}
+
+	// Nothing is sent back when subscribing to a game the client is up to date on, so this is the
+	// only way to know the subscription has been handled
+	subscribeUpToDate := func() {
+		Expect(conn.WriteJSON(&wsClientMessage{Type: wsSubscribe, GameID: "game id", Version: 3})).To(Succeed())
+		Expect(conn.WriteJSON(&wsClientMessage{Type: "ping"})).To(Succeed())
+		Expect(receive().Type).To(BeIdenticalTo(wsError))
+	}
+
+	It("Should refuse players who are not logged in", func() {
+		parserSpy.Err = api.ErrMissingJWT
+		_, resp, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(testServer.URL, "http")+"/ws", nil)
+		Expect(err).ToNot(BeNil())
+		Expect(resp.StatusCode).To(BeIdenticalTo(http.StatusForbidden))
+	})
+
+	It("Should catch the client up on a game that changed while it was away", func() {
+		conn = dial()
+		Expect(conn.WriteJSON(&wsClientMessage{Type: wsSubscribe, GameID: "game id", Version: 2})).To(Succeed())
+		message := receive()
+		Expect(message.Type).To(BeIdenticalTo(wsGame))
+		Expect(message.Event).To(BeIdenticalTo(gameSnapshotEvent))
+		Expect(message.Game.Version).To(BeIdenticalTo(3))
+	})
+
+	It("Should push changes to subscribed games", func() {
+		conn = dial()
+		subscribeUpToDate()
+		streams.HandleGameEvent(&api.GameEvent{Type: api.MOVE_MADE, Game: &api.Game{GameID: "game id", PlayerOneID: testUserID, Version: 4}})
+		message := receive()
+		Expect(message.Event).To(BeIdenticalTo("game-updated"))
+		Expect(message.Game.Version).To(BeIdenticalTo(4))
+	})
+
+	It("Should not push changes to games the client has not subscribed to", func() {
+		conn = dial()
+		subscribeUpToDate()
+		streams.HandleGameEvent(&api.GameEvent{Type: api.MOVE_MADE, Game: &api.Game{GameID: "other game", PlayerOneID: testUserID}})
+		streams.HandleGameEvent(&api.GameEvent{Type: api.GAME_FINISHED, Game: &api.Game{GameID: "game id", PlayerOneID: testUserID}})
+		Expect(receive().Event).To(BeIdenticalTo("game-finished"))
+	})
+
+	It("Should refuse subscriptions to games the player cannot see", func() {
+		conn = dial()
+		dataStoreSpy.GameReturn = &api.Game{GameID: "game id", PlayerOneID: "someone", PlayerTwoID: "someone else"}
+		Expect(conn.WriteJSON(&wsClientMessage{Type: wsSubscribe, GameID: "game id"})).To(Succeed())
+		message := receive()
+		Expect(message.Type).To(BeIdenticalTo(wsError))
+		Expect(message.StatusCode).To(BeIdenticalTo(http.StatusForbidden))
+	})
+
+	It("Should answer subscriptions without a game with bad request", func() {
+		conn = dial()
+		dataStoreSpy.ActiveGamesReturn = []*api.Game{}
+		Expect(conn.WriteJSON(&wsClientMessage{Type: wsSubscribe})).To(Succeed())
+		message := receive()
+		Expect(message.Type).To(BeIdenticalTo(wsError))
+		Expect(message.StatusCode).To(BeIdenticalTo(http.StatusBadRequest))
+		Expect(dataStoreSpy.ActiveGamesUserID).To(BeEmpty())
+	})
+
+	It("Should not subscribe to the players active games when the game is missing", func() {
+		conn = dial()
+		dataStoreSpy.ActiveGamesReturn = []*api.Game{{GameID: "game id", PlayerOneID: testUserID, Version: 3}}
+		Expect(conn.WriteJSON(&wsClientMessage{Type: wsSubscribe})).To(Succeed())
+		Expect(receive().StatusCode).To(BeIdenticalTo(http.StatusBadRequest))
+		streams.HandleGameEvent(&api.GameEvent{Type: api.MOVE_MADE, Game: &api.Game{PlayerOneID: testUserID}})
+		Expect(conn.WriteJSON(&wsClientMessage{Type: "ping"})).To(Succeed())
+		Expect(receive().Type).To(BeIdenticalTo(wsError))
+	})
+
+	It("Should take the token from the query when the browser cannot set headers", func() {
+		c, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(testServer.URL, "http")+"/ws?token=test-token", nil)
+		Expect(err).To(BeNil())
+		conn = c
+		Expect(parserSpy.Request.Header.Get(api.JWT_HEADER_KEY)).To(BeIdenticalTo("test-token"))
+	})
+
+	It("Should take the token from the subprotocols and agree to the token protocol", func() {
+		dialer := websocket.Dialer{Subprotocols: []string{api.WEB_SOCKET_TOKEN_PROTOCOL, "test-token"}}
+		c, _, err := dialer.Dial("ws"+strings.TrimPrefix(testServer.URL, "http")+"/ws", nil)
+		Expect(err).To(BeNil())
+		conn = c
+		Expect(parserSpy.Request.Header.Get(api.JWT_HEADER_KEY)).To(BeIdenticalTo("test-token"))
+		Expect(conn.Subprotocol()).To(BeIdenticalTo(api.WEB_SOCKET_TOKEN_PROTOCOL))
+	})
+
+	It("Should push changes to public games the client is watching", func() {
+		conn = dial()
+		dataStoreSpy.GameReturn = &api.Game{GameID: "game id", PlayerOneID: "someone", PlayerTwoID: "someone else", Visibility: api.PUBLIC, Version: 3}
+		subscribeUpToDate()
+		streams.HandleGameEvent(&api.GameEvent{Type: api.MOVE_MADE, Game: &api.Game{GameID: "game id", PlayerOneID: "someone",
+			PlayerTwoID: "someone else", Visibility: api.PUBLIC, InviteCode: "invite code", Version: 4}})
+		message := receive()
+		Expect(message.Event).To(BeIdenticalTo("game-updated"))
+		Expect(message.Game.Version).To(BeIdenticalTo(4))
+		Expect(message.Game.InviteCode).To(BeIdenticalTo(""))
+	})
+
+	It("Should not push changes to games the client was refused", func() {
+		conn = dial()
+		dataStoreSpy.GameReturn = &api.Game{GameID: "game id", PlayerOneID: "someone", PlayerTwoID: "someone else"}
+		Expect(conn.WriteJSON(&wsClientMessage{Type: wsSubscribe, GameID: "game id"})).To(Succeed())
+		Expect(receive().StatusCode).To(BeIdenticalTo(http.StatusForbidden))
+		streams.HandleGameEvent(&api.GameEvent{Type: api.MOVE_MADE, Game: &api.Game{GameID: "game id", PlayerOneID: "someone", PlayerTwoID: "someone else"}})
+		Expect(conn.WriteJSON(&wsClientMessage{Type: "ping"})).To(Succeed())
+		Expect(receive().Type).To(BeIdenticalTo(wsError))
+	})
+
+	It("Should play moves sent over the socket and report how it went", func() {
+		conn = dial()
+		dataStoreSpy.GameErr = errors.New("Error getting game")
+		Expect(conn.WriteJSON(&wsClientMessage{Type: wsMove, Move: &api.MakeMoveRequest{GameID: "game id"}})).To(Succeed())
+		message := receive()
+		Expect(message.Type).To(BeIdenticalTo(wsMoveResult))
+		Expect(message.GameID).To(BeIdenticalTo("game id"))
+		Expect(message.StatusCode).To(BeIdenticalTo(http.StatusInternalServerError))
+		Expect(dataStoreSpy.GameGameID).To(BeIdenticalTo("game id"))
+	})
+
+	It("Should answer moves without a move with bad request", func() {
+		conn = dial()
+		Expect(conn.WriteJSON(&wsClientMessage{Type: wsMove})).To(Succeed())
+		Expect(receive().StatusCode).To(BeIdenticalTo(http.StatusBadRequest))
+	})
+})
@@ -0,0 +1,22 @@
+package neutrinoapi
+
+import "net/http"
+
+type SetNotificationPreferencesEndpoint struct {
+	nds NotificationDataStore
+}
+
+func NewSetNotificationPreferencesEndpoint(nds NotificationDataStore) *SetNotificationPreferencesEndpoint {
+	return &SetNotificationPreferencesEndpoint{nds: nds}
+}
+
+func (spe *SetNotificationPreferencesEndpoint) PerformAction(userID string, preferences *NotificationPreferences) int {
+	if preferences == nil {
+		return http.StatusBadRequest
+	}
+
+	if err := spe.nds.SetNotificationPreferences(userID, preferences); err != nil {
+		return http.StatusInternalServerError
+	}
+	return http.StatusOK
+}
@@ -0,0 +1,44 @@
+package neutrinoapi_test
+
+import (
+	"errors"
+	api "github.com/Morras/neutrinoapi"
+	"github.com/Morras/neutrinoapi/spy"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+)
+
+var _ = Describe("setNotificationPreferencesEndpoint", func() {
+
+	testUserID := "TestUserId"
+
+	var dataStoreSpy *spy.NotificationDataStoreSpy
+	var endpoint *api.SetNotificationPreferencesEndpoint
+
+	BeforeEach(func() {
+		dataStoreSpy = &spy.NotificationDataStoreSpy{}
+		endpoint = api.NewSetNotificationPreferencesEndpoint(dataStoreSpy)
+	})
+
+	Context("performAction method", func() {
+		It("Should save the preferences for the user", func() {
+			preferences := &api.NotificationPreferences{YourTurn: true}
+			code := endpoint.PerformAction(testUserID, preferences)
+			Expect(code).To(BeIdenticalTo(http.StatusOK))
+			Expect(dataStoreSpy.SetNotificationPreferencesUserID).To(BeIdenticalTo(testUserID))
+			Expect(dataStoreSpy.SetNotificationPreferencesPreferences).To(BeIdenticalTo(preferences))
+		})
+
+		It("Should reject missing preferences", func() {
+			code := endpoint.PerformAction(testUserID, nil)
+			Expect(code).To(BeIdenticalTo(http.StatusBadRequest))
+		})
+
+		It("Should return an internal server error if the preferences could not be saved", func() {
+			dataStoreSpy.SetNotificationPreferencesErr = errors.New("error saving preferences")
+			code := endpoint.PerformAction(testUserID, &api.NotificationPreferences{})
+			Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
+		})
+	})
+})
@@ -0,0 +1,91 @@
+package neutrinoapi
+
+import (
+	"github.com/Morras/go-neutrino/game"
+	"net/http"
+	"time"
+)
+
+type PuzzleResult struct {
+	Solved bool
+	Rating int // The players puzzle rating after the attempt
+}
+
+type SolvePuzzleEndpoint struct {
+	pds      PuzzleDataStore
+	analyzer Analyzer
+}
+
+func NewSolvePuzzleEndpoint(pds PuzzleDataStore, analyzer Analyzer) *SolvePuzzleEndpoint {
+	return &SolvePuzzleEndpoint{pds: pds, analyzer: analyzer}
+}
+
+// PerformAction checks the first turn of the solution. The puzzle is solved if the turn wins right
+// away, or if the player can still force the win in the turns the puzzle has left. Only the first
+// attempt at a puzzle is rated, later attempts are checked but leave both ratings alone.
+func (spe *SolvePuzzleEndpoint) PerformAction(userID string, puzzleID string, turn *MakeMoveRequest, gameController game.GameController) (*PuzzleResult, int) {
+	puzzle, err := spe.pds.Puzzle(puzzleID)
+	if err != nil {
+		return nil, http.StatusInternalServerError
+	}
+	if puzzle == nil {
+		return nil, http.StatusNotFound
+	}
+
+	position := game.UInt64ToGame(puzzle.SerializedGame)
+	playerOneToMove := position.State == game.Player1NeutrinoMove || position.State == game.Player1Move
+	gameController.PlayGame(position)
+
+	state, _, err := makeMoves(gameController, turn)
+	if err != nil {
+		return nil, http.StatusBadRequest
+	}
+
+	solved := false
+	if isGameOver(state) {
+		solved = (state == game.Player1Win) == playerOneToMove
+	} else if puzzle.Plies > 1 {
+		analysis, err := spe.analyzer.Analyze(gameController.Game(), puzzle.Plies-1)
+		if err != nil {
+			return nil, http.StatusInternalServerError
+		}
+		// The analysis is from the opponents side, who is to move after the turn
+		solved = analysis.ForcedLoss
+	}
+
+	return spe.recordAttempt(userID, puzzle, solved)
+}
+
+func (spe *SolvePuzzleEndpoint) recordAttempt(userID string, puzzle *Puzzle, solved bool) (*PuzzleResult, int) {
+	rating, err := puzzleRatingOrDefault(spe.pds, userID)
+	if err != nil {
+		return nil, http.StatusInternalServerError
+	}
+
+	previous, err := spe.pds.Attempt(userID, puzzle.PuzzleID)
+	if err != nil {
+		return nil, http.StatusInternalServerError
+	}
+	if previous != nil {
+		return &PuzzleResult{Solved: solved, Rating: rating.Rating}, http.StatusOK
+	}
+
+	score := 0.0
+	if solved {
+		score = 1
+	}
+	attempt := &PuzzleAttempt{
+		UserID:            userID,
+		PuzzleID:          puzzle.PuzzleID,
+		Solved:            solved,
+		RatingBefore:      rating.Rating,
+		RatingAfter:       newEloRating(rating.Rating, puzzle.Rating, score),
+		PuzzleRatingAfter: newEloRating(puzzle.Rating, rating.Rating, 1-score),
+		Time:              time.Now(),
+	}
+	if err = spe.pds.RecordAttempt(attempt); err != nil {
+		return nil, http.StatusInternalServerError
+	}
+
+	return &PuzzleResult{Solved: solved, Rating: attempt.RatingAfter}, http.StatusOK
+}
@@ -0,0 +1,143 @@
+package neutrinoapi_test
+
+import (
+	"errors"
+	g "github.com/Morras/go-neutrino/game"
+	api "github.com/Morras/neutrinoapi"
+	"github.com/Morras/neutrinoapi/spy"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+)
+
+var _ = Describe("solvePuzzleEndpoint", func() {
+
+	testUserID := "TestUserId"
+
+	var puzzleDataStoreSpy *spy.PuzzleDataStoreSpy
+	var analyzerSpy *spy.AnalyzerSpy
+	var gameControllerSpy *spy.GameControllerSpy
+	var endpoint *api.SolvePuzzleEndpoint
+	var puzzle *api.Puzzle
+	var solution *api.MakeMoveRequest
+
+	BeforeEach(func() {
+		puzzleDataStoreSpy = &spy.PuzzleDataStoreSpy{}
+		analyzerSpy = &spy.AnalyzerSpy{AnalyzeReturn: &api.Analysis{ForcedLoss: true}}
+		gameControllerSpy = &spy.GameControllerSpy{MakeMoveReturn: g.Player2NeutrinoMove, GameReturn: g.NewStandardGame()}
+		endpoint = api.NewSolvePuzzleEndpoint(puzzleDataStoreSpy, analyzerSpy)
+
+		position := g.NewStandardGame()
+		position.State = g.Player1NeutrinoMove
+		puzzle = &api.Puzzle{PuzzleID: "puzzle id", SerializedGame: g.GameToUInt64(position), Plies: 2, Rating: api.INITIAL_RATING}
+		puzzleDataStoreSpy.PuzzleReturn = puzzle
+		solution = &api.MakeMoveRequest{NeutrinoFromX: 2, NeutrinoFromY: 2, NeutrinoToX: 2, NeutrinoToY: 3,
+			PieceFromX: 0, PieceFromY: 0, PieceToX: 0, PieceToY: 3}
+	})
+
+	Context("performAction method", func() {
+
+		It("Should play the solution on the puzzle position", func() {
+			endpoint.PerformAction(testUserID, "puzzle id", solution, gameControllerSpy)
+			Expect(puzzleDataStoreSpy.PuzzlePuzzleID).To(BeIdenticalTo("puzzle id"))
+			Expect(g.GameToUInt64(gameControllerSpy.PlayGameGame)).To(BeIdenticalTo(puzzle.SerializedGame))
+			Expect(gameControllerSpy.MakeMoveMove).To(Equal(g.NewMove(0, 0, 0, 3)))
+		})
+
+		It("Should count the puzzle as solved if the opponent cannot avoid losing", func() {
+			result, code := endpoint.PerformAction(testUserID, "puzzle id", solution, gameControllerSpy)
+			Expect(code).To(BeIdenticalTo(http.StatusOK))
+			Expect(result.Solved).To(BeTrue())
+			Expect(analyzerSpy.AnalyzePlies).To(BeIdenticalTo(1))
+		})
+
+		It("Should not count the puzzle as solved if the opponent can escape", func() {
+			analyzerSpy.AnalyzeReturn = &api.Analysis{}
+			result, _ := endpoint.PerformAction(testUserID, "puzzle id", solution, gameControllerSpy)
+			Expect(result.Solved).To(BeFalse())
+		})
+
+		It("Should count the puzzle as solved if the turn wins right away", func() {
+			gameControllerSpy.MakeMoveReturn = g.Player1Win
+			result, _ := endpoint.PerformAction(testUserID, "puzzle id", solution, gameControllerSpy)
+			Expect(result.Solved).To(BeTrue())
+			Expect(analyzerSpy.AnalyzeGame).To(BeNil())
+		})
+
+		It("Should not count the puzzle as solved if the turn loses right away", func() {
+			gameControllerSpy.MakeMoveReturn = g.Player2Win
+			result, _ := endpoint.PerformAction(testUserID, "puzzle id", solution, gameControllerSpy)
+			Expect(result.Solved).To(BeFalse())
+		})
+
+		It("Should not count the puzzle as solved if a win in one was missed", func() {
+			puzzle.Plies = 1
+			result, _ := endpoint.PerformAction(testUserID, "puzzle id", solution, gameControllerSpy)
+			Expect(result.Solved).To(BeFalse())
+		})
+
+		It("Should raise the players rating and lower the puzzles when solved", func() {
+			result, _ := endpoint.PerformAction(testUserID, "puzzle id", solution, gameControllerSpy)
+			attempt := puzzleDataStoreSpy.RecordAttemptAttempt
+			Expect(attempt.UserID).To(BeIdenticalTo(testUserID))
+			Expect(attempt.PuzzleID).To(BeIdenticalTo("puzzle id"))
+			Expect(attempt.RatingBefore).To(BeIdenticalTo(api.INITIAL_RATING))
+			Expect(attempt.RatingAfter).To(BeNumerically(">", api.INITIAL_RATING))
+			Expect(attempt.PuzzleRatingAfter).To(BeNumerically("<", api.INITIAL_RATING))
+			Expect(result.Rating).To(BeIdenticalTo(attempt.RatingAfter))
+		})
+
+		It("Should lower the players rating when failed", func() {
+			analyzerSpy.AnalyzeReturn = &api.Analysis{}
+			endpoint.PerformAction(testUserID, "puzzle id", solution, gameControllerSpy)
+			Expect(puzzleDataStoreSpy.RecordAttemptAttempt.RatingAfter).To(BeNumerically("<", api.INITIAL_RATING))
+		})
+
+		Context("and the player has attempted the puzzle before", func() {
+			BeforeEach(func() {
+				puzzleDataStoreSpy.AttemptReturn = &api.PuzzleAttempt{UserID: testUserID, PuzzleID: "puzzle id"}
+				puzzleDataStoreSpy.PuzzleRatingReturn = &api.Rating{UserID: testUserID, Rating: 1300}
+			})
+
+			It("Should still check the solution", func() {
+				result, code := endpoint.PerformAction(testUserID, "puzzle id", solution, gameControllerSpy)
+				Expect(code).To(BeIdenticalTo(http.StatusOK))
+				Expect(result.Solved).To(BeTrue())
+				Expect(puzzleDataStoreSpy.AttemptUserID).To(BeIdenticalTo(testUserID))
+				Expect(puzzleDataStoreSpy.AttemptPuzzleID).To(BeIdenticalTo("puzzle id"))
+			})
+
+			It("Should not change any ratings", func() {
+				result, _ := endpoint.PerformAction(testUserID, "puzzle id", solution, gameControllerSpy)
+				Expect(result.Rating).To(BeIdenticalTo(1300))
+				Expect(puzzleDataStoreSpy.RecordAttemptAttempt).To(BeNil())
+			})
+		})
+
+		It("Should return an internal server error if earlier attempts cannot be looked up", func() {
+			puzzleDataStoreSpy.AttemptErr = errors.New("Error getting attempt")
+			_, code := endpoint.PerformAction(testUserID, "puzzle id", solution, gameControllerSpy)
+			Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
+			Expect(puzzleDataStoreSpy.RecordAttemptAttempt).To(BeNil())
+		})
+
+		It("Should return bad request for an illegal turn", func() {
+			gameControllerSpy.MakeMoveErr = errors.New("Invalid move")
+			_, code := endpoint.PerformAction(testUserID, "puzzle id", solution, gameControllerSpy)
+			Expect(code).To(BeIdenticalTo(http.StatusBadRequest))
+			Expect(puzzleDataStoreSpy.RecordAttemptAttempt).To(BeNil())
+		})
+
+		It("Should return not found if the puzzle does not exist", func() {
+			puzzleDataStoreSpy.PuzzleReturn = nil
+			_, code := endpoint.PerformAction(testUserID, "puzzle id", solution, gameControllerSpy)
+			Expect(code).To(BeIdenticalTo(http.StatusNotFound))
+		})
+
+		It("Should return an internal server error if the attempt cannot be saved", func() {
+			puzzleDataStoreSpy.RecordAttemptErr = errors.New("Error saving attempt")
+			_, code := endpoint.PerformAction(testUserID, "puzzle id", solution, gameControllerSpy)
+			Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
+		})
+	})
+})
@@ -0,0 +1,19 @@
+package spy
+
+import (
+	"github.com/Morras/go-neutrino/game"
+	api "github.com/Morras/neutrinoapi"
+)
+
+type AnalyzerSpy struct {
+	AnalyzeGame   *game.Game
+	AnalyzePlies  int
+	AnalyzeReturn *api.Analysis
+	AnalyzeErr    error
+}
+
+func (spy *AnalyzerSpy) Analyze(g *game.Game, plies int) (*api.Analysis, error) {
+	spy.AnalyzeGame = g
+	spy.AnalyzePlies = plies
+	return spy.AnalyzeReturn, spy.AnalyzeErr
+}
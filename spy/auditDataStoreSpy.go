@@ -0,0 +1,28 @@
+package spy
+
+import api "github.com/Morras/neutrinoapi"
+
+type AuditDataStoreSpy struct {
+	AddAuditEntryEntries []*api.AuditEntry
+	AddAuditEntryErr     error
+
+	AuditEntriesGameID  string
+	AuditEntriesActorID string
+	AuditEntriesOffset  int
+	AuditEntriesCount   int
+	AuditEntriesReturn  []*api.AuditEntry
+	AuditEntriesErr     error
+}
+
+func (spy *AuditDataStoreSpy) AddAuditEntry(entry *api.AuditEntry) error {
+	spy.AddAuditEntryEntries = append(spy.AddAuditEntryEntries, entry)
+	return spy.AddAuditEntryErr
+}
+
+func (spy *AuditDataStoreSpy) AuditEntries(gameID string, actorID string, offset int, count int) ([]*api.AuditEntry, error) {
+	spy.AuditEntriesGameID = gameID
+	spy.AuditEntriesActorID = actorID
+	spy.AuditEntriesOffset = offset
+	spy.AuditEntriesCount = count
+	return spy.AuditEntriesReturn, spy.AuditEntriesErr
+}
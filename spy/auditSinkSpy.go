@@ -0,0 +1,13 @@
+package spy
+
+import api "github.com/Morras/neutrinoapi"
+
+type AuditSinkSpy struct {
+	RecordEntries []*api.AuditEntry
+	RecordErr     error
+}
+
+func (spy *AuditSinkSpy) Record(entry *api.AuditEntry) error {
+	spy.RecordEntries = append(spy.RecordEntries, entry)
+	return spy.RecordErr
+}
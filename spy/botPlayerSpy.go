@@ -0,0 +1,19 @@
+package spy
+
+import (
+	"github.com/Morras/go-neutrino/game"
+	api "github.com/Morras/neutrinoapi"
+)
+
+type BotPlayerSpy struct {
+	NextTurnGame       *game.Game
+	NextTurnDifficulty api.BotDifficulty
+	NextTurnReturn     *api.MakeMoveRequest
+	NextTurnErr        error
+}
+
+func (spy *BotPlayerSpy) NextTurn(g *game.Game, difficulty api.BotDifficulty) (*api.MakeMoveRequest, error) {
+	spy.NextTurnGame = g
+	spy.NextTurnDifficulty = difficulty
+	return spy.NextTurnReturn, spy.NextTurnErr
+}
@@ -0,0 +1,40 @@
+package spy
+
+import api "github.com/Morras/neutrinoapi"
+
+type ChallengeDataStoreSpy struct {
+	CreateChallengeChallenge *api.Challenge
+	CreateChallengeReturn    string
+	CreateChallengeErr       error
+
+	ChallengeChallengeID string
+	ChallengeReturn      *api.Challenge
+	ChallengeErr         error
+
+	PendingChallengesUserID string
+	PendingChallengesReturn []*api.Challenge
+	PendingChallengesErr    error
+
+	DeleteChallengeChallengeID string
+	DeleteChallengeErr         error
+}
+
+func (ds *ChallengeDataStoreSpy) CreateChallenge(challenge *api.Challenge) (string, error) {
+	ds.CreateChallengeChallenge = challenge
+	return ds.CreateChallengeReturn, ds.CreateChallengeErr
+}
+
+func (ds *ChallengeDataStoreSpy) Challenge(challengeID string) (*api.Challenge, error) {
+	ds.ChallengeChallengeID = challengeID
+	return ds.ChallengeReturn, ds.ChallengeErr
+}
+
+func (ds *ChallengeDataStoreSpy) PendingChallenges(userID string) ([]*api.Challenge, error) {
+	ds.PendingChallengesUserID = userID
+	return ds.PendingChallengesReturn, ds.PendingChallengesErr
+}
+
+func (ds *ChallengeDataStoreSpy) DeleteChallenge(challengeID string) error {
+	ds.DeleteChallengeChallengeID = challengeID
+	return ds.DeleteChallengeErr
+}
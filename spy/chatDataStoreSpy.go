@@ -0,0 +1,49 @@
+package spy
+
+import api "github.com/Morras/neutrinoapi"
+
+type ChatDataStoreSpy struct {
+	AddChatMessageMessage *api.ChatMessage
+	AddChatMessageReturn  string
+	AddChatMessageErr     error
+
+	ChatMessagesGameID                    string
+	ChatMessagesExcludedSenderIDs         []string
+	ChatMessagesOffset, ChatMessagesCount int
+	ChatMessagesReturn                    []*api.ChatMessage
+	ChatMessagesErr                       error
+
+	SetMutedGameID, SetMutedUserID string
+	SetMutedMuted                  bool
+	SetMutedErr                    error
+
+	MutedGameID, MutedUserID string
+	MutedReturn              bool
+	MutedErr                 error
+}
+
+func (ds *ChatDataStoreSpy) AddChatMessage(message *api.ChatMessage) (string, error) {
+	ds.AddChatMessageMessage = message
+	return ds.AddChatMessageReturn, ds.AddChatMessageErr
+}
+
+func (ds *ChatDataStoreSpy) ChatMessages(gameID string, excludedSenderIDs []string, offset int, count int) ([]*api.ChatMessage, error) {
+	ds.ChatMessagesGameID = gameID
+	ds.ChatMessagesExcludedSenderIDs = excludedSenderIDs
+	ds.ChatMessagesOffset = offset
+	ds.ChatMessagesCount = count
+	return ds.ChatMessagesReturn, ds.ChatMessagesErr
+}
+
+func (ds *ChatDataStoreSpy) SetMuted(gameID string, userID string, muted bool) error {
+	ds.SetMutedGameID = gameID
+	ds.SetMutedUserID = userID
+	ds.SetMutedMuted = muted
+	return ds.SetMutedErr
+}
+
+func (ds *ChatDataStoreSpy) Muted(gameID string, userID string) (bool, error) {
+	ds.MutedGameID = gameID
+	ds.MutedUserID = userID
+	return ds.MutedReturn, ds.MutedErr
+}
@@ -1,6 +1,9 @@
 package spy
 
-import api "github.com/Morras/neutrinoapi"
+import (
+	api "github.com/Morras/neutrinoapi"
+	"time"
+)
 
 type GameDataStoreSpy struct {
 	NumberOfActiveGamesUserID string
@@ -11,16 +14,41 @@ type GameDataStoreSpy struct {
 	ActiveGamesReturn []*api.Game
 	ActiveGamesErr    error
 
-	GameWaitingForPlayersCalled bool
-	GameWaitingForPlayersReturn *api.Game
-	GameWaitingForPlayersErr    error
+	GameWaitingForPlayersCalled              bool
+	GameWaitingForPlayersUserID              string
+	GameWaitingForPlayersExcludedOpponentIDs []string
+	GameWaitingForPlayersReturn              *api.Game
+	GameWaitingForPlayersErr                 error
 
-	StartNewGameUserID string
-	StartNewGameReturn string
-	StartNewGameErr    error
+	GamesWaitingForPlayersUserID              string
+	GamesWaitingForPlayersExcludedOpponentIDs []string
+	GamesWaitingForPlayersReturn              []*api.Game
+	GamesWaitingForPlayersErr                 error
+
+	StartNewGameUserID     string
+	StartNewGameVisibility api.Visibility
+	StartNewGameReturn     string
+	StartNewGameErr        error
+	StartNewGameEvents     []*api.GameEvent
 
 	JoinGameUserID, JoinGameGameID string
 	JoinGameErr                    error
+	JoinGameEvents                 []*api.GameEvent
+
+	StartPrivateGameUserID, StartPrivateGameInviteCode string
+	StartPrivateGameVisibility                         api.Visibility
+	StartPrivateGameReturn                             string
+	StartPrivateGameErr                                error
+	StartPrivateGameEvents                             []*api.GameEvent
+
+	GameByInviteCodeInviteCode string
+	GameByInviteCodeReturn     *api.Game
+	GameByInviteCodeErr        error
+
+	CreateGamePlayerOneID, CreateGamePlayerTwoID string
+	CreateGameReturn                             string
+	CreateGameErr                                error
+	CreateGameEvents                             []*api.GameEvent
 
 	GameGameID string
 	GameReturn *api.Game
@@ -30,8 +58,22 @@ type GameDataStoreSpy struct {
 	GamesReturn []*api.Game
 	GamesErr    error
 
-	UpdateGameGame *api.Game
-	UpdateGameErr  error
+	LiveGamesOffset, LiveGamesCount int
+	LiveGamesReturn                 []*api.Game
+	LiveGamesErr                    error
+
+	GamesByStateState                     api.State
+	GamesByStateOffset, GamesByStateCount int
+	GamesByStateReturn                    []*api.Game
+	GamesByStateErr                       error
+
+	UpdateGameGame   *api.Game
+	UpdateGameErr    error
+	UpdateGameEvents []*api.GameEvent
+
+	FinishedGamesSince  time.Time
+	FinishedGamesReturn []*api.Game
+	FinishedGamesErr    error
 }
 
 func (ds *GameDataStoreSpy) ActiveGames(userID string) ([]*api.Game, error) {
@@ -39,27 +81,58 @@ func (ds *GameDataStoreSpy) ActiveGames(userID string) ([]*api.Game, error) {
 	return ds.ActiveGamesReturn, ds.ActiveGamesErr
 }
 
-func (ds *GameDataStoreSpy) GameWaitingForPlayers() (*api.Game, error) {
+func (ds *GameDataStoreSpy) GameWaitingForPlayers(userID string, excludedOpponentIDs []string) (*api.Game, error) {
 	ds.GameWaitingForPlayersCalled = true
+	ds.GameWaitingForPlayersUserID = userID
+	ds.GameWaitingForPlayersExcludedOpponentIDs = excludedOpponentIDs
 	return ds.GameWaitingForPlayersReturn, ds.GameWaitingForPlayersErr
 }
 
+func (ds *GameDataStoreSpy) GamesWaitingForPlayers(userID string, excludedOpponentIDs []string) ([]*api.Game, error) {
+	ds.GamesWaitingForPlayersUserID = userID
+	ds.GamesWaitingForPlayersExcludedOpponentIDs = excludedOpponentIDs
+	return ds.GamesWaitingForPlayersReturn, ds.GamesWaitingForPlayersErr
+}
+
 func (ds *GameDataStoreSpy) NumberOfActiveGames(userID string) (int, error) {
 	ds.NumberOfActiveGamesUserID = userID
 	return ds.NumberOfActiveGamesReturn, ds.NumberOfActiveGamesErr
 }
 
-func (ds *GameDataStoreSpy) StartNewGame(userID string) (string, error) {
+func (ds *GameDataStoreSpy) StartNewGame(userID string, visibility api.Visibility, events ...*api.GameEvent) (string, error) {
 	ds.StartNewGameUserID = userID
+	ds.StartNewGameVisibility = visibility
+	ds.StartNewGameEvents = append(ds.StartNewGameEvents, events...)
 	return ds.StartNewGameReturn, ds.StartNewGameErr
 }
 
-func (ds *GameDataStoreSpy) JoinGame(userID string, gameID string) error {
+func (ds *GameDataStoreSpy) JoinGame(userID string, gameID string, events ...*api.GameEvent) error {
 	ds.JoinGameUserID = userID
 	ds.JoinGameGameID = gameID
+	ds.JoinGameEvents = append(ds.JoinGameEvents, events...)
 	return ds.JoinGameErr
 }
 
+func (ds *GameDataStoreSpy) StartPrivateGame(userID string, inviteCode string, visibility api.Visibility, events ...*api.GameEvent) (string, error) {
+	ds.StartPrivateGameUserID = userID
+	ds.StartPrivateGameInviteCode = inviteCode
+	ds.StartPrivateGameVisibility = visibility
+	ds.StartPrivateGameEvents = append(ds.StartPrivateGameEvents, events...)
+	return ds.StartPrivateGameReturn, ds.StartPrivateGameErr
+}
+
+func (ds *GameDataStoreSpy) GameByInviteCode(inviteCode string) (*api.Game, error) {
+	ds.GameByInviteCodeInviteCode = inviteCode
+	return ds.GameByInviteCodeReturn, ds.GameByInviteCodeErr
+}
+
+func (ds *GameDataStoreSpy) CreateGame(playerOneID string, playerTwoID string, events ...*api.GameEvent) (string, error) {
+	ds.CreateGamePlayerOneID = playerOneID
+	ds.CreateGamePlayerTwoID = playerTwoID
+	ds.CreateGameEvents = append(ds.CreateGameEvents, events...)
+	return ds.CreateGameReturn, ds.CreateGameErr
+}
+
 func (ds *GameDataStoreSpy) Game(gameID string) (*api.Game, error) {
 	ds.GameGameID = gameID
 	return ds.GameReturn, ds.GameErr
@@ -70,7 +143,26 @@ func (ds *GameDataStoreSpy) Games(userID string) ([]*api.Game, error) {
 	return ds.GamesReturn, ds.GamesErr
 }
 
-func (ds *GameDataStoreSpy) UpdateGame(game *api.Game) error {
+func (ds *GameDataStoreSpy) LiveGames(offset int, count int) ([]*api.Game, error) {
+	ds.LiveGamesOffset = offset
+	ds.LiveGamesCount = count
+	return ds.LiveGamesReturn, ds.LiveGamesErr
+}
+
+func (ds *GameDataStoreSpy) GamesByState(state api.State, offset int, count int) ([]*api.Game, error) {
+	ds.GamesByStateState = state
+	ds.GamesByStateOffset = offset
+	ds.GamesByStateCount = count
+	return ds.GamesByStateReturn, ds.GamesByStateErr
+}
+
+func (ds *GameDataStoreSpy) UpdateGame(game *api.Game, events ...*api.GameEvent) error {
 	ds.UpdateGameGame = game
+	ds.UpdateGameEvents = append(ds.UpdateGameEvents, events...)
 	return ds.UpdateGameErr
 }
+
+func (ds *GameDataStoreSpy) FinishedGames(since time.Time) ([]*api.Game, error) {
+	ds.FinishedGamesSince = since
+	return ds.FinishedGamesReturn, ds.FinishedGamesErr
+}
@@ -0,0 +1,13 @@
+package spy
+
+import api "github.com/Morras/neutrinoapi"
+
+type GameEventPublisherSpy struct {
+	PublishEvents []*api.GameEvent
+	PublishErr    error
+}
+
+func (spy *GameEventPublisherSpy) Publish(event *api.GameEvent) error {
+	spy.PublishEvents = append(spy.PublishEvents, event)
+	return spy.PublishErr
+}
@@ -0,0 +1,32 @@
+package spy
+
+import api "github.com/Morras/neutrinoapi"
+
+type LeaderboardDataStoreSpy struct {
+	LeaderboardEntriesOffset, LeaderboardEntriesCount int
+	LeaderboardEntriesReturn                          []*api.LeaderboardEntry
+	LeaderboardEntriesErr                             error
+
+	LeaderboardRankUserID string
+	LeaderboardRankReturn int
+	LeaderboardRankErr    error
+
+	UpdateLeaderboardEntries []*api.LeaderboardEntry
+	UpdateLeaderboardErr     error
+}
+
+func (ds *LeaderboardDataStoreSpy) LeaderboardEntries(offset int, count int) ([]*api.LeaderboardEntry, error) {
+	ds.LeaderboardEntriesOffset = offset
+	ds.LeaderboardEntriesCount = count
+	return ds.LeaderboardEntriesReturn, ds.LeaderboardEntriesErr
+}
+
+func (ds *LeaderboardDataStoreSpy) LeaderboardRank(userID string) (int, error) {
+	ds.LeaderboardRankUserID = userID
+	return ds.LeaderboardRankReturn, ds.LeaderboardRankErr
+}
+
+func (ds *LeaderboardDataStoreSpy) UpdateLeaderboard(entries []*api.LeaderboardEntry) error {
+	ds.UpdateLeaderboardEntries = entries
+	return ds.UpdateLeaderboardErr
+}
@@ -0,0 +1,12 @@
+package spy
+
+type MessageFilterSpy struct {
+	FilterText   string
+	FilterReturn string
+	FilterErr    error
+}
+
+func (spy *MessageFilterSpy) Filter(text string) (string, error) {
+	spy.FilterText = text
+	return spy.FilterReturn, spy.FilterErr
+}
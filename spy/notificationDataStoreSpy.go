@@ -0,0 +1,50 @@
+package spy
+
+import api "github.com/Morras/neutrinoapi"
+
+type NotificationDataStoreSpy struct {
+	AddDeviceTokenDevice *api.DeviceToken
+	AddDeviceTokenErr    error
+
+	RemoveDeviceTokenUserID, RemoveDeviceTokenToken string
+	RemoveDeviceTokenErr                            error
+
+	DeviceTokensUserIDs []string
+	DeviceTokensReturn  []*api.DeviceToken
+	DeviceTokensErr     error
+
+	NotificationPreferencesUserID string
+	NotificationPreferencesReturn *api.NotificationPreferences
+	NotificationPreferencesErr    error
+
+	SetNotificationPreferencesUserID      string
+	SetNotificationPreferencesPreferences *api.NotificationPreferences
+	SetNotificationPreferencesErr         error
+}
+
+func (ds *NotificationDataStoreSpy) AddDeviceToken(device *api.DeviceToken) error {
+	ds.AddDeviceTokenDevice = device
+	return ds.AddDeviceTokenErr
+}
+
+func (ds *NotificationDataStoreSpy) RemoveDeviceToken(userID string, token string) error {
+	ds.RemoveDeviceTokenUserID = userID
+	ds.RemoveDeviceTokenToken = token
+	return ds.RemoveDeviceTokenErr
+}
+
+func (ds *NotificationDataStoreSpy) DeviceTokens(userID string) ([]*api.DeviceToken, error) {
+	ds.DeviceTokensUserIDs = append(ds.DeviceTokensUserIDs, userID)
+	return ds.DeviceTokensReturn, ds.DeviceTokensErr
+}
+
+func (ds *NotificationDataStoreSpy) NotificationPreferences(userID string) (*api.NotificationPreferences, error) {
+	ds.NotificationPreferencesUserID = userID
+	return ds.NotificationPreferencesReturn, ds.NotificationPreferencesErr
+}
+
+func (ds *NotificationDataStoreSpy) SetNotificationPreferences(userID string, preferences *api.NotificationPreferences) error {
+	ds.SetNotificationPreferencesUserID = userID
+	ds.SetNotificationPreferencesPreferences = preferences
+	return ds.SetNotificationPreferencesErr
+}
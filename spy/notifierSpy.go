@@ -0,0 +1,15 @@
+package spy
+
+import api "github.com/Morras/neutrinoapi"
+
+type NotifierSpy struct {
+	NotifyDevices       []*api.DeviceToken
+	NotifyNotifications []*api.Notification
+	NotifyErr           error
+}
+
+func (spy *NotifierSpy) Notify(device *api.DeviceToken, notification *api.Notification) error {
+	spy.NotifyDevices = append(spy.NotifyDevices, device)
+	spy.NotifyNotifications = append(spy.NotifyNotifications, notification)
+	return spy.NotifyErr
+}
@@ -0,0 +1,40 @@
+package spy
+
+import api "github.com/Morras/neutrinoapi"
+
+type OutboxDataStoreSpy struct {
+	AddToOutboxEvent *api.GameEvent
+	AddToOutboxErr   error
+
+	OutboxEntriesCount  int
+	OutboxEntriesReturn []*api.OutboxEntry
+	OutboxEntriesErr    error
+
+	UpdateOutboxEntryEntryIDs []string
+	UpdateOutboxEntryEvents   []*api.GameEvent
+	UpdateOutboxEntryErr      error
+
+	RemoveFromOutboxEntryIDs []string
+	RemoveFromOutboxErr      error
+}
+
+func (ds *OutboxDataStoreSpy) AddToOutbox(event *api.GameEvent) error {
+	ds.AddToOutboxEvent = event
+	return ds.AddToOutboxErr
+}
+
+func (ds *OutboxDataStoreSpy) OutboxEntries(count int) ([]*api.OutboxEntry, error) {
+	ds.OutboxEntriesCount = count
+	return ds.OutboxEntriesReturn, ds.OutboxEntriesErr
+}
+
+func (ds *OutboxDataStoreSpy) UpdateOutboxEntry(entryID string, event *api.GameEvent) error {
+	ds.UpdateOutboxEntryEntryIDs = append(ds.UpdateOutboxEntryEntryIDs, entryID)
+	ds.UpdateOutboxEntryEvents = append(ds.UpdateOutboxEntryEvents, event)
+	return ds.UpdateOutboxEntryErr
+}
+
+func (ds *OutboxDataStoreSpy) RemoveFromOutbox(entryID string) error {
+	ds.RemoveFromOutboxEntryIDs = append(ds.RemoveFromOutboxEntryIDs, entryID)
+	return ds.RemoveFromOutboxErr
+}
@@ -0,0 +1,61 @@
+package spy
+
+import api "github.com/Morras/neutrinoapi"
+
+type PuzzleDataStoreSpy struct {
+	SavePuzzlePuzzles []*api.Puzzle
+	SavePuzzleErr     error
+
+	PuzzlePuzzleID string
+	PuzzleReturn   *api.Puzzle
+	PuzzleErr      error
+
+	NextPuzzleUserID string
+	NextPuzzleRating int
+	NextPuzzleReturn *api.Puzzle
+	NextPuzzleErr    error
+
+	PuzzleRatingUserID string
+	PuzzleRatingReturn *api.Rating
+	PuzzleRatingErr    error
+
+	AttemptUserID   string
+	AttemptPuzzleID string
+	AttemptReturn   *api.PuzzleAttempt
+	AttemptErr      error
+
+	RecordAttemptAttempt *api.PuzzleAttempt
+	RecordAttemptErr     error
+}
+
+func (ds *PuzzleDataStoreSpy) SavePuzzle(puzzle *api.Puzzle) error {
+	ds.SavePuzzlePuzzles = append(ds.SavePuzzlePuzzles, puzzle)
+	return ds.SavePuzzleErr
+}
+
+func (ds *PuzzleDataStoreSpy) Puzzle(puzzleID string) (*api.Puzzle, error) {
+	ds.PuzzlePuzzleID = puzzleID
+	return ds.PuzzleReturn, ds.PuzzleErr
+}
+
+func (ds *PuzzleDataStoreSpy) NextPuzzle(userID string, rating int) (*api.Puzzle, error) {
+	ds.NextPuzzleUserID = userID
+	ds.NextPuzzleRating = rating
+	return ds.NextPuzzleReturn, ds.NextPuzzleErr
+}
+
+func (ds *PuzzleDataStoreSpy) PuzzleRating(userID string) (*api.Rating, error) {
+	ds.PuzzleRatingUserID = userID
+	return ds.PuzzleRatingReturn, ds.PuzzleRatingErr
+}
+
+func (ds *PuzzleDataStoreSpy) Attempt(userID string, puzzleID string) (*api.PuzzleAttempt, error) {
+	ds.AttemptUserID = userID
+	ds.AttemptPuzzleID = puzzleID
+	return ds.AttemptReturn, ds.AttemptErr
+}
+
+func (ds *PuzzleDataStoreSpy) RecordAttempt(attempt *api.PuzzleAttempt) error {
+	ds.RecordAttemptAttempt = attempt
+	return ds.RecordAttemptErr
+}
@@ -0,0 +1,31 @@
+package spy
+
+import api "github.com/Morras/neutrinoapi"
+
+type RatingDataStoreSpy struct {
+	RatingUserIDs []string
+	RatingReturn  map[string]*api.Rating
+	RatingErr     error
+
+	UpdateRatingsChanges []*api.RatingChange
+	UpdateRatingsErr     error
+
+	RatingHistoryUserID string
+	RatingHistoryReturn []*api.RatingChange
+	RatingHistoryErr    error
+}
+
+func (ds *RatingDataStoreSpy) Rating(userID string) (*api.Rating, error) {
+	ds.RatingUserIDs = append(ds.RatingUserIDs, userID)
+	return ds.RatingReturn[userID], ds.RatingErr
+}
+
+func (ds *RatingDataStoreSpy) UpdateRatings(changes []*api.RatingChange) error {
+	ds.UpdateRatingsChanges = changes
+	return ds.UpdateRatingsErr
+}
+
+func (ds *RatingDataStoreSpy) RatingHistory(userID string) ([]*api.RatingChange, error) {
+	ds.RatingHistoryUserID = userID
+	return ds.RatingHistoryReturn, ds.RatingHistoryErr
+}
@@ -0,0 +1,58 @@
+package spy
+
+import (
+	api "github.com/Morras/neutrinoapi"
+	"time"
+)
+
+type WebhookDataStoreSpy struct {
+	WebhookSubscriptionsReturn []*api.WebhookSubscription
+	WebhookSubscriptionsErr    error
+
+	AddDeadLetterLetter *api.WebhookDeadLetter
+	AddDeadLetterErr    error
+
+	AddWebhookDeliveryDeliveries []*api.WebhookDelivery
+	AddWebhookDeliveryErr        error
+
+	DueWebhookDeliveriesNow    time.Time
+	DueWebhookDeliveriesCount  int
+	DueWebhookDeliveriesReturn []*api.WebhookDelivery
+	DueWebhookDeliveriesErr    error
+
+	UpdateWebhookDeliveryDeliveries []*api.WebhookDelivery
+	UpdateWebhookDeliveryErr        error
+
+	RemoveWebhookDeliveryDeliveryIDs []string
+	RemoveWebhookDeliveryErr         error
+}
+
+func (ds *WebhookDataStoreSpy) WebhookSubscriptions() ([]*api.WebhookSubscription, error) {
+	return ds.WebhookSubscriptionsReturn, ds.WebhookSubscriptionsErr
+}
+
+func (ds *WebhookDataStoreSpy) AddDeadLetter(letter *api.WebhookDeadLetter) error {
+	ds.AddDeadLetterLetter = letter
+	return ds.AddDeadLetterErr
+}
+
+func (ds *WebhookDataStoreSpy) AddWebhookDelivery(delivery *api.WebhookDelivery) error {
+	ds.AddWebhookDeliveryDeliveries = append(ds.AddWebhookDeliveryDeliveries, delivery)
+	return ds.AddWebhookDeliveryErr
+}
+
+func (ds *WebhookDataStoreSpy) DueWebhookDeliveries(now time.Time, count int) ([]*api.WebhookDelivery, error) {
+	ds.DueWebhookDeliveriesNow = now
+	ds.DueWebhookDeliveriesCount = count
+	return ds.DueWebhookDeliveriesReturn, ds.DueWebhookDeliveriesErr
+}
+
+func (ds *WebhookDataStoreSpy) UpdateWebhookDelivery(delivery *api.WebhookDelivery) error {
+	ds.UpdateWebhookDeliveryDeliveries = append(ds.UpdateWebhookDeliveryDeliveries, delivery)
+	return ds.UpdateWebhookDeliveryErr
+}
+
+func (ds *WebhookDataStoreSpy) RemoveWebhookDelivery(deliveryID string) error {
+	ds.RemoveWebhookDeliveryDeliveryIDs = append(ds.RemoveWebhookDeliveryDeliveryIDs, deliveryID)
+	return ds.RemoveWebhookDeliveryErr
+}
@@ -0,0 +1,90 @@
+package neutrinoapi
+
+import "sort"
+
+// ResultCounts splits wins or losses up by how the game was decided.
+type ResultCounts struct {
+	BackLine, Trap, Default int
+}
+
+func (rc *ResultCounts) add(winningCondition WinningCondition) {
+	switch winningCondition {
+	case BACK_LINE:
+		rc.BackLine++
+	case TRAP:
+		rc.Trap++
+	case DEFAULT:
+		rc.Default++
+	}
+}
+
+type Stats struct {
+	GamesPlayed                            int
+	Wins, Losses                           ResultCounts
+	Draws                                  int
+	WinRateAsPlayerOne, WinRateAsPlayerTwo float64
+	AverageGameLength                      float64 // In turns
+	CurrentStreak                          int     // Positive for a winning streak and negative for a losing streak
+}
+
+// computeStats only looks at finished games, games still being played do not count towards anything.
+func computeStats(userID string, games []*Game) *Stats {
+	finished := []*Game{}
+	for _, game := range games {
+		if game.State == DONE {
+			finished = append(finished, game)
+		}
+	}
+	sort.SliceStable(finished, func(i, j int) bool {
+		return finished[i].FinishedAt.Before(finished[j].FinishedAt)
+	})
+
+	stats := &Stats{GamesPlayed: len(finished)}
+	var gamesAsPlayerOne, winsAsPlayerOne, gamesAsPlayerTwo, winsAsPlayerTwo, turns int
+	for _, game := range finished {
+		turns += game.Turns
+		won := game.WinnerID == userID
+
+		switch {
+		case game.WinnerID == "":
+			stats.Draws++
+			stats.CurrentStreak = 0
+		case won:
+			stats.Wins.add(game.WinningCondition)
+			if stats.CurrentStreak < 0 {
+				stats.CurrentStreak = 0
+			}
+			stats.CurrentStreak++
+		default:
+			stats.Losses.add(game.WinningCondition)
+			if stats.CurrentStreak > 0 {
+				stats.CurrentStreak = 0
+			}
+			stats.CurrentStreak--
+		}
+
+		if game.PlayerOneID == userID {
+			gamesAsPlayerOne++
+			if won {
+				winsAsPlayerOne++
+			}
+		} else {
+			gamesAsPlayerTwo++
+			if won {
+				winsAsPlayerTwo++
+			}
+		}
+	}
+
+	stats.WinRateAsPlayerOne = rate(winsAsPlayerOne, gamesAsPlayerOne)
+	stats.WinRateAsPlayerTwo = rate(winsAsPlayerTwo, gamesAsPlayerTwo)
+	stats.AverageGameLength = rate(turns, len(finished))
+	return stats
+}
+
+func rate(count int, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(count) / float64(total)
+}
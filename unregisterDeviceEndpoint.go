@@ -0,0 +1,23 @@
+package neutrinoapi
+
+import "net/http"
+
+type UnregisterDeviceEndpoint struct {
+	nds NotificationDataStore
+}
+
+func NewUnregisterDeviceEndpoint(nds NotificationDataStore) *UnregisterDeviceEndpoint {
+	return &UnregisterDeviceEndpoint{nds: nds}
+}
+
+// PerformAction stops notifications to a device, for instance when the player signs out on it.
+func (ude *UnregisterDeviceEndpoint) PerformAction(userID string, token string) int {
+	if token == "" {
+		return http.StatusBadRequest
+	}
+
+	if err := ude.nds.RemoveDeviceToken(userID, token); err != nil {
+		return http.StatusInternalServerError
+	}
+	return http.StatusOK
+}
@@ -0,0 +1,43 @@
+package neutrinoapi_test
+
+import (
+	"errors"
+	api "github.com/Morras/neutrinoapi"
+	"github.com/Morras/neutrinoapi/spy"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+)
+
+var _ = Describe("unregisterDeviceEndpoint", func() {
+
+	testUserID := "TestUserId"
+
+	var dataStoreSpy *spy.NotificationDataStoreSpy
+	var endpoint *api.UnregisterDeviceEndpoint
+
+	BeforeEach(func() {
+		dataStoreSpy = &spy.NotificationDataStoreSpy{}
+		endpoint = api.NewUnregisterDeviceEndpoint(dataStoreSpy)
+	})
+
+	Context("performAction method", func() {
+		It("Should remove the device from the users devices", func() {
+			code := endpoint.PerformAction(testUserID, "device token")
+			Expect(code).To(BeIdenticalTo(http.StatusOK))
+			Expect(dataStoreSpy.RemoveDeviceTokenUserID).To(BeIdenticalTo(testUserID))
+			Expect(dataStoreSpy.RemoveDeviceTokenToken).To(BeIdenticalTo("device token"))
+		})
+
+		It("Should reject an empty token", func() {
+			code := endpoint.PerformAction(testUserID, "")
+			Expect(code).To(BeIdenticalTo(http.StatusBadRequest))
+		})
+
+		It("Should return an internal server error if the device could not be removed", func() {
+			dataStoreSpy.RemoveDeviceTokenErr = errors.New("error removing device")
+			code := endpoint.PerformAction(testUserID, "device token")
+			Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
+		})
+	})
+})
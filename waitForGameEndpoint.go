@@ -0,0 +1,59 @@
+package neutrinoapi
+
+import (
+	"net/http"
+	"time"
+)
+
+// WaitForGameEndpoint is for clients that cannot keep a connection open for events, like the ones
+// going through API Gateway. It holds the request until the game changes instead.
+type WaitForGameEndpoint struct {
+	ds           GameDataStore
+	ge           *GetGameEndpoint
+	pollInterval time.Duration
+	timeout      time.Duration
+}
+
+func NewWaitForGameEndpoint(ds GameDataStore, ge *GetGameEndpoint, pollInterval time.Duration, timeout time.Duration) *WaitForGameEndpoint {
+	return &WaitForGameEndpoint{ds: ds, ge: ge, pollInterval: pollInterval, timeout: timeout}
+}
+
+// PerformAction returns the game as soon as its version differs from knownVersion. If nothing has
+// changed before the timeout, the game is returned as it is and the client can simply ask again.
+func (we *WaitForGameEndpoint) PerformAction(userID string, gameID string, knownVersion int) (*Game, int) {
+	if gameID == "" {
+		return nil, http.StatusBadRequest
+	}
+
+	// Going through the game endpoint checks the player is allowed to see the game
+	games, statusCode := we.ge.PerformAction(userID, gameID, false)
+	if statusCode != http.StatusOK {
+		return nil, statusCode
+	}
+	if games[0].Version != knownVersion {
+		return games[0], http.StatusOK
+	}
+
+	deadline := time.Now().Add(we.timeout)
+	for time.Now().Before(deadline) {
+		time.Sleep(we.pollInterval)
+
+		// Only the game itself is polled, the ratings are looked up once it has changed
+		dsGame, err := we.ds.Game(gameID)
+		if err != nil {
+			return nil, http.StatusInternalServerError
+		}
+		if dsGame == nil {
+			return nil, http.StatusNotFound
+		}
+		if dsGame.Version != knownVersion {
+			games, statusCode = we.ge.PerformAction(userID, gameID, false)
+			if statusCode != http.StatusOK {
+				return nil, statusCode
+			}
+			return games[0], http.StatusOK
+		}
+	}
+
+	return games[0], http.StatusOK
+}
@@ -0,0 +1,90 @@
+package neutrinoapi_test
+
+import (
+	"errors"
+	api "github.com/Morras/neutrinoapi"
+	"github.com/Morras/neutrinoapi/spy"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"net/http"
+	"time"
+)
+
+// Returns the games one after the other, so the game can change while the endpoint is waiting
+type changingGameDataStore struct {
+	spy.GameDataStoreSpy
+	games []*api.Game
+	calls int
+}
+
+func (ds *changingGameDataStore) Game(gameID string) (*api.Game, error) {
+	game := ds.games[ds.calls]
+	if ds.calls < len(ds.games)-1 {
+		ds.calls++
+	}
+	return game, ds.GameErr
+}
+
+var _ = Describe("waitForGameEndpoint", func() {
+
+	testUserID := "TestUserId"
+
+	var dataStore *changingGameDataStore
+	var endpoint *api.WaitForGameEndpoint
+
+	BeforeEach(func() {
+		dataStore = &changingGameDataStore{}
+		getGameEndpoint := api.NewGetGameEndpoint(dataStore, &spy.RatingDataStoreSpy{})
+		endpoint = api.NewWaitForGameEndpoint(dataStore, getGameEndpoint, time.Millisecond, 20*time.Millisecond)
+	})
+
+	version := func(v int) *api.Game {
+		return &api.Game{GameID: "game id", PlayerOneID: testUserID, PlayerTwoID: "opponent", Version: v}
+	}
+
+	Context("performAction method", func() {
+
+		It("Should return right away if the client is behind", func() {
+			dataStore.games = []*api.Game{version(5)}
+			game, code := endpoint.PerformAction(testUserID, "game id", 4)
+			Expect(code).To(BeIdenticalTo(http.StatusOK))
+			Expect(game.Version).To(BeIdenticalTo(5))
+			Expect(dataStore.calls).To(BeIdenticalTo(0))
+		})
+
+		It("Should return the game once it changes", func() {
+			dataStore.games = []*api.Game{version(4), version(4), version(4), version(5)}
+			game, code := endpoint.PerformAction(testUserID, "game id", 4)
+			Expect(code).To(BeIdenticalTo(http.StatusOK))
+			Expect(game.Version).To(BeIdenticalTo(5))
+			Expect(game.PlayerOneRating).To(BeIdenticalTo(api.INITIAL_RATING))
+		})
+
+		It("Should return the unchanged game when it times out", func() {
+			dataStore.games = []*api.Game{version(4)}
+			start := time.Now()
+			game, code := endpoint.PerformAction(testUserID, "game id", 4)
+			Expect(code).To(BeIdenticalTo(http.StatusOK))
+			Expect(game.Version).To(BeIdenticalTo(4))
+			Expect(time.Since(start)).To(BeNumerically(">=", 20*time.Millisecond))
+		})
+
+		It("Should return forbidden to users who cannot see the game", func() {
+			dataStore.games = []*api.Game{version(4)}
+			_, code := endpoint.PerformAction("someoneElse", "game id", 4)
+			Expect(code).To(BeIdenticalTo(http.StatusForbidden))
+		})
+
+		It("Should return bad request without a game", func() {
+			_, code := endpoint.PerformAction(testUserID, "", 4)
+			Expect(code).To(BeIdenticalTo(http.StatusBadRequest))
+		})
+
+		It("Should return an internal server error if the game cannot be looked up", func() {
+			dataStore.games = []*api.Game{version(4)}
+			dataStore.GameErr = errors.New("Error getting game")
+			_, code := endpoint.PerformAction(testUserID, "game id", 4)
+			Expect(code).To(BeIdenticalTo(http.StatusInternalServerError))
+		})
+	})
+})
@@ -0,0 +1,263 @@
+package neutrinoapi
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// WebhookSubscription sends the events of the listed types to URL, or every event if there are none listed.
+type WebhookSubscription struct {
+	SubscriptionID string
+	URL            string
+	Secret         string // Signs the payloads so the receiver can tell they came from us
+	EventTypes     []GameEventType
+}
+
+func (ws *WebhookSubscription) wants(eventType GameEventType) bool {
+	if len(ws.EventTypes) == 0 {
+		return true
+	}
+	for _, wanted := range ws.EventTypes {
+		if wanted == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDeadLetter records a payload that could not be delivered, so it can be looked into or sent by hand.
+type WebhookDeadLetter struct {
+	SubscriptionID string
+	URL            string
+	Payload        []byte
+	Attempts       int
+	LastError      string
+	FailedAt       time.Time
+}
+
+// WebhookDelivery is a payload waiting to be posted to a subscription, or to be tried again.
+type WebhookDelivery struct {
+	DeliveryID     string
+	SubscriptionID string
+	URL            string
+	Secret         string
+	Event          string
+	Payload        []byte
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastError      string
+}
+
+type WebhookDataStore interface {
+	WebhookSubscriptions() ([]*WebhookSubscription, error)
+	AddDeadLetter(letter *WebhookDeadLetter) error
+
+	AddWebhookDelivery(delivery *WebhookDelivery) error
+	// DueWebhookDeliveries returns up to count deliveries whose next attempt is at or before now, oldest first.
+	DueWebhookDeliveries(now time.Time, count int) ([]*WebhookDelivery, error)
+	UpdateWebhookDelivery(delivery *WebhookDelivery) error
+	RemoveWebhookDelivery(deliveryID string) error
+}
+
+// WebhookGame is the part of a game receivers get to see. Webhooks go to third parties, so invite
+// codes, which let anyone take the open seat, and the history of unfinished games are left out.
+// Games that are not PUBLIC only tell who played and the position to their players, so receivers
+// just get to know that something happened in them.
+type WebhookGame struct {
+	GameID, PlayerOneID, PlayerTwoID string
+	State                            State
+	WinningCondition                 WinningCondition
+	SerializedGame                   uint64
+	WinnerID                         string
+	CreatedAt, FinishedAt            time.Time
+	Turns                            int
+	BotPlayed                        bool
+	Visibility                       Visibility
+	Version                          int
+}
+
+func newWebhookGame(game *Game) *WebhookGame {
+	if game.Visibility != PUBLIC {
+		return &WebhookGame{
+			GameID:           game.GameID,
+			State:            game.State,
+			WinningCondition: game.WinningCondition,
+			CreatedAt:        game.CreatedAt,
+			FinishedAt:       game.FinishedAt,
+			Turns:            game.Turns,
+			BotPlayed:        game.BotPlayed,
+			Visibility:       game.Visibility,
+			Version:          game.Version,
+		}
+	}
+	return &WebhookGame{
+		GameID:           game.GameID,
+		PlayerOneID:      game.PlayerOneID,
+		PlayerTwoID:      game.PlayerTwoID,
+		State:            game.State,
+		WinningCondition: game.WinningCondition,
+		SerializedGame:   game.SerializedGame,
+		WinnerID:         game.WinnerID,
+		CreatedAt:        game.CreatedAt,
+		FinishedAt:       game.FinishedAt,
+		Turns:            game.Turns,
+		BotPlayed:        game.BotPlayed,
+		Visibility:       game.Visibility,
+		Version:          game.Version,
+	}
+}
+
+type WebhookPayload struct {
+	Event    string
+	GameID   string
+	UserID   string
+	Occurred time.Time
+	Game     *WebhookGame
+}
+
+// SignWebhookPayload returns the value of the signature header for body. Receivers compute it
+// themselves with the shared secret and compare.
+func SignWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookDispatcher posts game events to every subscription that wants them. Handling an event only
+// queues the deliveries, as the event is published inside the request that caused it, and a receiver
+// that is down would hold the request up through every retry. DeliverPending does the posting.
+type WebhookDispatcher struct {
+	ds             WebhookDataStore
+	client         *http.Client
+	maxAttempts    int
+	initialBackoff time.Duration
+}
+
+func NewWebhookDispatcher(ds WebhookDataStore, client *http.Client, maxAttempts int, initialBackoff time.Duration) *WebhookDispatcher {
+	return &WebhookDispatcher{ds: ds, client: client, maxAttempts: maxAttempts, initialBackoff: initialBackoff}
+}
+
+// HandleGameEvent queues the event for every subscription that wants it, and only fails if the
+// subscriptions could not be read or a delivery could not be queued. The other subscriptions still
+// get their deliveries when one of them fails.
+func (wd *WebhookDispatcher) HandleGameEvent(event *GameEvent) error {
+	subscriptions, err := wd.ds.WebhookSubscriptions()
+	if err != nil {
+		return err
+	}
+
+	payload := &WebhookPayload{
+		Event:    gameEventTypeNames[event.Type],
+		GameID:   event.Game.GameID,
+		Occurred: event.Occurred,
+		Game:     newWebhookGame(event.Game),
+	}
+	if event.Game.Visibility == PUBLIC {
+		payload.UserID = event.UserID
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	var failed []string
+	for _, subscription := range subscriptions {
+		if !subscription.wants(event.Type) {
+			continue
+		}
+		delivery := &WebhookDelivery{
+			SubscriptionID: subscription.SubscriptionID,
+			URL:            subscription.URL,
+			Secret:         subscription.Secret,
+			Event:          gameEventTypeNames[event.Type],
+			Payload:        body,
+			NextAttemptAt:  event.Occurred,
+		}
+		if err = wd.ds.AddWebhookDelivery(delivery); err != nil {
+			failed = append(failed, fmt.Sprintf("%v: %v", subscription.SubscriptionID, err))
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("Error queueing webhooks for %v", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// DeliverPending posts the deliveries that are due, and returns how many were accepted. Failed
+// deliveries are tried again later, waiting twice as long every time, until they have used up their
+// attempts and become dead letters. It is meant to run every WEBHOOK_DELIVERY_INTERVAL or so.
+func (wd *WebhookDispatcher) DeliverPending() (int, error) {
+	now := time.Now()
+	deliveries, err := wd.ds.DueWebhookDeliveries(now, WEBHOOK_DELIVERY_BATCH_SIZE)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for _, delivery := range deliveries {
+		accepted, err := wd.deliver(delivery, now)
+		if err != nil {
+			// Left in the queue as it was, so it is tried again on the next run
+			fmt.Printf("Error updating webhook delivery %v: %v\n", delivery.DeliveryID, err)
+		}
+		if accepted {
+			delivered++
+		}
+	}
+	return delivered, nil
+}
+
+// deliver returns whether the receiver accepted the delivery, and fails if the queue could not be updated.
+func (wd *WebhookDispatcher) deliver(delivery *WebhookDelivery, now time.Time) (bool, error) {
+	delivery.Attempts++
+	err := wd.post(delivery)
+	if err == nil {
+		return true, wd.ds.RemoveWebhookDelivery(delivery.DeliveryID)
+	}
+	delivery.LastError = err.Error()
+
+	if delivery.Attempts < wd.maxAttempts {
+		delivery.NextAttemptAt = now.Add(wd.initialBackoff << uint(delivery.Attempts-1))
+		return false, wd.ds.UpdateWebhookDelivery(delivery)
+	}
+
+	fmt.Printf("Error delivering webhook to %v after %v attempts: %v\n", delivery.URL, delivery.Attempts, err)
+	if err = wd.ds.AddDeadLetter(&WebhookDeadLetter{
+		SubscriptionID: delivery.SubscriptionID,
+		URL:            delivery.URL,
+		Payload:        delivery.Payload,
+		Attempts:       delivery.Attempts,
+		LastError:      delivery.LastError,
+		FailedAt:       now,
+	}); err != nil {
+		return false, err
+	}
+	return false, wd.ds.RemoveWebhookDelivery(delivery.DeliveryID)
+}
+
+func (wd *WebhookDispatcher) post(delivery *WebhookDelivery) error {
+	req, err := http.NewRequest(http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WEBHOOK_EVENT_HEADER_KEY, delivery.Event)
+	req.Header.Set(WEBHOOK_SIGNATURE_HEADER_KEY, SignWebhookPayload(delivery.Secret, delivery.Payload))
+
+	resp, err := wd.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("Webhook receiver responded with status %v", resp.StatusCode)
+	}
+	return nil
+}
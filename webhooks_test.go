@@ -0,0 +1,229 @@
+package neutrinoapi_test
+
+import (
+	"encoding/json"
+	"errors"
+	api "github.com/Morras/neutrinoapi"
+	"github.com/Morras/neutrinoapi/spy"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+)
+
+// webhookReceiver stands in for the Discord bot or analytics service on the other end of a webhook.
+type webhookReceiver struct {
+	mutex      sync.Mutex
+	failFirst  int
+	requests   []*http.Request
+	bodies     [][]byte
+	server     *httptest.Server
+	statusCode int
+}
+
+func newWebhookReceiver() *webhookReceiver {
+	receiver := &webhookReceiver{statusCode: http.StatusOK}
+	receiver.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		receiver.mutex.Lock()
+		defer receiver.mutex.Unlock()
+		receiver.requests = append(receiver.requests, r)
+		receiver.bodies = append(receiver.bodies, body)
+		if len(receiver.requests) <= receiver.failFirst {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(receiver.statusCode)
+	}))
+	return receiver
+}
+
+func (receiver *webhookReceiver) received() int {
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+	return len(receiver.requests)
+}
+
+var _ = Describe("webhookDispatcher", func() {
+
+	const secret = "shared secret"
+	const backoff = time.Minute
+
+	var dataStoreSpy *spy.WebhookDataStoreSpy
+	var receiver *webhookReceiver
+	var dispatcher *api.WebhookDispatcher
+	var event *api.GameEvent
+
+	BeforeEach(func() {
+		receiver = newWebhookReceiver()
+		dataStoreSpy = &spy.WebhookDataStoreSpy{}
+		dataStoreSpy.WebhookSubscriptionsReturn = []*api.WebhookSubscription{{SubscriptionID: "subscription id", URL: receiver.server.URL, Secret: secret}}
+		dispatcher = api.NewWebhookDispatcher(dataStoreSpy, receiver.server.Client(), 3, backoff)
+		event = &api.GameEvent{Type: api.MOVE_MADE, UserID: "TestUserId", Occurred: time.Now(),
+			Game: &api.Game{GameID: "game id", PlayerOneID: "player one", PlayerTwoID: "player two", Turns: 3,
+				InviteCode: "ABC234", History: []uint64{1, 2, 3}, SerializedGame: 42, Visibility: api.PUBLIC}}
+	})
+
+	AfterEach(func() {
+		receiver.server.Close()
+	})
+
+	Context("HandleGameEvent method", func() {
+		It("Should queue the event as JSON without posting it", func() {
+			err := dispatcher.HandleGameEvent(event)
+			Expect(err).To(BeNil())
+			Expect(receiver.received()).To(BeIdenticalTo(0))
+			Expect(len(dataStoreSpy.AddWebhookDeliveryDeliveries)).To(BeIdenticalTo(1))
+
+			delivery := dataStoreSpy.AddWebhookDeliveryDeliveries[0]
+			Expect(delivery.SubscriptionID).To(BeIdenticalTo("subscription id"))
+			Expect(delivery.URL).To(BeIdenticalTo(receiver.server.URL))
+			Expect(delivery.Event).To(BeIdenticalTo("moveMade"))
+			Expect(delivery.NextAttemptAt).To(Equal(event.Occurred))
+
+			payload := &api.WebhookPayload{}
+			Expect(json.Unmarshal(delivery.Payload, payload)).To(Succeed())
+			Expect(payload.Event).To(BeIdenticalTo("moveMade"))
+			Expect(payload.GameID).To(BeIdenticalTo("game id"))
+			Expect(payload.UserID).To(BeIdenticalTo("TestUserId"))
+			Expect(payload.Game.Turns).To(BeIdenticalTo(3))
+			Expect(payload.Game.PlayerOneID).To(BeIdenticalTo("player one"))
+			Expect(payload.Game.SerializedGame).To(BeIdenticalTo(uint64(42)))
+		})
+
+		It("Should not tell receivers who plays a game that is not public or what the position is", func() {
+			event.Game.Visibility = api.PLAYERS_ONLY
+			dispatcher.HandleGameEvent(event)
+
+			payload := &api.WebhookPayload{}
+			Expect(json.Unmarshal(dataStoreSpy.AddWebhookDeliveryDeliveries[0].Payload, payload)).To(Succeed())
+			Expect(payload.GameID).To(BeIdenticalTo("game id"))
+			Expect(payload.UserID).To(BeEmpty())
+			Expect(payload.Game.PlayerOneID).To(BeEmpty())
+			Expect(payload.Game.PlayerTwoID).To(BeEmpty())
+			Expect(payload.Game.SerializedGame).To(BeZero())
+			Expect(payload.Game.Turns).To(BeIdenticalTo(3))
+		})
+
+		It("Should not send invite codes or history to the receivers", func() {
+			dispatcher.HandleGameEvent(event)
+			payload := string(dataStoreSpy.AddWebhookDeliveryDeliveries[0].Payload)
+			Expect(payload).ToNot(ContainSubstring("ABC234"))
+			Expect(payload).ToNot(ContainSubstring("History"))
+		})
+
+		It("Should only queue the event types a subscription asks for", func() {
+			dataStoreSpy.WebhookSubscriptionsReturn[0].EventTypes = []api.GameEventType{api.GAME_FINISHED}
+			dispatcher.HandleGameEvent(event)
+			Expect(dataStoreSpy.AddWebhookDeliveryDeliveries).To(BeEmpty())
+
+			event.Type = api.GAME_FINISHED
+			dispatcher.HandleGameEvent(event)
+			Expect(len(dataStoreSpy.AddWebhookDeliveryDeliveries)).To(BeIdenticalTo(1))
+		})
+
+		It("Should queue the event for every subscription even if one of them fails", func() {
+			dataStoreSpy.WebhookSubscriptionsReturn = append(dataStoreSpy.WebhookSubscriptionsReturn,
+				&api.WebhookSubscription{SubscriptionID: "other subscription id", URL: receiver.server.URL})
+			dataStoreSpy.AddWebhookDeliveryErr = errors.New("error adding delivery")
+			err := dispatcher.HandleGameEvent(event)
+			Expect(err).ToNot(BeNil())
+			Expect(len(dataStoreSpy.AddWebhookDeliveryDeliveries)).To(BeIdenticalTo(2))
+		})
+
+		It("Should return an error if the subscriptions could not be read", func() {
+			dataStoreSpy.WebhookSubscriptionsErr = errors.New("error getting subscriptions")
+			err := dispatcher.HandleGameEvent(event)
+			Expect(err).ToNot(BeNil())
+		})
+	})
+
+	Context("DeliverPending method", func() {
+		var delivery *api.WebhookDelivery
+
+		BeforeEach(func() {
+			dispatcher.HandleGameEvent(event)
+			delivery = dataStoreSpy.AddWebhookDeliveryDeliveries[0]
+			delivery.DeliveryID = "delivery id"
+			dataStoreSpy.DueWebhookDeliveriesReturn = []*api.WebhookDelivery{delivery}
+		})
+
+		It("Should post the deliveries that are due and take them out of the queue", func() {
+			delivered, err := dispatcher.DeliverPending()
+			Expect(err).To(BeNil())
+			Expect(delivered).To(BeIdenticalTo(1))
+			Expect(dataStoreSpy.DueWebhookDeliveriesCount).To(BeIdenticalTo(api.WEBHOOK_DELIVERY_BATCH_SIZE))
+			Expect(dataStoreSpy.DueWebhookDeliveriesNow).To(BeTemporally("~", time.Now(), time.Second))
+			Expect(receiver.received()).To(BeIdenticalTo(1))
+			Expect(receiver.requests[0].Header.Get(api.WEBHOOK_EVENT_HEADER_KEY)).To(BeIdenticalTo("moveMade"))
+			Expect(receiver.bodies[0]).To(Equal(delivery.Payload))
+			Expect(dataStoreSpy.RemoveWebhookDeliveryDeliveryIDs).To(Equal([]string{"delivery id"}))
+		})
+
+		It("Should sign the payload with the subscription secret", func() {
+			dispatcher.DeliverPending()
+			signature := receiver.requests[0].Header.Get(api.WEBHOOK_SIGNATURE_HEADER_KEY)
+			Expect(signature).To(BeIdenticalTo(api.SignWebhookPayload(secret, receiver.bodies[0])))
+			Expect(signature).ToNot(BeIdenticalTo(api.SignWebhookPayload("other secret", receiver.bodies[0])))
+		})
+
+		It("Should try again later, waiting longer every time, if the receiver fails", func() {
+			receiver.statusCode = http.StatusInternalServerError
+			delivered, err := dispatcher.DeliverPending()
+			Expect(err).To(BeNil())
+			Expect(delivered).To(BeIdenticalTo(0))
+			Expect(dataStoreSpy.RemoveWebhookDeliveryDeliveryIDs).To(BeEmpty())
+			Expect(dataStoreSpy.UpdateWebhookDeliveryDeliveries).To(Equal([]*api.WebhookDelivery{delivery}))
+			Expect(delivery.Attempts).To(BeIdenticalTo(1))
+			Expect(delivery.LastError).To(ContainSubstring("500"))
+			Expect(delivery.NextAttemptAt).To(BeTemporally("~", time.Now().Add(backoff), time.Second))
+
+			dispatcher.DeliverPending()
+			Expect(delivery.Attempts).To(BeIdenticalTo(2))
+			Expect(delivery.NextAttemptAt).To(BeTemporally("~", time.Now().Add(2*backoff), time.Second))
+		})
+
+		It("Should try again later if the receiver cannot be reached", func() {
+			receiver.server.Close()
+			dispatcher.DeliverPending()
+			Expect(delivery.Attempts).To(BeIdenticalTo(1))
+			Expect(dataStoreSpy.UpdateWebhookDeliveryDeliveries).ToNot(BeEmpty())
+		})
+
+		Context("Given the delivery has used up its attempts", func() {
+			BeforeEach(func() {
+				receiver.statusCode = http.StatusInternalServerError
+				delivery.Attempts = 2
+			})
+
+			It("Should give up and record a dead letter", func() {
+				dispatcher.DeliverPending()
+				letter := dataStoreSpy.AddDeadLetterLetter
+				Expect(letter.SubscriptionID).To(BeIdenticalTo("subscription id"))
+				Expect(letter.Attempts).To(BeIdenticalTo(3))
+				Expect(letter.Payload).To(Equal(delivery.Payload))
+				Expect(letter.LastError).To(ContainSubstring("500"))
+				Expect(dataStoreSpy.RemoveWebhookDeliveryDeliveryIDs).To(Equal([]string{"delivery id"}))
+			})
+
+			It("Should leave it in the queue and carry on if the dead letter could not be recorded", func() {
+				dataStoreSpy.AddDeadLetterErr = errors.New("error adding dead letter")
+				other := &api.WebhookDelivery{DeliveryID: "other delivery id", URL: receiver.server.URL}
+				dataStoreSpy.DueWebhookDeliveriesReturn = append(dataStoreSpy.DueWebhookDeliveriesReturn, other)
+				_, err := dispatcher.DeliverPending()
+				Expect(err).To(BeNil())
+				Expect(receiver.received()).To(BeIdenticalTo(2))
+				Expect(dataStoreSpy.RemoveWebhookDeliveryDeliveryIDs).To(BeEmpty())
+			})
+		})
+
+		It("Should return an error if the queue could not be read", func() {
+			dataStoreSpy.DueWebhookDeliveriesErr = errors.New("error getting deliveries")
+			_, err := dispatcher.DeliverPending()
+			Expect(err).ToNot(BeNil())
+		})
+	})
+})